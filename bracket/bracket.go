@@ -14,11 +14,17 @@ import (
 
 type Bracket struct {
 	FinalsMatchup *Matchup
-	matchupMap    map[matchupKey]*Matchup
+	RoundRobin    *RoundRobin
+	// Name of the hand-drawn SVG arrangement for the bracket's format, or empty if it is to be laid out automatically.
+	Layout     string
+	matchupMap map[matchupKey]*Matchup
 }
 
 const ElimMatchSpacingSec = 600
 
+// Series lengths that a playoff round can be configured to use.
+var BestOfOptions = []int{1, 3, 5}
+
 // Creates an unpopulated bracket with a format that is defined by the given matchup templates and number of alliances.
 func newBracket(matchupTemplates []matchupTemplate, finalsMatchupKey matchupKey, numAlliances int) (*Bracket, error) {
 	// Create a map of matchup templates by key for easy lookup while creating the bracket.
@@ -29,7 +35,7 @@ func newBracket(matchupTemplates []matchupTemplate, finalsMatchupKey matchupKey,
 
 	// Recursively build the bracket, starting with the finals matchup.
 	matchupMap := make(map[matchupKey]*Matchup)
-	finalsMatchup, _, err := createMatchupGraph(finalsMatchupKey, true, matchupTemplateMap, numAlliances, matchupMap)
+	finalsMatchup, _, _, err := createMatchupGraph(finalsMatchupKey, true, matchupTemplateMap, numAlliances, matchupMap)
 	if err != nil {
 		return nil, err
 	}
@@ -37,25 +43,46 @@ func newBracket(matchupTemplates []matchupTemplate, finalsMatchupKey matchupKey,
 	return &Bracket{FinalsMatchup: finalsMatchup, matchupMap: matchupMap}, nil
 }
 
-// Recursive helper method to create the current matchup node and all of its children.
+// Recursive helper method to create the current matchup node and all of its children. Returns the matchup along with
+// whether it is its winner that advances, or if this matchup doesn't need to be played, either the ID of the alliance
+// that has a bye through it or the earlier matchup whose winner or loser takes its place.
 func createMatchupGraph(
 	matchupKey matchupKey,
 	useWinner bool,
 	matchupTemplateMap map[matchupKey]matchupTemplate,
 	numAlliances int,
 	matchupMap map[matchupKey]*Matchup,
-) (*Matchup, int, error) {
+) (*Matchup, bool, int, error) {
 	matchupTemplate, ok := matchupTemplateMap[matchupKey]
 	if !ok {
-		return nil, 0, fmt.Errorf("could not find template for matchup %+v in the list of templates", matchupKey)
+		return nil, false, 0, fmt.Errorf("could not find template for matchup %+v in the list of templates", matchupKey)
 	}
 
 	redAllianceIdFromSelection := matchupTemplate.redAllianceSource.allianceId
 	blueAllianceIdFromSelection := matchupTemplate.blueAllianceSource.allianceId
-	if redAllianceIdFromSelection > 0 || blueAllianceIdFromSelection > 0 {
-		// This is a leaf node in the matchup graph; the alliances will come from the alliance selection.
-		if redAllianceIdFromSelection == 0 || blueAllianceIdFromSelection == 0 {
-			return nil, 0, fmt.Errorf("both alliances must be populated either from selection or a lower round")
+	redRoundRobinRank := matchupTemplate.redAllianceSource.roundRobinRank
+	blueRoundRobinRank := matchupTemplate.blueAllianceSource.roundRobinRank
+	if redAllianceIdFromSelection > 0 || blueAllianceIdFromSelection > 0 || redRoundRobinRank > 0 ||
+		blueRoundRobinRank > 0 {
+		// This is a leaf node in the matchup graph; the alliances will come from the alliance selection or the round
+		// robin standings.
+		if redAllianceIdFromSelection == 0 && redRoundRobinRank == 0 ||
+			blueAllianceIdFromSelection == 0 && blueRoundRobinRank == 0 {
+			return nil, false, 0, fmt.Errorf("both alliances must be populated either from selection or a lower round")
+		}
+		if redRoundRobinRank > 0 || blueRoundRobinRank > 0 {
+			// The round robin always ranks every alliance, so this matchup is played regardless of the number of
+			// alliances; its alliances are filled in once the round robin is complete.
+			matchup, ok := matchupMap[matchupKey]
+			if !ok {
+				matchup = &Matchup{
+					matchupTemplate: matchupTemplate,
+					RedAllianceId:   redAllianceIdFromSelection,
+					BlueAllianceId:  blueAllianceIdFromSelection,
+				}
+				matchupMap[matchupKey] = matchup
+			}
+			return matchup, useWinner, 0, nil
 		}
 
 		// Zero out alliance IDs that don't exist at this tournament to signal that this matchup doesn't need to be
@@ -78,30 +105,30 @@ func createMatchupGraph(
 				}
 				matchupMap[matchupKey] = matchup
 			}
-			return matchup, 0, nil
+			return matchup, useWinner, 0, nil
 		}
 		if redAllianceIdFromSelection == 0 && blueAllianceIdFromSelection == 0 {
 			// This matchup should be pruned from the bracket since neither alliance has a valid source; this tournament
 			// is too small for this matchup to be played.
-			return nil, 0, nil
+			return nil, false, 0, nil
 		}
 		if useWinner {
 			if redAllianceIdFromSelection > 0 {
 				// The red alliance has a bye.
-				return nil, redAllianceIdFromSelection, nil
+				return nil, false, redAllianceIdFromSelection, nil
 			} else {
 				// The blue alliance has a bye.
-				return nil, blueAllianceIdFromSelection, nil
+				return nil, false, blueAllianceIdFromSelection, nil
 			}
 		} else {
 			// There is no losing alliance to return; prune this matchup.
-			return nil, 0, nil
+			return nil, false, 0, nil
 		}
 	}
 
 	// Recurse to determine the lower-round red and blue matchups that will feed into this one, or the alliances that
 	// have a bye to this round.
-	redAllianceSourceMatchup, redByeAllianceId, err := createMatchupGraph(
+	redAllianceSourceMatchup, redUseWinner, redByeAllianceId, err := createMatchupGraph(
 		matchupTemplate.redAllianceSource.matchupKey,
 		matchupTemplate.redAllianceSource.useWinner,
 		matchupTemplateMap,
@@ -109,9 +136,9 @@ func createMatchupGraph(
 		matchupMap,
 	)
 	if err != nil {
-		return nil, 0, err
+		return nil, false, 0, err
 	}
-	blueAllianceSourceMatchup, blueByeAllianceId, err := createMatchupGraph(
+	blueAllianceSourceMatchup, blueUseWinner, blueByeAllianceId, err := createMatchupGraph(
 		matchupTemplate.blueAllianceSource.matchupKey,
 		matchupTemplate.blueAllianceSource.useWinner,
 		matchupTemplateMap,
@@ -119,32 +146,34 @@ func createMatchupGraph(
 		matchupMap,
 	)
 	if err != nil {
-		return nil, 0, err
+		return nil, false, 0, err
 	}
 
-	if redAllianceSourceMatchup == nil && redByeAllianceId == 0 &&
-		blueAllianceSourceMatchup == nil && blueByeAllianceId == 0 {
+	redIsEmpty := redAllianceSourceMatchup == nil && redByeAllianceId == 0
+	blueIsEmpty := blueAllianceSourceMatchup == nil && blueByeAllianceId == 0
+	if redIsEmpty && blueIsEmpty {
 		// This matchup should be pruned from the bracket since neither alliance has a valid source; this tournament is
 		// too small for this matchup to be played.
-		return nil, 0, nil
+		return nil, false, 0, nil
 	}
-	if redByeAllianceId > 0 && blueAllianceSourceMatchup == nil && blueByeAllianceId == 0 {
-		if useWinner {
-			// The red alliance has a bye.
-			return nil, redByeAllianceId, nil
-		} else {
+	if blueIsEmpty || redIsEmpty {
+		if !useWinner {
 			// There is no losing alliance to return; prune this matchup.
-			return nil, 0, nil
+			return nil, false, 0, nil
 		}
-	}
-	if blueByeAllianceId > 0 && redAllianceSourceMatchup == nil && redByeAllianceId == 0 {
-		if useWinner {
+		if redByeAllianceId > 0 {
+			// The red alliance has a bye.
+			return nil, false, redByeAllianceId, nil
+		}
+		if blueByeAllianceId > 0 {
 			// The blue alliance has a bye.
-			return nil, blueByeAllianceId, nil
-		} else {
-			// There is no losing alliance to return; prune this matchup.
-			return nil, 0, nil
+			return nil, false, blueByeAllianceId, nil
+		}
+		// The alliance coming from the only populated lower-round matchup advances directly past this one.
+		if blueIsEmpty {
+			return redAllianceSourceMatchup, redUseWinner, 0, nil
 		}
+		return blueAllianceSourceMatchup, blueUseWinner, 0, nil
 	}
 
 	// This is a real matchup that will be played out.
@@ -157,9 +186,30 @@ func createMatchupGraph(
 			redAllianceSourceMatchup:  redAllianceSourceMatchup,
 			blueAllianceSourceMatchup: blueAllianceSourceMatchup,
 		}
+		// Point the sources at the matchups that actually feed this one, in case any were skipped over.
+		if redAllianceSourceMatchup != nil {
+			matchup.redAllianceSource = allianceSource{
+				matchupKey: redAllianceSourceMatchup.matchupKey, useWinner: redUseWinner,
+			}
+		}
+		if blueAllianceSourceMatchup != nil {
+			matchup.blueAllianceSource = allianceSource{
+				matchupKey: blueAllianceSourceMatchup.matchupKey, useWinner: blueUseWinner,
+			}
+		}
 		matchupMap[matchupKey] = matchup
 	}
-	return matchup, 0, nil
+	return matchup, useWinner, 0, nil
+}
+
+// Overrides the length of the series played in each of the given rounds, keyed by round, with the best-of values that
+// the bracket format would otherwise use. Round-robin matchups are unaffected.
+func (bracket *Bracket) SetRoundBestOf(bestOfByRound map[int]int) {
+	for _, matchup := range bracket.matchupMap {
+		if bestOf, ok := bestOfByRound[matchup.Round]; ok && !matchup.isRoundRobin {
+			matchup.NumWinsToAdvance = (bestOf + 1) / 2
+		}
+	}
 }
 
 // Returns the winning alliance ID of the entire bracket, or 0 if it is not yet known.
@@ -204,6 +254,21 @@ func (bracket *Bracket) GetMatchup(round, group int) (*Matchup, error) {
 // Traverses the bracket to update the state of each matchup based on match results, counting wins and creating or
 // deleting matches as required.
 func (bracket *Bracket) Update(database *model.Database, startTime *time.Time) error {
+	if bracket.RoundRobin != nil {
+		// The round robin is played first and determines the alliances in the matchups that draw from its standings.
+		if err := bracket.RoundRobin.update(database); err != nil {
+			return err
+		}
+		for _, matchup := range bracket.matchupMap {
+			if rank := matchup.redAllianceSource.roundRobinRank; rank > 0 {
+				matchup.RedAllianceId = bracket.RoundRobin.rankedAllianceId(rank)
+			}
+			if rank := matchup.blueAllianceSource.roundRobinRank; rank > 0 {
+				matchup.BlueAllianceId = bracket.RoundRobin.rankedAllianceId(rank)
+			}
+		}
+	}
+
 	if err := bracket.FinalsMatchup.update(database); err != nil {
 		return err
 	}
@@ -0,0 +1,462 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Model and loading logic for playoff bracket formats that are defined in JSON files rather than in code.
+
+package bracket
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const bracketsDir = "brackets"
+
+// Display name that identifies the finals matchup of a bracket.
+const finalsDisplayName = "F"
+
+// Upper limit on the number of round-robin matches per alliance, to keep the generated schedule reasonable.
+const maxRoundRobinMatchesPerAlliance = 20
+
+var bracketDefinitionIdPattern = regexp.MustCompile("^[A-Za-z0-9_-]+$")
+
+// Layouts for which the bracket SVG has a hand-drawn arrangement; any other bracket is laid out automatically.
+var bracketLayouts = map[string]struct{}{"": {}, "single": {}, "double": {}}
+
+// Comp levels that The Blue Alliance accepts for playoff matches.
+var tbaCompLevels = map[string]struct{}{"ef": {}, "qf": {}, "sf": {}, "f": {}}
+
+// Identifies an earlier matchup whose winner or loser populates an alliance.
+type MatchupReference struct {
+	Round int `json:"round"`
+	Group int `json:"group"`
+}
+
+// Conveys where an alliance in a matchup comes from. Exactly one of the fields is set: the alliance ID for alliances
+// coming directly from alliance selection, the rank for alliances coming from the round robin standings, or a reference
+// to the matchup whose winner or loser advances to this one.
+type AllianceSourceDefinition struct {
+	AllianceId     int               `json:"allianceId,omitempty"`
+	RoundRobinRank int               `json:"roundRobinRank,omitempty"`
+	WinnerOf       *MatchupReference `json:"winnerOf,omitempty"`
+	LoserOf        *MatchupReference `json:"loserOf,omitempty"`
+}
+
+// Describes a round robin played among all of the alliances before the rest of the bracket. Its matches are generated
+// automatically in round 1, and the alliances are ranked by either their average score or their average ranking points
+// per match.
+type RoundRobinDefinition struct {
+	MatchesPerAlliance int    `json:"matchesPerAlliance"`
+	RankBy             string `json:"rankBy"`
+}
+
+// Describes a single matchup within a bracket definition. The TBA fields are optional and override the key under which
+// the matchup's matches are published.
+type MatchupDefinition struct {
+	Round              int                      `json:"round"`
+	Group              int                      `json:"group"`
+	DisplayName        string                   `json:"displayName"`
+	NumWinsToAdvance   int                      `json:"numWinsToAdvance"`
+	RedAllianceSource  AllianceSourceDefinition `json:"redAllianceSource"`
+	BlueAllianceSource AllianceSourceDefinition `json:"blueAllianceSource"`
+	TbaCompLevel       string                   `json:"tbaCompLevel,omitempty"`
+	TbaSetNumber       int                      `json:"tbaSetNumber,omitempty"`
+}
+
+// Describes a complete playoff bracket format. The ID is taken from the name of the file that the definition was loaded
+// from, and the finals matchup is the one having the display name "F".
+type BracketDefinition struct {
+	Id           string                `json:"-"`
+	Name         string                `json:"name"`
+	MinAlliances int                   `json:"minAlliances"`
+	MaxAlliances int                   `json:"maxAlliances"`
+	Layout       string                `json:"layout"`
+	RoundRobin   *RoundRobinDefinition `json:"roundRobin,omitempty"`
+	Matchups     []MatchupDefinition   `json:"matchups"`
+}
+
+// A round of a bracket definition's elimination matchups, along with the length of the series played in it by default.
+type PlayoffRound struct {
+	Round         int
+	Name          string
+	DefaultBestOf int
+}
+
+// Loads all of the bracket definitions in the brackets directory, sorted by ID.
+func GetBracketDefinitions() ([]BracketDefinition, error) {
+	paths, err := filepath.Glob(filepath.Join(model.BaseDir, bracketsDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	definitions := make([]BracketDefinition, 0, len(paths))
+	for _, path := range paths {
+		definition, err := GetBracketDefinition(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, *definition)
+	}
+	return definitions, nil
+}
+
+// Loads the bracket definition having the given ID from the brackets directory.
+func GetBracketDefinition(id string) (*BracketDefinition, error) {
+	if !bracketDefinitionIdPattern.MatchString(id) {
+		return nil, fmt.Errorf("invalid playoff type: %v", id)
+	}
+	data, err := os.ReadFile(filepath.Join(model.BaseDir, bracketsDir, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("invalid playoff type: %v", id)
+		}
+		return nil, err
+	}
+	return ParseBracketDefinition(id, data)
+}
+
+// Parses and validates a bracket definition from the given JSON document.
+func ParseBracketDefinition(id string, data []byte) (*BracketDefinition, error) {
+	var definition BracketDefinition
+	if err := json.Unmarshal(data, &definition); err != nil {
+		return nil, fmt.Errorf("could not parse bracket definition %q: %v", id, err)
+	}
+	definition.Id = id
+	if err := definition.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bracket definition %q: %v", id, err)
+	}
+	return &definition, nil
+}
+
+// Returns an error if the bracket definition is missing required fields or doesn't form a playable bracket for every
+// supported number of alliances.
+func (definition *BracketDefinition) Validate() error {
+	if definition.Name == "" {
+		return fmt.Errorf("bracket definition must have a name")
+	}
+	if definition.MinAlliances < 2 || definition.MaxAlliances < definition.MinAlliances {
+		return fmt.Errorf("alliance range %d-%d is invalid", definition.MinAlliances, definition.MaxAlliances)
+	}
+	if _, ok := bracketLayouts[definition.Layout]; !ok {
+		return fmt.Errorf("layout %q is invalid", definition.Layout)
+	}
+	if definition.RoundRobin != nil {
+		if err := definition.RoundRobin.Validate(); err != nil {
+			return err
+		}
+	}
+
+	matchupKeys := make(map[matchupKey]struct{}, len(definition.Matchups))
+	displayNames := make(map[string]struct{}, len(definition.Matchups))
+	for _, matchup := range definition.Matchups {
+		key := newMatchupKey(matchup.Round, matchup.Group)
+		if matchup.Round < 1 || matchup.Group < 1 {
+			return fmt.Errorf("matchup %+v must have a positive round and group", key)
+		}
+		if definition.RoundRobin != nil && matchup.Round <= RoundRobinRound {
+			return fmt.Errorf("matchup %+v must come after the round robin in round %d", key, RoundRobinRound)
+		}
+		if _, ok := matchupKeys[key]; ok {
+			return fmt.Errorf("matchup %+v is defined more than once", key)
+		}
+		matchupKeys[key] = struct{}{}
+		if matchup.DisplayName == "" {
+			return fmt.Errorf("matchup %+v must have a display name", key)
+		}
+		if _, ok := displayNames[matchup.DisplayName]; ok {
+			return fmt.Errorf("display name %q is used more than once", matchup.DisplayName)
+		}
+		if _, err := strconv.Atoi(matchup.DisplayName); err == nil && definition.RoundRobin != nil {
+			return fmt.Errorf("display name %q is reserved for round-robin matches", matchup.DisplayName)
+		}
+		displayNames[matchup.DisplayName] = struct{}{}
+		if matchup.NumWinsToAdvance < 1 {
+			return fmt.Errorf("matchup %q must require at least one win to advance", matchup.DisplayName)
+		}
+		if _, ok := tbaCompLevels[matchup.TbaCompLevel]; matchup.TbaCompLevel != "" && !ok {
+			return fmt.Errorf("matchup %q has invalid TBA comp level %q", matchup.DisplayName, matchup.TbaCompLevel)
+		}
+		if (matchup.TbaCompLevel == "") != (matchup.TbaSetNumber == 0) || matchup.TbaSetNumber < 0 {
+			return fmt.Errorf("matchup %q must have both a TBA comp level and set number or neither", matchup.DisplayName)
+		}
+	}
+	if _, ok := displayNames[finalsDisplayName]; !ok {
+		return fmt.Errorf("bracket must have a matchup with display name %q", finalsDisplayName)
+	}
+
+	for _, matchup := range definition.Matchups {
+		for _, source := range []AllianceSourceDefinition{matchup.RedAllianceSource, matchup.BlueAllianceSource} {
+			if err := definition.validateAllianceSource(&matchup, &source, matchupKeys); err != nil {
+				return err
+			}
+		}
+		if (matchup.RedAllianceSource.RoundRobinRank > 0) != (matchup.BlueAllianceSource.RoundRobinRank > 0) {
+			return fmt.Errorf(
+				"matchup %q must take both alliances from the round robin standings or neither", matchup.DisplayName,
+			)
+		}
+		if (matchup.RedAllianceSource.AllianceId > 0) != (matchup.BlueAllianceSource.AllianceId > 0) {
+			// Byes are expressed instead by pairing an alliance against one that may not exist at the event.
+			return fmt.Errorf(
+				"matchup %q must take both alliances from selection or both from earlier matchups", matchup.DisplayName,
+			)
+		}
+	}
+
+	if definition.RoundRobin == nil {
+		// Every alliance plays in a round robin, but otherwise each must be placed somewhere in the bracket.
+		seededAlliances := make(map[int]struct{})
+		for _, matchup := range definition.Matchups {
+			seededAlliances[matchup.RedAllianceSource.AllianceId] = struct{}{}
+			seededAlliances[matchup.BlueAllianceSource.AllianceId] = struct{}{}
+		}
+		for allianceId := 1; allianceId <= definition.MaxAlliances; allianceId++ {
+			if _, ok := seededAlliances[allianceId]; !ok {
+				return fmt.Errorf("alliance %d is never placed in the bracket", allianceId)
+			}
+		}
+	}
+
+	// Build the bracket for every supported number of alliances to catch sources that don't connect up.
+	for numAlliances := definition.MinAlliances; numAlliances <= definition.MaxAlliances; numAlliances++ {
+		bracket, err := definition.newBracket(numAlliances)
+		if err != nil {
+			return fmt.Errorf("bracket is invalid for %d alliances: %v", numAlliances, err)
+		}
+		if bracket.FinalsMatchup == nil || !bracket.FinalsMatchup.isFinal() {
+			return fmt.Errorf("bracket is invalid for %d alliances: the finals would not be played", numAlliances)
+		}
+	}
+	return nil
+}
+
+// Returns an error if the round robin has an unsupported number of matches per alliance or ranking criterion.
+func (roundRobin *RoundRobinDefinition) Validate() error {
+	if roundRobin.MatchesPerAlliance < 1 || roundRobin.MatchesPerAlliance > maxRoundRobinMatchesPerAlliance {
+		return fmt.Errorf("round robin must have between 1 and %d matches per alliance", maxRoundRobinMatchesPerAlliance)
+	}
+	if roundRobin.RankBy != RoundRobinRankByAverageScore && roundRobin.RankBy != RoundRobinRankByRankingPoints {
+		return fmt.Errorf("round robin ranking criterion %q is invalid", roundRobin.RankBy)
+	}
+	return nil
+}
+
+// Overrides the number of matches each alliance plays in the round robin and the criterion by which the alliances are
+// ranked, where a zero value keeps the definition's own. Does nothing if the bracket has no round robin.
+func (definition *BracketDefinition) SetRoundRobin(matchesPerAlliance int, rankBy string) error {
+	if definition.RoundRobin == nil {
+		return nil
+	}
+	roundRobin := definition.RoundRobin.Override(matchesPerAlliance, rankBy)
+	if err := roundRobin.Validate(); err != nil {
+		return err
+	}
+	definition.RoundRobin = &roundRobin
+	return nil
+}
+
+// Returns a copy of the round robin with the given number of matches per alliance and ranking criterion, where a zero
+// value keeps the existing one.
+func (roundRobin *RoundRobinDefinition) Override(matchesPerAlliance int, rankBy string) RoundRobinDefinition {
+	overridden := *roundRobin
+	if matchesPerAlliance != 0 {
+		overridden.MatchesPerAlliance = matchesPerAlliance
+	}
+	if rankBy != "" {
+		overridden.RankBy = rankBy
+	}
+	return overridden
+}
+
+// Returns the rounds of the bracket in order of play, excluding any round robin since its matches are always played
+// once each.
+func (definition *BracketDefinition) Rounds() []PlayoffRound {
+	roundMap := make(map[int]*PlayoffRound)
+	for _, matchup := range definition.Matchups {
+		round, ok := roundMap[matchup.Round]
+		if !ok {
+			round = &PlayoffRound{Round: matchup.Round, Name: "Finals", DefaultBestOf: 2*matchup.NumWinsToAdvance - 1}
+			roundMap[matchup.Round] = round
+		}
+		if matchup.DisplayName != finalsDisplayName {
+			round.Name = fmt.Sprintf("Round %d", matchup.Round)
+		}
+	}
+	rounds := make([]PlayoffRound, 0, len(roundMap))
+	for _, round := range roundMap {
+		rounds = append(rounds, *round)
+	}
+	sort.Slice(rounds, func(i, j int) bool {
+		return rounds[i].Round < rounds[j].Round
+	})
+	return rounds
+}
+
+// Returns the definition of the matchup having the given round and group, or nil if there isn't one. Round-robin
+// matchups are generated rather than defined, so a definition is synthesized for them.
+func (definition *BracketDefinition) GetMatchup(round, group int) *MatchupDefinition {
+	if definition.RoundRobin != nil && round == RoundRobinRound && group > 0 {
+		return &MatchupDefinition{Round: round, Group: group, DisplayName: strconv.Itoa(group), NumWinsToAdvance: 1}
+	}
+	for i := range definition.Matchups {
+		if definition.Matchups[i].Round == round && definition.Matchups[i].Group == group {
+			return &definition.Matchups[i]
+		}
+	}
+	return nil
+}
+
+// Returns the TBA comp level and set number under which the given matchup's matches should be published. Matchups that
+// don't specify them are numbered in order of play as semifinals, with the finals matchup as the only final.
+// Round-robin matches are published as quarterfinals, as TBA did for the 2015 format.
+func (definition *BracketDefinition) TbaMatchKey(matchup *MatchupDefinition) (string, int) {
+	if matchup.TbaCompLevel != "" {
+		return matchup.TbaCompLevel, matchup.TbaSetNumber
+	}
+	if definition.RoundRobin != nil && matchup.Round == RoundRobinRound {
+		return "qf", matchup.Group
+	}
+	if matchup.DisplayName == finalsDisplayName {
+		return "f", 1
+	}
+	setNumber := 1
+	for _, otherMatchup := range definition.Matchups {
+		if otherMatchup.DisplayName != finalsDisplayName && (otherMatchup.Round < matchup.Round ||
+			otherMatchup.Round == matchup.Round && otherMatchup.Group < matchup.Group) {
+			setNumber++
+		}
+	}
+	return "sf", setNumber
+}
+
+// Creates an unpopulated bracket of this format containing only the required matchups for the given number of
+// alliances.
+func (definition *BracketDefinition) NewBracket(numAlliances int) (*Bracket, error) {
+	if definition.MinAlliances == definition.MaxAlliances && numAlliances != definition.MinAlliances {
+		return nil, fmt.Errorf("Must have exactly %d alliances", definition.MinAlliances)
+	}
+	if numAlliances < definition.MinAlliances {
+		return nil, fmt.Errorf("Must have at least %d alliances", definition.MinAlliances)
+	}
+	if numAlliances > definition.MaxAlliances {
+		return nil, fmt.Errorf("Must have at most %d alliances", definition.MaxAlliances)
+	}
+	return definition.newBracket(numAlliances)
+}
+
+// Creates an unpopulated bracket using the format loaded from the bracket definition having the given ID.
+func NewBracketFromDefinition(id string, numAlliances int) (*Bracket, error) {
+	definition, err := GetBracketDefinition(id)
+	if err != nil {
+		return nil, err
+	}
+	return definition.NewBracket(numAlliances)
+}
+
+// Builds the bracket for the given number of alliances without checking it against the supported range.
+func (definition *BracketDefinition) newBracket(numAlliances int) (*Bracket, error) {
+	bracket, err := newBracket(definition.matchupTemplates(), definition.finalsMatchupKey(), numAlliances)
+	if err != nil {
+		return nil, err
+	}
+	bracket.Layout = definition.Layout
+	if definition.RoundRobin != nil {
+		bracket.RoundRobin = newRoundRobin(
+			numAlliances, definition.RoundRobin.MatchesPerAlliance, definition.RoundRobin.RankBy,
+		)
+		for _, matchup := range bracket.RoundRobin.matchups {
+			bracket.matchupMap[matchup.matchupKey] = matchup
+		}
+	}
+	return bracket, nil
+}
+
+// Returns an error if the given alliance source doesn't set exactly one of its fields or refers to something that
+// doesn't exist.
+func (definition *BracketDefinition) validateAllianceSource(
+	matchup *MatchupDefinition, source *AllianceSourceDefinition, matchupKeys map[matchupKey]struct{},
+) error {
+	numSet := 0
+	var reference *MatchupReference
+	if source.AllianceId != 0 {
+		numSet++
+		if source.AllianceId < 1 {
+			return fmt.Errorf("matchup %q refers to invalid alliance %d", matchup.DisplayName, source.AllianceId)
+		}
+	}
+	if source.RoundRobinRank != 0 {
+		numSet++
+		if definition.RoundRobin == nil {
+			return fmt.Errorf("matchup %q refers to a round robin rank without a round robin", matchup.DisplayName)
+		}
+		if source.RoundRobinRank < 1 || source.RoundRobinRank > definition.MinAlliances {
+			return fmt.Errorf("matchup %q refers to invalid round robin rank %d", matchup.DisplayName,
+				source.RoundRobinRank)
+		}
+	}
+	if source.WinnerOf != nil {
+		numSet++
+		reference = source.WinnerOf
+	}
+	if source.LoserOf != nil {
+		numSet++
+		reference = source.LoserOf
+	}
+	if numSet != 1 {
+		return fmt.Errorf(
+			"each alliance in matchup %q must have exactly one of allianceId, roundRobinRank, winnerOf or loserOf",
+			matchup.DisplayName,
+		)
+	}
+	if reference != nil {
+		if _, ok := matchupKeys[newMatchupKey(reference.Round, reference.Group)]; !ok {
+			return fmt.Errorf("matchup %q refers to nonexistent matchup %+v", matchup.DisplayName, *reference)
+		}
+		if reference.Round >= matchup.Round {
+			return fmt.Errorf("matchup %q must only refer to matchups in earlier rounds", matchup.DisplayName)
+		}
+	}
+	return nil
+}
+
+// Returns the key of the finals matchup.
+func (definition *BracketDefinition) finalsMatchupKey() matchupKey {
+	for _, matchup := range definition.Matchups {
+		if matchup.DisplayName == finalsDisplayName {
+			return newMatchupKey(matchup.Round, matchup.Group)
+		}
+	}
+	return matchupKey{}
+}
+
+// Converts the matchup definitions into the templates from which a bracket is constructed.
+func (definition *BracketDefinition) matchupTemplates() []matchupTemplate {
+	matchupTemplates := make([]matchupTemplate, len(definition.Matchups))
+	for i, matchup := range definition.Matchups {
+		matchupTemplates[i] = matchupTemplate{
+			matchupKey:         newMatchupKey(matchup.Round, matchup.Group),
+			displayName:        matchup.DisplayName,
+			NumWinsToAdvance:   matchup.NumWinsToAdvance,
+			redAllianceSource:  matchup.RedAllianceSource.toAllianceSource(),
+			blueAllianceSource: matchup.BlueAllianceSource.toAllianceSource(),
+		}
+	}
+	return matchupTemplates
+}
+
+// Converts the alliance source definition into the form used when constructing a bracket.
+func (source *AllianceSourceDefinition) toAllianceSource() allianceSource {
+	if source.WinnerOf != nil {
+		return newWinnerAllianceSource(source.WinnerOf.Round, source.WinnerOf.Group)
+	}
+	if source.LoserOf != nil {
+		return newLoserAllianceSource(source.LoserOf.Round, source.LoserOf.Group)
+	}
+	return allianceSource{allianceId: source.AllianceId, roundRobinRank: source.RoundRobinRank}
+}
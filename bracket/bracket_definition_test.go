@@ -0,0 +1,237 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package bracket
+
+import (
+	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/FRCTeam1987/crimson-arena/tournament"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const ladderBracketDefinition = `{
+  "name": "Ladder",
+  "minAlliances": 2,
+  "maxAlliances": 3,
+  "matchups": [
+    {"round": 1, "group": 1, "displayName": "Bye", "numWinsToAdvance": 1,
+     "redAllianceSource": {"allianceId": 1}, "blueAllianceSource": {"allianceId": 4}},
+    {"round": 1, "group": 2, "displayName": "Play-In", "numWinsToAdvance": 1,
+     "redAllianceSource": {"allianceId": 2}, "blueAllianceSource": {"allianceId": 3}},
+    {"round": 2, "group": 1, "displayName": "F", "numWinsToAdvance": 2,
+     "redAllianceSource": {"winnerOf": {"round": 1, "group": 1}},
+     "blueAllianceSource": {"winnerOf": {"round": 1, "group": 2}}, "tbaCompLevel": "f", "tbaSetNumber": 1}
+  ]
+}`
+
+func TestGetBracketDefinitions(t *testing.T) {
+	model.BaseDir = ".."
+	definitions, err := GetBracketDefinitions()
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(definitions)) {
+		assert.Equal(t, "double", definitions[0].Id)
+		assert.Equal(t, "Double-Elimination", definitions[0].Name)
+		assert.Equal(t, 4, definitions[0].MinAlliances)
+		assert.Equal(t, 8, definitions[0].MaxAlliances)
+		assert.Equal(t, 14, len(definitions[0].Matchups))
+		assert.Equal(t, "round_robin", definitions[1].Id)
+		assert.Equal(t, 3, definitions[1].RoundRobin.MatchesPerAlliance)
+		assert.Equal(t, "single", definitions[2].Id)
+		assert.Equal(t, 2, definitions[2].MinAlliances)
+		assert.Equal(t, 16, definitions[2].MaxAlliances)
+		assert.Equal(t, 15, len(definitions[2].Matchups))
+	}
+
+	_, err = GetBracketDefinition("triple")
+	assert.EqualError(t, err, "invalid playoff type: triple")
+	_, err = GetBracketDefinition("../game_definitions/generic")
+	assert.EqualError(t, err, "invalid playoff type: ../game_definitions/generic")
+}
+
+func TestCustomBracketDefinition(t *testing.T) {
+	database := setupTestDb(t)
+
+	definition, err := ParseBracketDefinition("ladder", []byte(ladderBracketDefinition))
+	assert.Nil(t, err)
+	_, err = definition.NewBracket(4)
+	assert.EqualError(t, err, "Must have at most 3 alliances")
+
+	// With two alliances the play-in is skipped.
+	bracket, err := definition.NewBracket(2)
+	assert.Nil(t, err)
+	tournament.CreateTestAlliances(database, 2)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, err := database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(matches)) {
+		assertMatch(t, matches[0], "F-1", 1, 2)
+		assertMatch(t, matches[1], "F-2", 1, 2)
+	}
+
+	database = setupTestDb(t)
+	bracket, err = definition.NewBracket(3)
+	assert.Nil(t, err)
+	tournament.CreateTestAlliances(database, 3)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, _ = database.GetMatchesByType("elimination")
+	if assert.Equal(t, 1, len(matches)) {
+		assertMatch(t, matches[0], "Play-In", 2, 3)
+	}
+	scoreMatch(database, "Play-In", game.BlueWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, _ = database.GetMatchesByType("elimination")
+	if assert.Equal(t, 3, len(matches)) {
+		assertMatch(t, matches[1], "F-1", 1, 3)
+	}
+	finals, _ := bracket.GetMatchup(2, 1)
+	assert.Equal(t, "Finals", finals.LongDisplayName())
+	source, useWinner := finals.BlueAllianceSourceMatchup()
+	assert.Equal(t, "Play-In", source.LongDisplayName())
+	assert.True(t, useWinner)
+	source, _ = finals.RedAllianceSourceMatchup()
+	assert.Nil(t, source)
+	assert.Equal(t, 1, finals.RedAllianceId)
+}
+
+func TestBracketDefinitionValidation(t *testing.T) {
+	assertInvalid := func(data, expectedError string) {
+		_, err := ParseBracketDefinition("test", []byte(data))
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), expectedError)
+		}
+	}
+	matchup := func(round, group int, displayName, red, blue string) string {
+		return fmt.Sprintf(
+			`{"round": %d, "group": %d, "displayName": "%s", "numWinsToAdvance": 1, "redAllianceSource": %s, `+
+				`"blueAllianceSource": %s}`,
+			round, group, displayName, red, blue,
+		)
+	}
+	definition := func(matchups ...string) string {
+		return `{"name": "Test", "minAlliances": 2, "maxAlliances": 4, "matchups": [` + strings.Join(matchups, ", ") + "]}"
+	}
+	a1, a2, a3, a4 := `{"allianceId": 1}`, `{"allianceId": 2}`, `{"allianceId": 3}`, `{"allianceId": 4}`
+	semis := []string{matchup(1, 1, "SF1", a1, a4), matchup(1, 2, "SF2", a2, a3)}
+	winner1, winner2 := `{"winnerOf": {"round": 1, "group": 1}}`, `{"winnerOf": {"round": 1, "group": 2}}`
+
+	_, err := ParseBracketDefinition("test", []byte(definition(append(semis, matchup(2, 1, "F", winner1, winner2))...)))
+	assert.Nil(t, err)
+
+	assertInvalid(`{"name": "Test"`, "could not parse bracket definition")
+	assertInvalid(`{"minAlliances": 2, "maxAlliances": 4}`, "must have a name")
+	assertInvalid(`{"name": "Test", "minAlliances": 4, "maxAlliances": 2}`, "alliance range 4-2 is invalid")
+	assertInvalid(
+		`{"name": "Test", "minAlliances": 2, "maxAlliances": 2, "layout": "triangle"}`, `layout "triangle" is invalid`,
+	)
+	assertInvalid(definition(semis...), `must have a matchup with display name "F"`)
+	assertInvalid(definition(append(semis, matchup(1, 2, "F", winner1, winner2))...), "defined more than once")
+	assertInvalid(definition(append(semis, matchup(2, 1, "SF1", winner1, winner2))...), "used more than once")
+	assertInvalid(
+		definition(append(semis, matchup(2, 1, "F", winner1, `{"winnerOf": {"round": 1, "group": 3}}`))...),
+		"refers to nonexistent matchup",
+	)
+	assertInvalid(
+		definition(append(semis, matchup(2, 1, "F", winner1, `{"winnerOf": {"round": 2, "group": 1}}`))...),
+		"must only refer to matchups in earlier rounds",
+	)
+	assertInvalid(definition(append(semis, matchup(2, 1, "F", winner1, `{}`))...), "exactly one of")
+	assertInvalid(definition(append(semis, matchup(2, 1, "F", winner1, `{"allianceId": -1}`))...), "invalid alliance -1")
+	assertInvalid(
+		definition(append(semis, matchup(2, 1, "F", winner1, a1))...), "both alliances from selection or both from",
+	)
+	assertInvalid(
+		definition(matchup(1, 1, "SF1", a1, a4), matchup(2, 1, "F", winner1, winner2)),
+		"refers to nonexistent matchup",
+	)
+	assertInvalid(
+		definition(matchup(1, 1, "SF1", a1, a4), matchup(1, 2, "SF2", a3, a4), matchup(2, 1, "F", winner1, winner2)),
+		"alliance 2 is never placed in the bracket",
+	)
+	assertInvalid(
+		definition(matchup(1, 1, "SF1", a3, a4), matchup(1, 2, "SF2", a1, a2), matchup(2, 1, "F", winner1, winner1)),
+		"bracket is invalid for 2 alliances: the finals would not be played",
+	)
+	assertInvalid(
+		`{"name": "Test", "minAlliances": 2, "maxAlliances": 2, "matchups": [{"round": 1, "group": 1, `+
+			`"displayName": "F", "numWinsToAdvance": 0, "redAllianceSource": {"allianceId": 1}, `+
+			`"blueAllianceSource": {"allianceId": 2}}]}`,
+		"at least one win",
+	)
+	assertInvalid(
+		`{"name": "Test", "minAlliances": 2, "maxAlliances": 2, "matchups": [{"round": 1, "group": 1, `+
+			`"displayName": "F", "numWinsToAdvance": 1, "redAllianceSource": {"allianceId": 1}, `+
+			`"blueAllianceSource": {"allianceId": 2}, "tbaCompLevel": "gf", "tbaSetNumber": 1}]}`,
+		`invalid TBA comp level "gf"`,
+	)
+}
+
+func TestBracketDefinitionSetRoundRobin(t *testing.T) {
+	model.BaseDir = ".."
+	definition, err := GetBracketDefinition("round_robin")
+	assert.Nil(t, err)
+	assert.Nil(t, definition.SetRoundRobin(0, RoundRobinRankByRankingPoints))
+	assert.Equal(t, RoundRobinDefinition{3, RoundRobinRankByRankingPoints}, *definition.RoundRobin)
+	assert.Nil(t, definition.SetRoundRobin(5, ""))
+	assert.Equal(t, RoundRobinDefinition{5, RoundRobinRankByRankingPoints}, *definition.RoundRobin)
+	bracket, err := definition.NewBracket(4)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, len(bracket.RoundRobin.Matchups()))
+	assert.Equal(t, RoundRobinRankByRankingPoints, bracket.RoundRobin.RankBy)
+
+	assert.EqualError(t, definition.SetRoundRobin(21, ""), "round robin must have between 1 and 20 matches per alliance")
+	assert.EqualError(t, definition.SetRoundRobin(0, "wins"), `round robin ranking criterion "wins" is invalid`)
+	assert.Equal(t, RoundRobinDefinition{5, RoundRobinRankByRankingPoints}, *definition.RoundRobin)
+
+	// Brackets without a round robin are unaffected.
+	definition, err = GetBracketDefinition("single")
+	assert.Nil(t, err)
+	assert.Nil(t, definition.SetRoundRobin(5, RoundRobinRankByRankingPoints))
+	assert.Nil(t, definition.RoundRobin)
+}
+
+func TestBracketDefinitionRounds(t *testing.T) {
+	model.BaseDir = ".."
+	definition, err := GetBracketDefinition("single")
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		[]PlayoffRound{{1, "Round 1", 3}, {2, "Round 2", 3}, {3, "Round 3", 3}, {4, "Finals", 3}},
+		definition.Rounds(),
+	)
+
+	definition, err = GetBracketDefinition("double")
+	assert.Nil(t, err)
+	rounds := definition.Rounds()
+	if assert.Equal(t, 6, len(rounds)) {
+		assert.Equal(t, PlayoffRound{5, "Round 5", 1}, rounds[4])
+		assert.Equal(t, PlayoffRound{6, "Finals", 3}, rounds[5])
+	}
+
+	// The round robin isn't configurable, leaving only the finals.
+	definition, err = GetBracketDefinition("round_robin")
+	assert.Nil(t, err)
+	assert.Equal(t, []PlayoffRound{{2, "Finals", 3}}, definition.Rounds())
+}
+
+func TestBracketDefinitionTbaMatchKey(t *testing.T) {
+	model.BaseDir = ".."
+	definition, err := GetBracketDefinition("double")
+	assert.Nil(t, err)
+	compLevel, setNumber := definition.TbaMatchKey(definition.GetMatchup(3, 2))
+	assert.Equal(t, "qf", compLevel)
+	assert.Equal(t, 4, setNumber)
+	assert.Nil(t, definition.GetMatchup(7, 1))
+
+	// Matchups without explicit keys are numbered as semifinals in order of play.
+	definition, err = ParseBracketDefinition("ladder", []byte(ladderBracketDefinition))
+	assert.Nil(t, err)
+	compLevel, setNumber = definition.TbaMatchKey(definition.GetMatchup(1, 2))
+	assert.Equal(t, "sf", compLevel)
+	assert.Equal(t, 2, setNumber)
+	compLevel, setNumber = definition.TbaMatchKey(definition.GetMatchup(2, 1))
+	assert.Equal(t, "f", compLevel)
+	assert.Equal(t, 1, setNumber)
+}
@@ -231,3 +231,44 @@ func TestBracketLevelOrderTraversal(t *testing.T) {
 	})
 	assert.Equal(t, []string{"F", "13", "11", "12", "9", "10", "5", "6", "7", "8", "1", "2", "3", "4"}, displayNames)
 }
+
+func TestBracketSetRoundBestOf(t *testing.T) {
+	database := setupTestDb(t)
+	tournament.CreateTestAlliances(database, 4)
+
+	bracket, err := NewSingleEliminationBracket(4)
+	assert.Nil(t, err)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, _ := database.GetMatchesByType("elimination")
+	if assert.Equal(t, 4, len(matches)) {
+		assertMatch(t, matches[0], "SF1-1", 1, 4)
+	}
+
+	// Shortening the semifinals renames and removes the unplayed matches, and lengthening the finals applies once the
+	// finals are created.
+	bracket, err = NewSingleEliminationBracket(4)
+	assert.Nil(t, err)
+	bracket.SetRoundBestOf(map[int]int{3: 1, 4: 5})
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, _ = database.GetMatchesByType("elimination")
+	if assert.Equal(t, 2, len(matches)) {
+		assertMatch(t, matches[0], "SF1", 1, 4)
+		assertMatch(t, matches[1], "SF2", 2, 3)
+	}
+	semifinal, _ := bracket.GetMatchup(3, 1)
+	assert.Equal(t, 1, semifinal.BestOf())
+	scoreMatch(database, "SF1", game.RedWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	leader, status := semifinal.StatusText()
+	assert.Equal(t, "red", leader)
+	assert.Equal(t, "Red Advances 1-0", status)
+
+	scoreMatch(database, "SF2", game.BlueWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, _ = database.GetMatchesByType("elimination")
+	if assert.Equal(t, 5, len(matches)) {
+		assertMatch(t, matches[2], "F-1", 1, 3)
+		assertMatch(t, matches[4], "F-3", 1, 3)
+	}
+	assert.Equal(t, 5, bracket.FinalsMatchup.BestOf())
+}
@@ -28,17 +28,135 @@ func TestDoubleEliminationInitial(t *testing.T) {
 }
 
 func TestDoubleEliminationErrors(t *testing.T) {
-	_, err := NewDoubleEliminationBracket(7)
+	_, err := NewDoubleEliminationBracket(3)
 	if assert.NotNil(t, err) {
-		assert.Equal(t, "Must have exactly 8 alliances", err.Error())
+		assert.Equal(t, "Must have at least 4 alliances", err.Error())
 	}
 
 	_, err = NewDoubleEliminationBracket(9)
 	if assert.NotNil(t, err) {
-		assert.Equal(t, "Must have exactly 8 alliances", err.Error())
+		assert.Equal(t, "Must have at most 8 alliances", err.Error())
 	}
 }
 
+func TestDoubleEliminationFourAlliances(t *testing.T) {
+	database := setupTestDb(t)
+
+	tournament.CreateTestAlliances(database, 4)
+	bracket, err := NewDoubleEliminationBracket(4)
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(bracket.GetAllMatchups()))
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, err := database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(matches)) {
+		assertMatch(t, matches[0], "7", 1, 4)
+		assertMatch(t, matches[1], "8", 2, 3)
+	}
+
+	// The losers of the first round meet directly in the lower bracket.
+	matchup12, err := bracket.GetMatchup(4, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "L 8", matchup12.RedAllianceSourceDisplayName())
+	assert.Equal(t, "L 7", matchup12.BlueAllianceSourceDisplayName())
+
+	scoreMatch(database, "7", game.BlueWonMatch)
+	scoreMatch(database, "8", game.RedWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, err = database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(matches)) {
+		assertMatch(t, matches[2], "11", 4, 2)
+		assertMatch(t, matches[3], "12", 3, 1)
+	}
+
+	scoreMatch(database, "11", game.RedWonMatch)
+	scoreMatch(database, "12", game.BlueWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, err = database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 5, len(matches)) {
+		assertMatch(t, matches[4], "13", 2, 1)
+	}
+
+	scoreMatch(database, "13", game.BlueWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, err = database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 7, len(matches)) {
+		assertMatch(t, matches[5], "F-1", 4, 1)
+		assertMatch(t, matches[6], "F-2", 4, 1)
+	}
+
+	scoreMatch(database, "F-1", game.BlueWonMatch)
+	scoreMatch(database, "F-2", game.BlueWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	assert.True(t, bracket.IsComplete())
+	assert.Equal(t, 1, bracket.Winner())
+	assert.Equal(t, 4, bracket.Finalist())
+}
+
+func TestDoubleEliminationSixAlliances(t *testing.T) {
+	database := setupTestDb(t)
+
+	// The top two alliances have a bye through the first round.
+	tournament.CreateTestAlliances(database, 6)
+	bracket, err := NewDoubleEliminationBracket(6)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, len(bracket.GetAllMatchups()))
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, err := database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(matches)) {
+		assertMatch(t, matches[0], "2", 4, 5)
+		assertMatch(t, matches[1], "4", 3, 6)
+	}
+
+	scoreMatch(database, "2", game.RedWonMatch)
+	scoreMatch(database, "4", game.BlueWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, err = database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(matches)) {
+		assertMatch(t, matches[2], "7", 1, 4)
+		assertMatch(t, matches[3], "8", 2, 6)
+	}
+
+	// The first-round losers wait for the losers of the second round.
+	scoreMatch(database, "7", game.RedWonMatch)
+	scoreMatch(database, "8", game.BlueWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, err = database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 7, len(matches)) {
+		assertMatch(t, matches[4], "9", 4, 3)
+		assertMatch(t, matches[5], "10", 2, 5)
+		assertMatch(t, matches[6], "11", 1, 6)
+	}
+
+	scoreMatch(database, "9", game.RedWonMatch)
+	scoreMatch(database, "10", game.RedWonMatch)
+	scoreMatch(database, "11", game.BlueWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	scoreMatch(database, "12", game.BlueWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	matches, err = database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 9, len(matches)) {
+		assertMatch(t, matches[7], "12", 2, 4)
+		assertMatch(t, matches[8], "13", 1, 4)
+	}
+
+	scoreMatch(database, "13", game.RedWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	scoreMatch(database, "F-1", game.RedWonMatch)
+	scoreMatch(database, "F-2", game.RedWonMatch)
+	assert.Nil(t, bracket.Update(database, &dummyStartTime))
+	assert.True(t, bracket.IsComplete())
+	assert.Equal(t, 6, bracket.Winner())
+	assert.Equal(t, 1, bracket.Finalist())
+}
+
 func TestDoubleEliminationProgression(t *testing.T) {
 	database := setupTestDb(t)
 
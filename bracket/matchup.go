@@ -13,12 +13,13 @@ import (
 	"strconv"
 )
 
-// Conveys how a given alliance should be populated -- either directly from alliance selection or based on the results
-// of a prior matchup.
+// Conveys how a given alliance should be populated -- either directly from alliance selection, from its final rank in
+// the round robin, or based on the results of a prior matchup.
 type allianceSource struct {
-	allianceId int
-	matchupKey matchupKey
-	useWinner  bool
+	allianceId     int
+	roundRobinRank int
+	matchupKey     matchupKey
+	useWinner      bool
 }
 
 // Key for uniquely identifying a matchup. Round IDs are arbitrary and in descending order with "1" always representing
@@ -37,6 +38,7 @@ type matchupTemplate struct {
 	NumWinsToAdvance   int
 	redAllianceSource  allianceSource
 	blueAllianceSource allianceSource
+	isRoundRobin       bool
 }
 
 // Encapsulates the format and state of a group of one or more matches between the same two alliances at a given point
@@ -49,6 +51,7 @@ type Matchup struct {
 	BlueAllianceId            int
 	RedAllianceWins           int
 	BlueAllianceWins          int
+	numCompleteMatches        int
 }
 
 // Convenience method to quickly create an alliance source that points to the winner of a different matchup.
@@ -88,8 +91,16 @@ func (matchup *Matchup) LongDisplayName() string {
 	return matchup.displayName
 }
 
-// Returns the display name for the linked matchup from which the red alliance is populated.
+// Returns the maximum number of matches in the series, not counting any replays of tied matches.
+func (matchup *Matchup) BestOf() int {
+	return 2*matchup.NumWinsToAdvance - 1
+}
+
+// Returns the display name for the linked matchup or round robin rank from which the red alliance is populated.
 func (matchup *Matchup) RedAllianceSourceDisplayName() string {
+	if rank := matchup.redAllianceSource.roundRobinRank; rank > 0 {
+		return fmt.Sprintf("RR #%d", rank)
+	}
 	if matchup.redAllianceSourceMatchup == nil {
 		return ""
 	}
@@ -99,8 +110,11 @@ func (matchup *Matchup) RedAllianceSourceDisplayName() string {
 	return "L " + matchup.redAllianceSourceMatchup.displayName
 }
 
-// Returns the display name for the linked matchup from which the blue alliance is populated.
+// Returns the display name for the linked matchup or round robin rank from which the blue alliance is populated.
 func (matchup *Matchup) BlueAllianceSourceDisplayName() string {
+	if rank := matchup.blueAllianceSource.roundRobinRank; rank > 0 {
+		return fmt.Sprintf("RR #%d", rank)
+	}
 	if matchup.blueAllianceSourceMatchup == nil {
 		return ""
 	}
@@ -110,11 +124,23 @@ func (matchup *Matchup) BlueAllianceSourceDisplayName() string {
 	return "L " + matchup.blueAllianceSourceMatchup.displayName
 }
 
+// Returns the linked matchup from which the red alliance is populated and whether it is that matchup's winner that
+// advances, or nil if the red alliance comes directly from alliance selection.
+func (matchup *Matchup) RedAllianceSourceMatchup() (*Matchup, bool) {
+	return matchup.redAllianceSourceMatchup, matchup.redAllianceSource.useWinner
+}
+
+// Returns the linked matchup from which the blue alliance is populated and whether it is that matchup's winner that
+// advances, or nil if the blue alliance comes directly from alliance selection.
+func (matchup *Matchup) BlueAllianceSourceMatchup() (*Matchup, bool) {
+	return matchup.blueAllianceSourceMatchup, matchup.blueAllianceSource.useWinner
+}
+
 // Returns a pair of strings indicating the leading alliance and a readable status of the matchup.
 func (matchup *Matchup) StatusText() (string, string) {
 	var leader, status string
 	winText := "Advances"
-	if matchup.isFinal() {
+	if matchup.isFinal() || matchup.isRoundRobin {
 		winText = "Wins"
 	}
 	if matchup.RedAllianceWins >= matchup.NumWinsToAdvance {
@@ -129,6 +155,8 @@ func (matchup *Matchup) StatusText() (string, string) {
 	} else if matchup.BlueAllianceWins > matchup.RedAllianceWins {
 		leader = "blue"
 		status = fmt.Sprintf("Blue Leads %d-%d", matchup.BlueAllianceWins, matchup.RedAllianceWins)
+	} else if matchup.isRoundRobin && matchup.numCompleteMatches > 0 {
+		status = "Tied"
 	} else if matchup.RedAllianceWins > 0 {
 		status = fmt.Sprintf("Series Tied %d-%d", matchup.RedAllianceWins, matchup.BlueAllianceWins)
 	}
@@ -157,14 +185,23 @@ func (matchup *Matchup) Loser() int {
 	return 0
 }
 
-// Returns true if the matchup has been won, and false if it is still to be determined.
+// Returns true if the matchup has been won, and false if it is still to be determined. A round-robin matchup is
+// complete once its match has been played, even if it was a tie.
 func (matchup *Matchup) IsComplete() bool {
+	if matchup.isRoundRobin {
+		return matchup.numCompleteMatches > 0
+	}
 	return matchup.Winner() > 0
 }
 
+// Returns true if the matchup is a single match within the round robin rather than part of the elimination bracket.
+func (matchup *Matchup) IsRoundRobin() bool {
+	return matchup.isRoundRobin
+}
+
 // Returns true if the matchup represents the final matchup in the bracket.
 func (matchup *Matchup) isFinal() bool {
-	return matchup.displayName == "F"
+	return matchup.displayName == finalsDisplayName
 }
 
 // Recursively traverses the matchup graph to update the state of this matchup and all of its children based on match
@@ -208,6 +245,7 @@ func (matchup *Matchup) update(database *model.Database) error {
 		// Ensure the current state is reset; it may have previously been populated if a match result was edited.
 		matchup.RedAllianceWins = 0
 		matchup.BlueAllianceWins = 0
+		matchup.numCompleteMatches = 0
 
 		// Delete any previously created matches.
 		for _, match := range matches {
@@ -236,11 +274,17 @@ func (matchup *Matchup) update(database *model.Database) error {
 	}
 	matchup.RedAllianceWins = 0
 	matchup.BlueAllianceWins = 0
+	matchup.numCompleteMatches = 0
 	var unplayedMatches []model.Match
 	for _, match := range matches {
 		if !match.IsComplete() {
-			// Update the teams in the match if they are not yet set or are incorrect.
+			// Update the teams in the match if they are not yet set or are incorrect, and the name in case the length
+			// of the series has changed.
 			changed := false
+			if displayName := matchup.matchDisplayName(match.ElimInstance); match.DisplayName != displayName {
+				match.DisplayName = displayName
+				changed = true
+			}
 			if match.Red1 != redAlliance.Lineup[0] || match.Red2 != redAlliance.Lineup[1] ||
 				match.Red3 != redAlliance.Lineup[2] {
 				positionRedTeams(&match, redAlliance)
@@ -267,6 +311,7 @@ func (matchup *Matchup) update(database *model.Database) error {
 		}
 
 		// Check who won.
+		matchup.numCompleteMatches++
 		if match.Status == game.RedWonMatch {
 			matchup.RedAllianceWins++
 		} else if match.Status == game.BlueWonMatch {
@@ -279,6 +324,10 @@ func (matchup *Matchup) update(database *model.Database) error {
 		maxWins = matchup.BlueAllianceWins
 	}
 	numUnplayedMatchesNeeded := matchup.NumWinsToAdvance - maxWins
+	if matchup.isRoundRobin {
+		// A round-robin matchup is a single match whose result stands even if it is a tie.
+		numUnplayedMatchesNeeded = max(1-matchup.numCompleteMatches, 0)
+	}
 	if len(unplayedMatches) > numUnplayedMatchesNeeded {
 		// Delete any superfluous matches off the end of the list.
 		for i := 0; i < len(unplayedMatches)-numUnplayedMatchesNeeded; i++ {
@@ -0,0 +1,212 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Model and logic for a playoff round robin, in which each alliance plays single matches against the others and the
+// standings determine which alliances advance to the rest of the bracket.
+
+package bracket
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"sort"
+	"strconv"
+)
+
+// Criteria by which the alliances in a round robin can be ranked.
+const (
+	RoundRobinRankByAverageScore  = "averageScore"
+	RoundRobinRankByRankingPoints = "rankingPoints"
+)
+
+// Round in which the round-robin matches are played; the matchups fed by its standings come in later rounds.
+const RoundRobinRound = 1
+
+type RoundRobin struct {
+	RankBy   string
+	Rankings []RoundRobinRanking
+	matchups []*Matchup
+}
+
+// The standing of a single alliance in the round robin, accumulated over the matches it has played so far.
+type RoundRobinRanking struct {
+	AllianceId int
+	Rank       int
+	TotalScore int
+	game.RankingFields
+}
+
+// Creates the matchups for a round robin among the given number of alliances in which each alliance plays the given
+// number of matches, or one fewer for a single alliance if the total doesn't divide evenly into matches.
+func newRoundRobin(numAlliances, matchesPerAlliance int, rankBy string) *RoundRobin {
+	roundRobin := RoundRobin{RankBy: rankBy}
+	for i, pairing := range roundRobinPairings(numAlliances, matchesPerAlliance) {
+		group := i + 1
+		roundRobin.matchups = append(roundRobin.matchups, &Matchup{
+			matchupTemplate: matchupTemplate{
+				matchupKey:         newMatchupKey(RoundRobinRound, group),
+				displayName:        strconv.Itoa(group),
+				NumWinsToAdvance:   1,
+				redAllianceSource:  allianceSource{allianceId: pairing[0]},
+				blueAllianceSource: allianceSource{allianceId: pairing[1]},
+				isRoundRobin:       true,
+			},
+			RedAllianceId:  pairing[0],
+			BlueAllianceId: pairing[1],
+		})
+	}
+	return &roundRobin
+}
+
+// Returns the red and blue alliance IDs for each round-robin match in order of play. The pairings are generated using
+// the circle method so that every alliance plays once per round (sitting out one round per cycle if there is an odd
+// number of alliances), and the cycle repeats with the sides swapped until each alliance has played enough matches.
+func roundRobinPairings(numAlliances, matchesPerAlliance int) [][2]int {
+	slots := make([]int, 0, numAlliances+1)
+	for allianceId := 1; allianceId <= numAlliances; allianceId++ {
+		slots = append(slots, allianceId)
+	}
+	if numAlliances%2 == 1 {
+		// Whichever alliance is paired against the empty slot sits out the round.
+		slots = append(slots, 0)
+	}
+	numSlots := len(slots)
+
+	var pairings [][2]int
+	numMatches := make(map[int]int, numAlliances)
+	for cycle := 0; ; cycle++ {
+		numAdded := 0
+		for round := 0; round < numSlots-1; round++ {
+			for i := 0; i < numSlots/2; i++ {
+				red, blue := slots[i], slots[numSlots-1-i]
+				if red == 0 || blue == 0 || numMatches[red] >= matchesPerAlliance ||
+					numMatches[blue] >= matchesPerAlliance {
+					continue
+				}
+				// Alternate the sides of the alliance in the fixed slot every round, and of everyone every cycle.
+				if (i == 0 && round%2 == 1) != (cycle%2 == 1) {
+					red, blue = blue, red
+				}
+				pairings = append(pairings, [2]int{red, blue})
+				numMatches[red]++
+				numMatches[blue]++
+				numAdded++
+			}
+
+			// Keep the first slot fixed and rotate the rest.
+			slots = append([]int{slots[0], slots[numSlots-1]}, slots[1:numSlots-1]...)
+		}
+		if numAdded == 0 {
+			return pairings
+		}
+	}
+}
+
+// Returns the alliance's average score across the round-robin matches it has played.
+func (ranking *RoundRobinRanking) AverageScore() float64 {
+	if ranking.Played == 0 {
+		return 0
+	}
+	return float64(ranking.TotalScore) / float64(ranking.Played)
+}
+
+// Returns the alliance's average number of ranking points per round-robin match played.
+func (ranking *RoundRobinRanking) AverageRankingPoints() float64 {
+	if ranking.Played == 0 {
+		return 0
+	}
+	return float64(ranking.RankingPoints) / float64(ranking.Played)
+}
+
+// Returns the matchups for each of the round-robin matches, in order of play.
+func (roundRobin *RoundRobin) Matchups() []*Matchup {
+	return roundRobin.matchups
+}
+
+// Returns true if every round-robin match has been played and the standings are final.
+func (roundRobin *RoundRobin) IsComplete() bool {
+	for _, matchup := range roundRobin.matchups {
+		if !matchup.IsComplete() {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the ID of the alliance holding the given rank in the final standings, or 0 if the round robin is still in
+// progress.
+func (roundRobin *RoundRobin) rankedAllianceId(rank int) int {
+	if !roundRobin.IsComplete() || rank > len(roundRobin.Rankings) {
+		return 0
+	}
+	return roundRobin.Rankings[rank-1].AllianceId
+}
+
+// Updates each of the round-robin matchups based on match results, creating matches as required, and recalculates the
+// standings.
+func (roundRobin *RoundRobin) update(database *model.Database) error {
+	rankings := make(map[int]*RoundRobinRanking)
+	addResult := func(allianceId int, ownScore, opponentScore *game.ScoreSummary) {
+		ranking := rankings[allianceId]
+		ranking.AddScoreSummary(ownScore, opponentScore, ownScore.PlayoffDq)
+		ranking.TotalScore += ownScore.Score
+	}
+	for _, matchup := range roundRobin.matchups {
+		if err := matchup.update(database); err != nil {
+			return err
+		}
+		for _, allianceId := range []int{matchup.RedAllianceId, matchup.BlueAllianceId} {
+			if _, ok := rankings[allianceId]; !ok {
+				rankings[allianceId] = &RoundRobinRanking{AllianceId: allianceId}
+			}
+		}
+
+		matches, err := database.GetMatchesByElimRoundGroup(matchup.Round, matchup.Group)
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			if !match.IsComplete() {
+				continue
+			}
+			matchResult, err := database.GetMatchResultForMatch(match.Id)
+			if err != nil {
+				return err
+			}
+			if matchResult == nil {
+				continue
+			}
+			redScore, blueScore := matchResult.RedScoreSummary(), matchResult.BlueScoreSummary()
+			addResult(matchup.RedAllianceId, redScore, blueScore)
+			addResult(matchup.BlueAllianceId, blueScore, redScore)
+		}
+	}
+
+	roundRobin.Rankings = make([]RoundRobinRanking, 0, len(rankings))
+	for _, ranking := range rankings {
+		roundRobin.Rankings = append(roundRobin.Rankings, *ranking)
+	}
+	sort.Slice(roundRobin.Rankings, func(i, j int) bool {
+		return roundRobin.rankingLess(&roundRobin.Rankings[i], &roundRobin.Rankings[j])
+	})
+	for i := range roundRobin.Rankings {
+		roundRobin.Rankings[i].Rank = i + 1
+	}
+	return nil
+}
+
+// Returns true if the first ranking should be placed ahead of the second. Alliances are ordered by the chosen
+// criterion, then by the other one, and finally by alliance selection seed.
+func (roundRobin *RoundRobin) rankingLess(a, b *RoundRobinRanking) bool {
+	criteria := []func(*RoundRobinRanking) float64{
+		(*RoundRobinRanking).AverageScore, (*RoundRobinRanking).AverageRankingPoints,
+	}
+	if roundRobin.RankBy == RoundRobinRankByRankingPoints {
+		criteria[0], criteria[1] = criteria[1], criteria[0]
+	}
+	for _, criterion := range criteria {
+		if criterion(a) != criterion(b) {
+			return criterion(a) > criterion(b)
+		}
+	}
+	return a.AllianceId < b.AllianceId
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package bracket
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/FRCTeam1987/crimson-arena/tournament"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRoundRobinPairings(t *testing.T) {
+	// With four alliances and three matches each, every pair of alliances meets exactly once.
+	assert.Equal(t, [][2]int{{1, 4}, {2, 3}, {3, 1}, {4, 2}, {1, 2}, {3, 4}}, roundRobinPairings(4, 3))
+
+	for numAlliances := 2; numAlliances <= 16; numAlliances++ {
+		for matchesPerAlliance := 1; matchesPerAlliance <= 2*numAlliances; matchesPerAlliance++ {
+			numMatches := make(map[int]int)
+			for _, pairing := range roundRobinPairings(numAlliances, matchesPerAlliance) {
+				assert.NotEqual(t, pairing[0], pairing[1])
+				numMatches[pairing[0]]++
+				numMatches[pairing[1]]++
+			}
+			numShort := 0
+			for allianceId := 1; allianceId <= numAlliances; allianceId++ {
+				assert.LessOrEqual(t, numMatches[allianceId], matchesPerAlliance)
+				numShort += matchesPerAlliance - numMatches[allianceId]
+			}
+			// Only an odd total number of alliance appearances should leave one alliance a match short.
+			assert.Equal(t, numAlliances*matchesPerAlliance%2, numShort, "%d alliances", numAlliances)
+		}
+	}
+}
+
+func TestRoundRobinBracket(t *testing.T) {
+	database := setupTestDb(t)
+	model.BaseDir = ".."
+
+	playoffBracket, err := NewBracketFromDefinition("round_robin", 4)
+	assert.Nil(t, err)
+	assert.Equal(t, "", playoffBracket.Layout)
+	tournament.CreateTestAlliances(database, 4)
+	assert.Nil(t, playoffBracket.Update(database, &dummyStartTime))
+	matches, err := database.GetMatchesByType("elimination")
+	assert.Nil(t, err)
+	if assert.Equal(t, 6, len(matches)) {
+		assertMatch(t, matches[0], "1", 1, 4)
+		assertMatch(t, matches[1], "2", 2, 3)
+		assertMatch(t, matches[2], "3", 3, 1)
+		assertMatch(t, matches[3], "4", 4, 2)
+		assertMatch(t, matches[4], "5", 1, 2)
+		assertMatch(t, matches[5], "6", 3, 4)
+	}
+	assert.Equal(t, "RR #1", playoffBracket.FinalsMatchup.RedAllianceSourceDisplayName())
+	assert.Equal(t, "RR #2", playoffBracket.FinalsMatchup.BlueAllianceSourceDisplayName())
+
+	// A tie stands rather than being replayed.
+	scoreRoundRobinMatch(database, "1", 50, 50)
+	scoreRoundRobinMatch(database, "2", 40, 90)
+	scoreRoundRobinMatch(database, "3", 100, 20)
+	scoreRoundRobinMatch(database, "4", 60, 70)
+	scoreRoundRobinMatch(database, "5", 30, 80)
+	assert.Nil(t, playoffBracket.Update(database, &dummyStartTime))
+	matches, _ = database.GetMatchesByType("elimination")
+	assert.Equal(t, 6, len(matches))
+	matchup, _ := playoffBracket.GetMatchup(1, 1)
+	assert.True(t, matchup.IsComplete())
+	assert.True(t, matchup.IsRoundRobin())
+	_, status := matchup.StatusText()
+	assert.Equal(t, "Tied", status)
+	matchup, _ = playoffBracket.GetMatchup(1, 2)
+	_, status = matchup.StatusText()
+	assert.Equal(t, "Blue Wins 1-0", status)
+	assert.False(t, playoffBracket.RoundRobin.IsComplete())
+	assert.Equal(t, 0, playoffBracket.FinalsMatchup.RedAllianceId)
+
+	// The finals are created once the last round-robin match is played.
+	scoreRoundRobinMatch(database, "6", 95, 35)
+	assert.Nil(t, playoffBracket.Update(database, &dummyStartTime))
+	assert.True(t, playoffBracket.RoundRobin.IsComplete())
+	rankings := playoffBracket.RoundRobin.Rankings
+	if assert.Equal(t, 4, len(rankings)) {
+		// Averages: alliance 3 is 95.0, alliance 2 is 63.3, alliance 4 is 48.3 and alliance 1 is 33.3.
+		assert.Equal(t, 3, rankings[0].AllianceId)
+		assert.Equal(t, 1, rankings[0].Rank)
+		assert.Equal(t, 285, rankings[0].TotalScore)
+		assert.Equal(t, 3, rankings[0].Played)
+		assert.Equal(t, 2, rankings[1].AllianceId)
+		assert.Equal(t, 4, rankings[2].AllianceId)
+		assert.Equal(t, 1, rankings[3].AllianceId)
+		assert.Equal(t, 1, rankings[3].Ties)
+	}
+	matches, _ = database.GetMatchesByType("elimination")
+	if assert.Equal(t, 8, len(matches)) {
+		assertMatch(t, matches[6], "F-1", 3, 2)
+		assertMatch(t, matches[7], "F-2", 3, 2)
+	}
+
+	scoreMatch(database, "F-1", game.BlueWonMatch)
+	scoreMatch(database, "F-2", game.BlueWonMatch)
+	assert.Nil(t, playoffBracket.Update(database, &dummyStartTime))
+	assert.True(t, playoffBracket.IsComplete())
+	assert.Equal(t, 2, playoffBracket.Winner())
+	assert.Equal(t, 3, playoffBracket.Finalist())
+}
+
+func TestRoundRobinRankByRankingPoints(t *testing.T) {
+	database := setupTestDb(t)
+
+	definition, err := ParseBracketDefinition("rp", []byte(`{
+	  "name": "Round-Robin by RP", "minAlliances": 3, "maxAlliances": 3,
+	  "roundRobin": {"matchesPerAlliance": 2, "rankBy": "rankingPoints"},
+	  "matchups": [{"round": 2, "group": 1, "displayName": "F", "numWinsToAdvance": 1,
+	    "redAllianceSource": {"roundRobinRank": 1}, "blueAllianceSource": {"roundRobinRank": 2}}]
+	}`))
+	assert.Nil(t, err)
+	playoffBracket, err := definition.NewBracket(3)
+	assert.Nil(t, err)
+	tournament.CreateTestAlliances(database, 3)
+	assert.Nil(t, playoffBracket.Update(database, &dummyStartTime))
+	matches, _ := database.GetMatchesByType("elimination")
+	if assert.Equal(t, 3, len(matches)) {
+		assertMatch(t, matches[0], "1", 2, 3)
+		assertMatch(t, matches[1], "2", 3, 1)
+		assertMatch(t, matches[2], "3", 1, 2)
+	}
+
+	// Alliance 1 has the highest average score but only one win.
+	scoreRoundRobinMatch(database, "1", 20, 30)
+	scoreRoundRobinMatch(database, "2", 40, 35)
+	scoreRoundRobinMatch(database, "3", 150, 25)
+	assert.Nil(t, playoffBracket.Update(database, &dummyStartTime))
+	rankings := playoffBracket.RoundRobin.Rankings
+	if assert.Equal(t, 3, len(rankings)) {
+		assert.Equal(t, 3, rankings[0].AllianceId)
+		assert.Equal(t, 4, rankings[0].RankingPoints)
+		assert.Equal(t, 1, rankings[1].AllianceId)
+		assert.Equal(t, 2, rankings[2].AllianceId)
+	}
+	assert.Equal(t, 3, playoffBracket.FinalsMatchup.RedAllianceId)
+	assert.Equal(t, 1, playoffBracket.FinalsMatchup.BlueAllianceId)
+}
+
+func TestRoundRobinBracketDefinitionValidation(t *testing.T) {
+	assertInvalid := func(roundRobin, finals string, expectedError string) {
+		data := `{"name": "Test", "minAlliances": 3, "maxAlliances": 6, ` + roundRobin + `"matchups": [` + finals + `]}`
+		_, err := ParseBracketDefinition("test", []byte(data))
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), expectedError)
+		}
+	}
+	roundRobin := `"roundRobin": {"matchesPerAlliance": 3, "rankBy": "averageScore"}, `
+	finals := `{"round": 2, "group": 1, "displayName": "F", "numWinsToAdvance": 2, ` +
+		`"redAllianceSource": {"roundRobinRank": 1}, "blueAllianceSource": {"roundRobinRank": 2}}`
+
+	assertInvalid(`"roundRobin": {"matchesPerAlliance": 0, "rankBy": "averageScore"}, `, finals, "between 1 and 20")
+	assertInvalid(
+		`"roundRobin": {"matchesPerAlliance": 3, "rankBy": "wins"}, `, finals, `ranking criterion "wins" is invalid`,
+	)
+	assertInvalid("", finals, "refers to a round robin rank without a round robin")
+	assertInvalid(
+		roundRobin,
+		`{"round": 1, "group": 1, "displayName": "F", "numWinsToAdvance": 2, `+
+			`"redAllianceSource": {"roundRobinRank": 1}, "blueAllianceSource": {"roundRobinRank": 2}}`,
+		"must come after the round robin",
+	)
+	assertInvalid(
+		roundRobin,
+		`{"round": 2, "group": 1, "displayName": "F", "numWinsToAdvance": 2, `+
+			`"redAllianceSource": {"roundRobinRank": 1}, "blueAllianceSource": {"roundRobinRank": 4}}`,
+		"invalid round robin rank 4",
+	)
+	assertInvalid(
+		roundRobin,
+		`{"round": 2, "group": 1, "displayName": "F", "numWinsToAdvance": 2, `+
+			`"redAllianceSource": {"roundRobinRank": 1}, "blueAllianceSource": {"allianceId": 2}}`,
+		"from the round robin standings or neither",
+	)
+	assertInvalid(
+		roundRobin,
+		`{"round": 2, "group": 1, "displayName": "1", "numWinsToAdvance": 1, `+
+			`"redAllianceSource": {"roundRobinRank": 1}, "blueAllianceSource": {"roundRobinRank": 2}}, `+finals,
+		`display name "1" is reserved for round-robin matches`,
+	)
+}
+
+func TestRoundRobinTbaMatchKey(t *testing.T) {
+	model.BaseDir = ".."
+	definition, err := GetBracketDefinition("round_robin")
+	assert.Nil(t, err)
+	matchup := definition.GetMatchup(1, 5)
+	if assert.NotNil(t, matchup) {
+		assert.Equal(t, "5", matchup.DisplayName)
+		compLevel, setNumber := definition.TbaMatchKey(matchup)
+		assert.Equal(t, "qf", compLevel)
+		assert.Equal(t, 5, setNumber)
+	}
+	compLevel, setNumber := definition.TbaMatchKey(definition.GetMatchup(2, 1))
+	assert.Equal(t, "f", compLevel)
+	assert.Equal(t, 1, setNumber)
+}
+
+// Records a result for the given round-robin match in which each alliance scores the given number of points.
+func scoreRoundRobinMatch(database *model.Database, displayName string, redPoints, bluePoints int) {
+	match, _ := database.GetMatchByName("elimination", displayName)
+	matchResult := model.MatchResult{
+		MatchId:    match.Id,
+		PlayNumber: 1,
+		MatchType:  "elimination",
+		RedScore:   &game.Score{ElementCounts: map[string]int{"teleop": redPoints}},
+		BlueScore:  &game.Score{ElementCounts: map[string]int{"teleop": bluePoints}},
+	}
+	database.CreateMatchResult(&matchResult)
+	match.Status = game.DetermineMatchStatus(matchResult.RedScoreSummary(), matchResult.BlueScoreSummary())
+	database.UpdateMatch(match)
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Tracking of the turnaround time each playoff alliance has had since its last match.
+
+package field
+
+import (
+	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"math"
+	"time"
+)
+
+// When a playoff alliance last finished a match, and how much any timeouts since then have added to its rest.
+type allianceRest struct {
+	lastPlayedAt time.Time
+	extension    time.Duration
+}
+
+// Starts tracking the rest of any alliance that isn't already tracked from the playoff matches already played,
+// assuming each ran to completion. Alliances already tracked keep their rest, since it reflects timeouts and aborted
+// matches that the database doesn't record.
+func (arena *Arena) loadAllianceRests() error {
+	if arena.allianceRests == nil {
+		arena.allianceRests = make(map[int]*allianceRest)
+	}
+	matches, err := arena.Database.GetMatchesByType("elimination")
+	if err != nil {
+		return err
+	}
+	lastPlayedAt := make(map[int]time.Time)
+	for _, match := range matches {
+		if match.StartedAt.IsZero() {
+			continue
+		}
+		endedAt := match.StartedAt.Add(game.GetDurationToTeleopEnd())
+		for _, allianceId := range []int{match.ElimRedAlliance, match.ElimBlueAlliance} {
+			if allianceId != 0 && endedAt.After(lastPlayedAt[allianceId]) {
+				lastPlayedAt[allianceId] = endedAt
+			}
+		}
+	}
+	for allianceId, endedAt := range lastPlayedAt {
+		if _, ok := arena.allianceRests[allianceId]; !ok {
+			arena.allianceRests[allianceId] = &allianceRest{lastPlayedAt: endedAt}
+		}
+	}
+	return nil
+}
+
+// Restarts the rest of the given alliance as of the given match end time, unless it has played more recently.
+func (arena *Arena) recordAlliancePlayed(allianceId int, endedAt time.Time) {
+	if allianceId == 0 {
+		return
+	}
+	if rest, ok := arena.allianceRests[allianceId]; !ok || endedAt.After(rest.lastPlayedAt) {
+		arena.allianceRests[allianceId] = &allianceRest{lastPlayedAt: endedAt}
+	}
+}
+
+// Restarts the rest of both alliances in the current match if it is a playoff match.
+func (arena *Arena) recordCurrentMatchPlayed() {
+	if arena.CurrentMatch.Type == "elimination" {
+		arena.recordAlliancePlayed(arena.CurrentMatch.ElimRedAlliance, time.Now())
+		arena.recordAlliancePlayed(arena.CurrentMatch.ElimBlueAlliance, time.Now())
+	}
+}
+
+// Lengthens the rest of both alliances in the current match by the given duration if it is a playoff match.
+func (arena *Arena) extendCurrentMatchRests(duration time.Duration) {
+	if arena.CurrentMatch.Type != "elimination" {
+		return
+	}
+	arena.extendAllianceRest(arena.CurrentMatch.ElimRedAlliance, duration)
+	arena.extendAllianceRest(arena.CurrentMatch.ElimBlueAlliance, duration)
+}
+
+// Lengthens the rest of the given alliance by the given duration if it has played a playoff match.
+func (arena *Arena) extendAllianceRest(allianceId int, duration time.Duration) {
+	if rest, ok := arena.allianceRests[allianceId]; ok {
+		rest.extension += duration
+	}
+}
+
+// Returns how much longer the given alliance needs to rest to have had the configured minimum turnaround since its
+// last match, or zero if it has rested enough.
+func (arena *Arena) AllianceRestRemaining(allianceId int) time.Duration {
+	rest, ok := arena.allianceRests[allianceId]
+	if !ok || arena.EventSettings.ElimMinRestSec <= 0 {
+		return 0
+	}
+	restEnd := rest.lastPlayedAt.Add(time.Duration(arena.EventSettings.ElimMinRestSec)*time.Second + rest.extension)
+	return max(time.Until(restEnd), 0)
+}
+
+// Returns an error if either alliance in the current match has not yet had the minimum rest since its last match.
+func (arena *Arena) checkAlliancesRested() error {
+	if arena.CurrentMatch.Type != "elimination" {
+		return nil
+	}
+	for _, allianceId := range []int{arena.CurrentMatch.ElimRedAlliance, arena.CurrentMatch.ElimBlueAlliance} {
+		if remaining := arena.AllianceRestRemaining(allianceId); remaining > 0 {
+			return fmt.Errorf(
+				"alliance %d still has %s of its minimum rest remaining", allianceId, formatRestDuration(remaining),
+			)
+		}
+	}
+	return nil
+}
+
+// Returns the reason to show the operator alongside the match start button; either why the match can't be started,
+// or a warning that it would be starting before the alliances have rested.
+func (arena *Arena) canStartMatchReason() string {
+	if err := arena.checkCanStartMatch(); err != nil {
+		return err.Error()
+	}
+	if arena.MatchState == PreMatch {
+		if err := arena.checkAlliancesRested(); err != nil {
+			return fmt.Sprintf("Warning: %v", err)
+		}
+	}
+	return ""
+}
+
+// Formats the given duration as minutes and seconds, rounding up to the next second.
+func formatRestDuration(duration time.Duration) string {
+	seconds := int(math.Ceil(duration.Seconds()))
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
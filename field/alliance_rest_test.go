@@ -0,0 +1,113 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/FRCTeam1987/crimson-arena/tournament"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAllianceRest(t *testing.T) {
+	arena := setupTestArena(t)
+	for _, station := range arena.AllianceStations {
+		station.Bypass = true
+	}
+	tournament.CreateTestAlliances(arena.Database, 4)
+	arena.EventSettings.ElimMinRestSec = 600
+
+	// The rest is rebuilt from the playoff matches already played, as of when they would have ended.
+	playedMatch := model.Match{Type: "elimination", DisplayName: "SF1-1", ElimRedAlliance: 1, ElimBlueAlliance: 4,
+		StartedAt: time.Now().Add(-game.GetDurationToTeleopEnd() - 2*time.Minute)}
+	arena.Database.CreateMatch(&playedMatch)
+	arena.Database.CreateMatch(&model.Match{Type: "elimination", DisplayName: "SF2-1", ElimRedAlliance: 2,
+		ElimBlueAlliance: 3})
+	assert.Nil(t, arena.loadAllianceRests())
+	assert.InDelta(t, 8*time.Minute, arena.AllianceRestRemaining(1), float64(time.Second))
+	assert.InDelta(t, 8*time.Minute, arena.AllianceRestRemaining(4), float64(time.Second))
+	assert.Equal(t, time.Duration(0), arena.AllianceRestRemaining(2))
+
+	// Starting the next match early only draws a warning unless the rest is enforced.
+	nextMatch := model.Match{Type: "elimination", DisplayName: "SF1-2", ElimRedAlliance: 4, ElimBlueAlliance: 1}
+	arena.Database.CreateMatch(&nextMatch)
+	assert.Nil(t, arena.LoadMatch(&nextMatch))
+	assert.Nil(t, arena.checkCanStartMatch())
+	assert.Contains(t, arena.canStartMatchReason(), "Warning: alliance 4 still has 8:00 of its minimum rest")
+	message := arena.generateAllianceRestMessage().(*AllianceRestMessage)
+	assert.Equal(t, 4, message.RedAllianceId)
+	assert.InDelta(t, 480, message.RedRemainingSec, 1)
+	arena.EventSettings.ElimRestEnforced = true
+	err := arena.StartMatch()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "cannot start match while alliance 4 still has 8:00 of its minimum rest")
+	}
+
+	// A timeout adds to the rest of both alliances in the loaded match.
+	assert.Nil(t, arena.StartTimeout(60))
+	assert.InDelta(t, 9*time.Minute, arena.AllianceRestRemaining(4), float64(time.Second))
+	assert.InDelta(t, 9*time.Minute, arena.AllianceRestRemaining(1), float64(time.Second))
+	arena.MatchState = PreMatch
+
+	// An alliance's own timeout adds only to its rest, even if it isn't in the loaded match.
+	assert.Nil(t, arena.StartAllianceTimeout(2, 120))
+	assert.InDelta(t, 9*time.Minute, arena.AllianceRestRemaining(4), float64(time.Second))
+	assert.InDelta(t, 9*time.Minute, arena.AllianceRestRemaining(1), float64(time.Second))
+	assert.Equal(t, time.Duration(0), arena.AllianceRestRemaining(2))
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.StartAllianceTimeout(4, 60))
+	assert.InDelta(t, 10*time.Minute, arena.AllianceRestRemaining(4), float64(time.Second))
+	assert.InDelta(t, 9*time.Minute, arena.AllianceRestRemaining(1), float64(time.Second))
+	arena.MatchState = PreMatch
+
+	arena.allianceRests[1].lastPlayedAt = time.Now().Add(-13 * time.Minute)
+	arena.allianceRests[4].lastPlayedAt = time.Now().Add(-13 * time.Minute)
+	assert.Equal(t, "", arena.canStartMatchReason())
+	assert.Nil(t, arena.StartMatch())
+	assert.Equal(t, 0, arena.generateAllianceRestMessage().(*AllianceRestMessage).RedAllianceId)
+
+	// Finishing the match restarts the rest of both alliances.
+	arena.Update()
+	arena.MatchStartTime = time.Now().Add(-game.GetDurationToTeleopEnd())
+	for arena.MatchState != PostMatch {
+		arena.Update()
+	}
+	assert.InDelta(t, 10*time.Minute, arena.AllianceRestRemaining(4), float64(time.Second))
+	assert.InDelta(t, 10*time.Minute, arena.AllianceRestRemaining(1), float64(time.Second))
+
+	// Qualification matches and a zero minimum are never held up.
+	arena.MatchState = PreMatch
+	assert.Nil(t, arena.LoadMatch(&model.Match{Type: "qualification", DisplayName: "1"}))
+	assert.Nil(t, arena.checkCanStartMatch())
+	assert.Nil(t, arena.LoadMatch(&nextMatch))
+	arena.EventSettings.ElimMinRestSec = 0
+	assert.Nil(t, arena.checkCanStartMatch())
+}
+
+func TestAllianceRestSurvivesSettingsSave(t *testing.T) {
+	arena := setupTestArena(t)
+	tournament.CreateTestAlliances(arena.Database, 4)
+	arena.EventSettings.NumElimAlliances = 4
+	arena.EventSettings.ElimMinRestSec = 600
+	assert.Nil(t, arena.Database.UpdateEventSettings(arena.EventSettings))
+	assert.Nil(t, arena.LoadSettings())
+
+	playedMatch := model.Match{Type: "elimination", DisplayName: "SF1-1", ElimRedAlliance: 1, ElimBlueAlliance: 4,
+		StartedAt: time.Now().Add(-game.GetDurationToTeleopEnd() - 2*time.Minute)}
+	arena.Database.CreateMatch(&playedMatch)
+	arena.Database.CreateMatch(&model.Match{Type: "elimination", DisplayName: "SF2-1", ElimRedAlliance: 2,
+		ElimBlueAlliance: 3, StartedAt: time.Now().Add(-game.GetDurationToTeleopEnd() - 4*time.Minute)})
+	assert.Nil(t, arena.loadAllianceRests())
+	assert.Nil(t, arena.StartAllianceTimeout(1, 120))
+	arena.MatchState = PreMatch
+
+	// Saving the settings shouldn't lose the timeout, but should still pick up an alliance that wasn't tracked yet.
+	delete(arena.allianceRests, 2)
+	assert.Nil(t, arena.Database.UpdateEventSettings(arena.EventSettings))
+	assert.Nil(t, arena.LoadSettings())
+	assert.InDelta(t, 10*time.Minute, arena.AllianceRestRemaining(1), float64(time.Second))
+	assert.InDelta(t, 8*time.Minute, arena.AllianceRestRemaining(4), float64(time.Second))
+	assert.InDelta(t, 6*time.Minute, arena.AllianceRestRemaining(2), float64(time.Second))
+}
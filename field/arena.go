@@ -13,6 +13,7 @@ import (
 	"github.com/FRCTeam1987/crimson-arena/network"
 	"github.com/FRCTeam1987/crimson-arena/partner"
 	"github.com/FRCTeam1987/crimson-arena/plc"
+	"github.com/FRCTeam1987/crimson-arena/tournament"
 	"log"
 	"reflect"
 	"time"
@@ -65,6 +66,8 @@ type Arena struct {
 	LastMatchTimeSec           float64
 	RedScore                   *game.Score
 	BlueScore                  *game.Score
+	RedCards                   map[string]string
+	BlueCards                  map[string]string
 	lastDsPacketTime           time.Time
 	lastPeriodicTaskTime       time.Time
 	EventStatus                EventStatus
@@ -74,14 +77,17 @@ type Arena struct {
 	SavedMatchResult           *model.MatchResult
 	SavedRankings              game.Rankings
 	AllianceStationDisplayMode string
-	AllianceSelectionAlliances []model.Alliance
+	AllianceSelection          *model.AllianceSelection
 	PlayoffBracket             *bracket.Bracket
+	allianceRests              map[int]*allianceRest
 	LowerThird                 *model.LowerThird
 	ShowLowerThird             bool
 	MuteMatchSounds            bool
 	matchAborted               bool
 	soundsPlayed               map[*game.MatchSound]struct{}
 	preloadedTeams             *[6]*model.Team
+	DriverStationSimulator     *DriverStationSimulator
+	GameDataRevealed           bool
 }
 
 type AllianceStation struct {
@@ -146,6 +152,13 @@ func (arena *Arena) LoadSettings() error {
 	if err != nil {
 		return err
 	}
+	if settings.TiebreakerSeed == 0 {
+		// The settings predate the seed; generate one now and persist it so that it doesn't change again.
+		settings.TiebreakerSeed = model.NewTiebreakerSeed()
+		if err = arena.Database.UpdateEventSettings(settings); err != nil {
+			return err
+		}
+	}
 	arena.EventSettings = settings
 
 	// Initialize the components that depend on settings.
@@ -177,6 +190,8 @@ func (arena *Arena) LoadSettings() error {
 	game.MatchTiming.WarningRemainingDurationSec = settings.WarningRemainingDurationSec
 	game.UpdateMatchSounds()
 	arena.MatchTimingNotifier.Notify()
+	game.CurrentGame = settings.GameDefinition
+	game.CurrentRankingRules = settings.RankingRules
 
 	// Reconstruct the playoff bracket in memory.
 	if err = arena.CreatePlayoffBracket(); err != nil {
@@ -185,22 +200,40 @@ func (arena *Arena) LoadSettings() error {
 	if err = arena.UpdatePlayoffBracket(nil); err != nil {
 		return err
 	}
+	if err = arena.loadAllianceRests(); err != nil {
+		return err
+	}
+	if arena.CurrentMatch != nil {
+		arena.AllianceRestNotifier.Notify()
+	}
+
+	// Pick up any alliance selection that was in progress when the database was last open.
+	if arena.AllianceSelection, err = arena.Database.GetAllianceSelection(); err != nil {
+		return err
+	}
+	arena.AllianceSelectionNotifier.Notify()
 
 	return nil
 }
 
-// Constructs an empty playoff bracket in memory, based only on the number of alliances.
+// Constructs an empty playoff bracket in memory, based only on the playoff format, the number of alliances, the
+// configured round robin and the configured length of each round's series.
 func (arena *Arena) CreatePlayoffBracket() error {
-	var err error
-	switch arena.EventSettings.ElimType {
-	case "single":
-		arena.PlayoffBracket, err = bracket.NewSingleEliminationBracket(arena.EventSettings.NumElimAlliances)
-	case "double":
-		arena.PlayoffBracket, err = bracket.NewDoubleEliminationBracket(arena.EventSettings.NumElimAlliances)
-	default:
-		err = fmt.Errorf("invalid playoff type: %v", arena.EventSettings.ElimType)
+	definition, err := bracket.GetBracketDefinition(arena.EventSettings.ElimType)
+	if err != nil {
+		return err
 	}
-	return err
+	if err = definition.SetRoundRobin(
+		arena.EventSettings.ElimRoundRobinMatchesPerAlliance, arena.EventSettings.ElimRoundRobinRankBy,
+	); err != nil {
+		return err
+	}
+	arena.PlayoffBracket, err = definition.NewBracket(arena.EventSettings.NumElimAlliances)
+	if err != nil {
+		return err
+	}
+	arena.PlayoffBracket.SetRoundBestOf(arena.EventSettings.ElimRoundBestOf)
+	return nil
 }
 
 // Traverses the in-memory playoff bracket to populate alliances, create matches, and assess winners. Does nothing if
@@ -222,6 +255,23 @@ func (arena *Arena) LoadMatch(match *model.Match) error {
 		return fmt.Errorf("cannot load match while there is a match still in progress or with results pending")
 	}
 
+	if match.Type == "elimination" {
+		// Field whichever teams the alliance captains have chosen for this match.
+		if err := arena.Database.ApplySubmittedLineups(match); err != nil {
+			return err
+		}
+	}
+
+	if match.RedGameData == "" && match.BlueGameData == "" {
+		// Pick the messages only once per match, so that reloading it doesn't replace them.
+		arena.chooseGameData(match)
+		if match.Id != 0 && match.RedGameData != "" {
+			if err := arena.Database.UpdateMatch(match); err != nil {
+				return err
+			}
+		}
+	}
+	arena.GameDataRevealed = false
 	arena.CurrentMatch = match
 	err := arena.assignTeam(match.Red1, "R1")
 	if err != nil {
@@ -264,11 +314,16 @@ func (arena *Arena) LoadMatch(match *model.Match) error {
 	arena.soundsPlayed = make(map[*game.MatchSound]struct{})
 	arena.RedScore = new(game.Score)
 	arena.BlueScore = new(game.Score)
+	arena.RedCards = make(map[string]string)
+	arena.BlueCards = make(map[string]string)
 	arena.FieldReset = false
 	arena.Plc.ResetMatch()
 
+	arena.syncDriverStationSimulator()
+
 	// Notify any listeners about the new match.
 	arena.MatchLoadNotifier.Notify()
+	arena.AllianceRestNotifier.Notify()
 	arena.RealtimeScoreNotifier.Notify()
 	arena.AllianceStationDisplayMode = "match"
 	arena.AllianceStationDisplayModeNotifier.Notify()
@@ -327,6 +382,7 @@ func (arena *Arena) SubstituteTeam(teamId int, station string) error {
 		},
 		false,
 	)
+	arena.syncDriverStationSimulator()
 	arena.MatchLoadNotifier.Notify()
 
 	if arena.CurrentMatch.Type != "test" {
@@ -345,6 +401,9 @@ func (arena *Arena) StartMatch() error {
 			arena.Database.UpdateMatch(arena.CurrentMatch)
 		}
 		arena.updateCycleTime(arena.CurrentMatch.StartedAt)
+		if arena.EventSettings.AutoRetimeEnabled {
+			arena.autoRetimeRemainingMatches()
+		}
 
 		// Save the missed packet count to subtract it from the running count.
 		for _, allianceStation := range arena.AllianceStations {
@@ -364,6 +423,7 @@ func (arena *Arena) StartMatch() error {
 		}
 
 		arena.MatchState = StartMatch
+		arena.AllianceRestNotifier.Notify()
 	}
 	return err
 }
@@ -383,6 +443,10 @@ func (arena *Arena) AbortMatch() error {
 	if arena.MatchState != WarmupPeriod {
 		arena.playSound("abort")
 	}
+	if arena.MatchState != StartMatch && arena.MatchState != WarmupPeriod {
+		// The robots have been playing, so the replay comes with a fresh turnaround.
+		arena.recordCurrentMatchPlayed()
+	}
 	arena.MatchState = PostMatch
 	arena.matchAborted = true
 	arena.AudienceDisplayMode = "blank"
@@ -399,6 +463,7 @@ func (arena *Arena) ResetMatch() error {
 	}
 	arena.MatchState = PreMatch
 	arena.matchAborted = false
+	arena.GameDataRevealed = false
 	arena.AllianceStations["R1"].Bypass = false
 	arena.AllianceStations["R2"].Bypass = false
 	arena.AllianceStations["R3"].Bypass = false
@@ -409,8 +474,14 @@ func (arena *Arena) ResetMatch() error {
 	return nil
 }
 
-// Starts a timeout of the given duration.
+// Starts a timeout of the given duration, which adds to the rest of both alliances in the loaded playoff match.
 func (arena *Arena) StartTimeout(durationSec int) error {
+	return arena.startTimeout(durationSec, 0)
+}
+
+// Starts a timeout of the given duration, adding it to the rest of the given alliance that called it or, if there is
+// no such alliance, to that of both alliances in the loaded playoff match.
+func (arena *Arena) startTimeout(durationSec int, allianceId int) error {
 	if arena.MatchState != PreMatch {
 		return fmt.Errorf("cannot start timeout while there is a match still in progress or with results pending")
 	}
@@ -421,6 +492,12 @@ func (arena *Arena) StartTimeout(durationSec int) error {
 	arena.MatchTimingNotifier.Notify()
 	arena.MatchState = TimeoutActive
 	arena.MatchStartTime = time.Now()
+	if allianceId == 0 {
+		arena.extendCurrentMatchRests(time.Duration(durationSec) * time.Second)
+	} else {
+		arena.extendAllianceRest(allianceId, time.Duration(durationSec)*time.Second)
+	}
+	arena.AllianceRestNotifier.Notify()
 	arena.LastMatchTimeSec = -1
 	arena.AllianceStationDisplayMode = "timeout"
 	arena.AllianceStationDisplayModeNotifier.Notify()
@@ -428,6 +505,66 @@ func (arena *Arena) StartTimeout(durationSec int) error {
 	return nil
 }
 
+// Starts a timeout of the given duration on behalf of the given playoff alliance, using up one of its timeouts.
+func (arena *Arena) StartAllianceTimeout(allianceId int, durationSec int) error {
+	alliance, err := arena.Database.GetAllianceById(allianceId)
+	if err != nil {
+		return err
+	}
+	if alliance == nil {
+		return fmt.Errorf("alliance %d does not exist", allianceId)
+	}
+	if alliance.TimeoutsRemaining() == 0 {
+		return fmt.Errorf("alliance %d has no timeouts remaining", allianceId)
+	}
+
+	if err = arena.startTimeout(durationSec, allianceId); err != nil {
+		return err
+	}
+	alliance.TimeoutsUsed++
+	return arena.Database.UpdateAlliance(alliance)
+}
+
+// Brings the next available backup team onto the given playoff alliance in place of the given team, using up one of
+// the alliance's backups. The backup also takes the team's place in the loaded match if the alliance is playing in it,
+// and in the alliance's upcoming matches. Returns the ID of the backup team.
+func (arena *Arena) InvokeBackup(allianceId int, replacedTeamId int) (int, error) {
+	if arena.MatchState != PreMatch {
+		return 0, fmt.Errorf("cannot invoke a backup while there is a match still in progress or with results pending")
+	}
+
+	backupTeamId, err := tournament.InvokeBackup(arena.Database, allianceId, replacedTeamId)
+	if err != nil {
+		return 0, err
+	}
+
+	if arena.CurrentMatch.Type == "elimination" {
+		stationTeamIds := make(map[string]int)
+		if arena.CurrentMatch.ElimRedAlliance == allianceId {
+			stationTeamIds["R1"] = arena.CurrentMatch.Red1
+			stationTeamIds["R2"] = arena.CurrentMatch.Red2
+			stationTeamIds["R3"] = arena.CurrentMatch.Red3
+		}
+		if arena.CurrentMatch.ElimBlueAlliance == allianceId {
+			stationTeamIds["B1"] = arena.CurrentMatch.Blue1
+			stationTeamIds["B2"] = arena.CurrentMatch.Blue2
+			stationTeamIds["B3"] = arena.CurrentMatch.Blue3
+		}
+		for station, teamId := range stationTeamIds {
+			if teamId == replacedTeamId {
+				if err = arena.SubstituteTeam(backupTeamId, station); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+
+	if err = arena.UpdatePlayoffBracket(nil); err != nil {
+		return 0, err
+	}
+	return backupTeamId, nil
+}
+
 // Updates the audience display screen.
 func (arena *Arena) SetAudienceDisplayMode(mode string) {
 	if arena.AudienceDisplayMode != mode {
@@ -528,6 +665,7 @@ func (arena *Arena) Update() {
 		enabled = true
 		if matchTimeSec >= game.GetDurationToTeleopEnd().Seconds() {
 			arena.MatchState = PostMatch
+			arena.recordCurrentMatchPlayed()
 			auto = false
 			enabled = false
 			sendDsPacket = true
@@ -569,6 +707,8 @@ func (arena *Arena) Update() {
 		arena.MatchTimeNotifier.Notify()
 	}
 
+	arena.updateGameData(matchTimeSec)
+
 	// Send a packet if at a period transition point or if it's been long enough since the last one.
 	if sendDsPacket || time.Since(arena.lastDsPacketTime).Seconds()*1000 >= dsPacketPeriodMs {
 		arena.sendDsPacket(auto, enabled)
@@ -605,12 +745,12 @@ func (arena *Arena) Run() {
 
 // Calculates the red alliance score summary for the given realtime snapshot.
 func (arena *Arena) RedScoreSummary() *game.ScoreSummary {
-	return arena.RedScore.Summarize()
+	return arena.RedScore.Summarize(arena.BlueScore)
 }
 
 // Calculates the blue alliance score summary for the given realtime snapshot.
 func (arena *Arena) BlueScoreSummary() *game.ScoreSummary {
-	return arena.BlueScore.Summarize()
+	return arena.BlueScore.Summarize(arena.RedScore)
 }
 
 // Loads a team into an alliance station, cleaning up the previous team there if there is one.
@@ -726,6 +866,54 @@ func (arena *Arena) setupNetwork(teams [6]*model.Team, isPreload bool) {
 	}
 }
 
+// Starts or stops simulating the driver stations of the teams in the current match.
+func (arena *Arena) SetDriverStationSimulatorEnabled(enabled bool) {
+	if enabled && arena.DriverStationSimulator == nil {
+		arena.DriverStationSimulator = NewDriverStationSimulator(network.ServerIpAddress)
+		arena.syncDriverStationSimulator()
+		return
+	}
+	if !enabled && arena.DriverStationSimulator != nil {
+		arena.DriverStationSimulator.SetTeams(nil)
+		arena.DriverStationSimulator = nil
+	}
+	arena.DriverStationSimulatorNotifier.Notify()
+}
+
+// Changes the robot conditions reported by the simulated driver station in the given alliance station.
+func (arena *Arena) SetSimulatedRobot(station string, robot SimulatedRobot) error {
+	if arena.DriverStationSimulator == nil {
+		return fmt.Errorf("driver station simulator is not running")
+	}
+	allianceStation, ok := arena.AllianceStations[station]
+	if !ok {
+		return fmt.Errorf("invalid alliance station '%s'", station)
+	}
+	if allianceStation.Team == nil {
+		return fmt.Errorf("no team is assigned to station %s", station)
+	}
+	if err := arena.DriverStationSimulator.SetRobot(allianceStation.Team.Id, robot); err != nil {
+		return err
+	}
+	arena.DriverStationSimulatorNotifier.Notify()
+	return nil
+}
+
+// Points the driver station simulator, if it is running, at the teams currently assigned to the alliance stations.
+func (arena *Arena) syncDriverStationSimulator() {
+	if arena.DriverStationSimulator == nil {
+		return
+	}
+	var teamIds []int
+	for _, allianceStation := range arena.AllianceStations {
+		if allianceStation.Team != nil {
+			teamIds = append(teamIds, allianceStation.Team.Id)
+		}
+	}
+	arena.DriverStationSimulator.SetTeams(teamIds)
+	arena.DriverStationSimulatorNotifier.Notify()
+}
+
 // Returns nil if the match can be started, and an error otherwise.
 func (arena *Arena) checkCanStartMatch() error {
 	if arena.MatchState != PreMatch {
@@ -742,6 +930,12 @@ func (arena *Arena) checkCanStartMatch() error {
 		return err
 	}
 
+	if arena.EventSettings.ElimRestEnforced {
+		if err = arena.checkAlliancesRested(); err != nil {
+			return fmt.Errorf("cannot start match while %v", err)
+		}
+	}
+
 	if arena.Plc.IsEnabled() {
 		if !arena.Plc.IsHealthy {
 			return fmt.Errorf("cannot start match while PLC is not healthy")
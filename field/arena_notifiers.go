@@ -10,16 +10,21 @@ import (
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/FRCTeam1987/crimson-arena/websocket"
+	"math"
 	"strconv"
+	"time"
 )
 
 type ArenaNotifiers struct {
+	AllianceRestNotifier               *websocket.Notifier
 	AllianceSelectionNotifier          *websocket.Notifier
 	AllianceStationDisplayModeNotifier *websocket.Notifier
 	ArenaStatusNotifier                *websocket.Notifier
 	AudienceDisplayModeNotifier        *websocket.Notifier
 	DisplayConfigurationNotifier       *websocket.Notifier
+	DriverStationSimulatorNotifier     *websocket.Notifier
 	EventStatusNotifier                *websocket.Notifier
+	GameDataNotifier                   *websocket.Notifier
 	LowerThirdNotifier                 *websocket.Notifier
 	MatchLoadNotifier                  *websocket.Notifier
 	MatchTimeNotifier                  *websocket.Notifier
@@ -27,6 +32,7 @@ type ArenaNotifiers struct {
 	PlaySoundNotifier                  *websocket.Notifier
 	RealtimeScoreNotifier              *websocket.Notifier
 	ReloadDisplaysNotifier             *websocket.Notifier
+	ScheduleUpdateNotifier             *websocket.Notifier
 	ScorePostedNotifier                *websocket.Notifier
 	FieldLightsNotifier                *websocket.Notifier
 	SCCNotifier                        *websocket.Notifier
@@ -37,6 +43,35 @@ type MatchTimeMessage struct {
 	MatchTimeSec int
 }
 
+type AllianceRestMessage struct {
+	RedAllianceId    int
+	BlueAllianceId   int
+	RedRemainingSec  int
+	BlueRemainingSec int
+	Enforced         bool
+}
+
+type AllianceSelectionMessage struct {
+	Alliances        []model.Alliance
+	ShowTimer        bool
+	TimeRemainingSec int
+}
+
+type DriverStationSimulatorMessage struct {
+	Enabled  bool
+	Stations map[string]SimulatedStation
+}
+
+type SimulatedStation struct {
+	TeamId int
+	SimulatedRobot
+}
+
+type GameDataMessage struct {
+	RedGameData  string
+	BlueGameData string
+}
+
 type audienceAllianceScoreFields struct {
 	Score        *game.Score
 	ScoreSummary *game.ScoreSummary
@@ -44,6 +79,7 @@ type audienceAllianceScoreFields struct {
 
 // Instantiates notifiers and configures their message producing methods.
 func (arena *Arena) configureNotifiers() {
+	arena.AllianceRestNotifier = websocket.NewNotifier("allianceRest", arena.generateAllianceRestMessage)
 	arena.AllianceSelectionNotifier = websocket.NewNotifier("allianceSelection", arena.generateAllianceSelectionMessage)
 	arena.AllianceStationDisplayModeNotifier = websocket.NewNotifier("allianceStationDisplayMode",
 		arena.generateAllianceStationDisplayModeMessage)
@@ -52,7 +88,10 @@ func (arena *Arena) configureNotifiers() {
 		arena.generateAudienceDisplayModeMessage)
 	arena.DisplayConfigurationNotifier = websocket.NewNotifier("displayConfiguration",
 		arena.generateDisplayConfigurationMessage)
+	arena.DriverStationSimulatorNotifier = websocket.NewNotifier("driverStationSimulator",
+		arena.generateDriverStationSimulatorMessage)
 	arena.EventStatusNotifier = websocket.NewNotifier("eventStatus", arena.generateEventStatusMessage)
+	arena.GameDataNotifier = websocket.NewNotifier("gameData", arena.generateGameDataMessage)
 	arena.LowerThirdNotifier = websocket.NewNotifier("lowerThird", arena.generateLowerThirdMessage)
 	arena.MatchLoadNotifier = websocket.NewNotifier("matchLoad", arena.generateMatchLoadMessage)
 	arena.MatchTimeNotifier = websocket.NewNotifier("matchTime", arena.generateMatchTimeMessage)
@@ -60,13 +99,34 @@ func (arena *Arena) configureNotifiers() {
 	arena.PlaySoundNotifier = websocket.NewNotifier("playSound", nil)
 	arena.RealtimeScoreNotifier = websocket.NewNotifier("realtimeScore", arena.generateRealtimeScoreMessage)
 	arena.ReloadDisplaysNotifier = websocket.NewNotifier("reload", nil)
+	arena.ScheduleUpdateNotifier = websocket.NewNotifier("scheduleUpdate", nil)
 	arena.ScorePostedNotifier = websocket.NewNotifier("scorePosted", arena.generateScorePostedMessage)
 	arena.FieldLightsNotifier = websocket.NewNotifier("fieldLights", arena.generateFieldLightsMessage)
 	arena.SCCNotifier = websocket.NewNotifier("sccstatus", arena.generateSCCStatusMessage)
 }
 
+func (arena *Arena) generateAllianceRestMessage() any {
+	message := AllianceRestMessage{Enforced: arena.EventSettings.ElimRestEnforced}
+	// Once the match is underway the rest shown would be for the match being played rather than the one to come.
+	isBeforeMatch := arena.MatchState == PreMatch || arena.MatchState == TimeoutActive ||
+		arena.MatchState == PostTimeout
+	if arena.CurrentMatch.Type == "elimination" && isBeforeMatch {
+		message.RedAllianceId = arena.CurrentMatch.ElimRedAlliance
+		message.BlueAllianceId = arena.CurrentMatch.ElimBlueAlliance
+		message.RedRemainingSec = int(math.Ceil(arena.AllianceRestRemaining(message.RedAllianceId).Seconds()))
+		message.BlueRemainingSec = int(math.Ceil(arena.AllianceRestRemaining(message.BlueAllianceId).Seconds()))
+	}
+	return &message
+}
+
 func (arena *Arena) generateAllianceSelectionMessage() any {
-	return &arena.AllianceSelectionAlliances
+	message := AllianceSelectionMessage{Alliances: model.WithoutLineupPins(arena.AllianceSelection.Alliances)}
+	if !arena.AllianceSelection.PickDeadline.IsZero() {
+		message.ShowTimer = true
+		message.TimeRemainingSec =
+			max(int(math.Ceil(time.Until(arena.AllianceSelection.PickDeadline).Seconds())), 0)
+	}
+	return &message
 }
 
 func (arena *Arena) generateAllianceStationDisplayModeMessage() any {
@@ -79,6 +139,8 @@ func (arena *Arena) generateArenaStatusMessage() any {
 		AllianceStations map[string]*AllianceStation
 		MatchState
 		CanStartMatch         bool
+		CanStartMatchReason   string
+		GameDataRevealed      bool
 		AccessPointStatus     string
 		SwitchStart           string
 		PlcIsHealthy          bool
@@ -92,6 +154,8 @@ func (arena *Arena) generateArenaStatusMessage() any {
 		arena.AllianceStations,
 		arena.MatchState,
 		arena.checkCanStartMatch() == nil,
+		arena.canStartMatchReason(),
+		arena.GameDataRevealed,
 		arena.accessPoint.Status,
 		arena.networkSwitch.Status,
 		arena.Plc.IsHealthy,
@@ -117,10 +181,32 @@ func (arena *Arena) generateDisplayConfigurationMessage() any {
 	return displaysCopy
 }
 
+func (arena *Arena) generateDriverStationSimulatorMessage() any {
+	message := DriverStationSimulatorMessage{
+		Enabled: arena.DriverStationSimulator != nil, Stations: make(map[string]SimulatedStation),
+	}
+	if arena.DriverStationSimulator != nil {
+		for stationName, allianceStation := range arena.AllianceStations {
+			if allianceStation.Team == nil {
+				continue
+			}
+			if robot, ok := arena.DriverStationSimulator.GetRobot(allianceStation.Team.Id); ok {
+				message.Stations[stationName] = SimulatedStation{allianceStation.Team.Id, robot}
+			}
+		}
+	}
+	return &message
+}
+
 func (arena *Arena) generateEventStatusMessage() any {
 	return arena.EventStatus
 }
 
+// Generates the game-specific messages for the current match, which are secret and only for the scorekeeper.
+func (arena *Arena) generateGameDataMessage() any {
+	return &GameDataMessage{arena.CurrentMatch.RedGameData, arena.CurrentMatch.BlueGameData}
+}
+
 func (arena *Arena) generateLowerThirdMessage() any {
 	return &struct {
 		LowerThird     *model.LowerThird
@@ -158,6 +244,11 @@ func (arena *Arena) generateMatchLoadMessage() any {
 		}
 	}
 
+	// Keep the game-specific messages from the displays, since they are secret until the field reveals them.
+	match := *arena.CurrentMatch
+	match.RedGameData = ""
+	match.BlueGameData = ""
+
 	return &struct {
 		MatchType         string
 		Match             *model.Match
@@ -168,7 +259,7 @@ func (arena *Arena) generateMatchLoadMessage() any {
 		BlueOffFieldTeams []*model.Team
 	}{
 		arena.CurrentMatch.CapitalizedType(),
-		arena.CurrentMatch,
+		&match,
 		teams,
 		rankings,
 		matchup,
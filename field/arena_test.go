@@ -611,6 +611,67 @@ func TestArenaTimeout(t *testing.T) {
 	}
 }
 
+func TestArenaAllianceTimeout(t *testing.T) {
+	arena := setupTestArena(t)
+	tournament.CreateTestAlliances(arena.Database, 2)
+
+	err := arena.StartAllianceTimeout(3, 60)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "alliance 3 does not exist", err.Error())
+	}
+
+	assert.Nil(t, arena.StartAllianceTimeout(2, 60))
+	assert.Equal(t, TimeoutActive, arena.MatchState)
+	assert.Equal(t, 60, game.MatchTiming.TimeoutDurationSec)
+	alliance, _ := arena.Database.GetAllianceById(2)
+	assert.Equal(t, 1, alliance.TimeoutsUsed)
+	assert.Equal(t, 0, alliance.TimeoutsRemaining())
+
+	// A timeout that can't be started right now doesn't use up the alliance's allowance.
+	err = arena.StartAllianceTimeout(1, 60)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "cannot start timeout")
+	}
+	alliance, _ = arena.Database.GetAllianceById(1)
+	assert.Equal(t, 0, alliance.TimeoutsUsed)
+
+	arena.MatchState = PreMatch
+	err = arena.StartAllianceTimeout(2, 60)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "alliance 2 has no timeouts remaining", err.Error())
+	}
+	assert.Equal(t, PreMatch, arena.MatchState)
+}
+
+func TestArenaInvokeBackup(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.EventSettings.NumElimAlliances = 2
+	tournament.CreateTestAlliances(arena.Database, 2)
+	arena.Database.CreateRanking(&game.Ranking{TeamId: 254, Rank: 9})
+	assert.Nil(t, arena.CreatePlayoffBracket())
+	assert.Nil(t, arena.UpdatePlayoffBracket(nil))
+	matches, _ := arena.Database.GetMatchesByType("elimination")
+	assert.Nil(t, arena.LoadMatch(&matches[0]))
+	assert.Equal(t, 201, arena.CurrentMatch.Blue2)
+
+	// The backup takes the replaced team's place in the loaded match and in the alliance's other matches.
+	backupTeamId, err := arena.InvokeBackup(2, 201)
+	assert.Nil(t, err)
+	assert.Equal(t, 254, backupTeamId)
+	assert.Equal(t, 254, arena.CurrentMatch.Blue2)
+	assert.Equal(t, 254, arena.AllianceStations["B2"].Team.Id)
+	matches, _ = arena.Database.GetMatchesByType("elimination")
+	for _, match := range matches {
+		assert.Equal(t, 254, match.Blue2)
+	}
+
+	arena.MatchState = PostMatch
+	_, err = arena.InvokeBackup(1, 101)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "cannot invoke a backup")
+	}
+}
+
 func TestSaveTeamHasConnected(t *testing.T) {
 	arena := setupTestArena(t)
 
@@ -647,3 +708,23 @@ func TestSaveTeamHasConnected(t *testing.T) {
 		assert.Equal(t, "San Jose", teams[5].City)
 	}
 }
+
+func TestLoadSettingsTiebreakerSeed(t *testing.T) {
+	arena := setupTestArena(t)
+	seed := arena.EventSettings.TiebreakerSeed
+	assert.NotEqual(t, int64(0), seed)
+
+	// Loading settings saved before the seed existed should generate one and persist it.
+	arena.EventSettings.TiebreakerSeed = 0
+	assert.Nil(t, arena.Database.UpdateEventSettings(arena.EventSettings))
+	assert.Nil(t, arena.LoadSettings())
+	assert.NotEqual(t, int64(0), arena.EventSettings.TiebreakerSeed)
+	assert.NotEqual(t, seed, arena.EventSettings.TiebreakerSeed)
+	eventSettings, _ := arena.Database.GetEventSettings()
+	assert.Equal(t, arena.EventSettings.TiebreakerSeed, eventSettings.TiebreakerSeed)
+
+	// Loading them again should leave the seed alone.
+	seed = arena.EventSettings.TiebreakerSeed
+	assert.Nil(t, arena.LoadSettings())
+	assert.Equal(t, seed, arena.EventSettings.TiebreakerSeed)
+}
@@ -6,15 +6,19 @@
 package field
 
 import (
+	"errors"
 	"fmt"
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/FRCTeam1987/crimson-arena/network"
+	"io"
 	"log"
 	"net"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
 // FMS uses 1121 for sending UDP packets, and FMS Lite uses 1120. Using 1121
@@ -26,6 +30,19 @@ const (
 	driverStationTcpLinkTimeoutSec = 5
 	driverStationUdpLinkTimeoutSec = 1
 	maxTcpPacketBytes              = 4096
+	maxDsLogMessages               = 10
+)
+
+// Tags identifying the types of packets that a driver station sends to the field over TCP.
+const (
+	dsTcpWpilibVersionTag = 0x00
+	dsTcpRioVersionTag    = 0x01
+	dsTcpDsVersionTag     = 0x02
+	dsTcpUsageReportTag   = 0x15
+	dsTcpRobotStatusTag   = 0x16
+	dsTcpLogMessageTag    = 0x17
+	dsTcpTeamNumberTag    = 0x18
+	dsTcpKeepaliveTag     = 0x1c
 )
 
 type DriverStationConnection struct {
@@ -42,10 +59,24 @@ type DriverStationConnection struct {
 	DsRobotTripTimeMs         int
 	MissedPacketCount         int
 	SecondsSinceLastRobotLink float64
+	Brownout                  bool
+	CanUtilization            int
+	CpuUsage                  int
+	RamUsage                  int
+	DiskUsage                 int
+	PdpTotalCurrent           float64
+	PdpCurrents               []float64
+	RadioSignalDb             int
+	RadioBandwidthMbps        float64
+	WpilibVersion             string
+	RioVersion                string
+	DsVersion                 string
+	LogMessages               []string
 	lastPacketTime            time.Time
 	lastRobotLinkedTime       time.Time
 	packetCount               int
 	missedPacketOffset        int
+	sentGameData              string
 	tcpConn                   net.Conn
 	udpConn                   net.Conn
 	log                       *TeamMatchLog
@@ -81,10 +112,16 @@ func (arena *Arena) listenForDsUdpPackets() {
 		log.Fatalf("Error opening driver station UDP socket: %v", err)
 	}
 	log.Printf("Listening for driver stations on UDP port %d\n", driverStationUdpReceivePort)
+	arena.handleDsUdpPackets(listener)
+}
 
+// Loops until the given socket is closed to read status packets from driver stations and update their connections.
+func (arena *Arena) handleDsUdpPackets(listener *net.UDPConn) {
 	var data [50]byte
 	for {
-		listener.Read(data[:])
+		if _, err := listener.Read(data[:]); errors.Is(err, net.ErrClosed) {
+			return
+		}
 
 		teamId := int(data[4])<<8 + int(data[5])
 
@@ -126,6 +163,7 @@ func (dsConn *DriverStationConnection) update(arena *Arena) error {
 		dsConn.RadioLinked = false
 		dsConn.RobotLinked = false
 		dsConn.BatteryVoltage = 0
+		dsConn.Brownout = false
 	}
 	dsConn.SecondsSinceLastRobotLink = time.Since(dsConn.lastRobotLinkedTime).Seconds()
 
@@ -267,6 +305,79 @@ func (dsConn *DriverStationConnection) decodeStatusPacket(data [36]byte) {
 
 	// Number of missed packets sent from the DS to the robot.
 	dsConn.MissedPacketCount = int(data[2]) - dsConn.missedPacketOffset
+
+	// Bytes 3-4 repeat the battery voltage, which is already read more frequently from the UDP packets, and byte 6 holds
+	// the robot mode flags, which the field already knows.
+	dsConn.Brownout = data[5] != 0
+
+	// CAN bus utilization as a percentage.
+	dsConn.CanUtilization = int(data[7])
+
+	// Robot radio signal strength in dB, stored as a signed byte.
+	dsConn.RadioSignalDb = int(int8(data[8]))
+
+	// Robot radio bandwidth usage, stored as megabits per second * 256.
+	dsConn.RadioBandwidthMbps = float64(data[9]) + float64(data[10])/256
+}
+
+// Deserializes the robot resource usage that the DS relays from the roboRIO and PDP.
+func (dsConn *DriverStationConnection) decodeUsageReportPacket(data []byte) {
+	if len(data) < 6 {
+		return
+	}
+
+	// roboRIO CPU, RAM, and disk utilization as percentages.
+	dsConn.CpuUsage = int(data[1])
+	dsConn.RamUsage = int(data[2])
+	dsConn.DiskUsage = int(data[3])
+
+	// Total PDP current, stored big-endian as amps * 10.
+	dsConn.PdpTotalCurrent = float64(int(data[4])<<8+int(data[5])) / 10
+
+	// Current on each PDP channel in turn, stored as amps * 2.
+	dsConn.PdpCurrents = make([]float64, len(data)-6)
+	for i, current := range data[6:] {
+		dsConn.PdpCurrents[i] = float64(current) / 2
+	}
+}
+
+// Deserializes a single tagged packet received from the DS over TCP.
+func (dsConn *DriverStationConnection) decodeTcpPacket(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	switch data[0] {
+	case dsTcpKeepaliveTag, dsTcpTeamNumberTag:
+		// Nothing to record.
+	case dsTcpRobotStatusTag:
+		var statusPacket [36]byte
+		copy(statusPacket[:], data)
+		dsConn.decodeStatusPacket(statusPacket)
+	case dsTcpUsageReportTag:
+		dsConn.decodeUsageReportPacket(data)
+	case dsTcpWpilibVersionTag:
+		dsConn.WpilibVersion = decodeDsString(data[1:])
+	case dsTcpRioVersionTag:
+		dsConn.RioVersion = decodeDsString(data[1:])
+	case dsTcpDsVersionTag:
+		dsConn.DsVersion = decodeDsString(data[1:])
+	case dsTcpLogMessageTag:
+		message := decodeDsString(data[1:])
+		if message == "" {
+			return
+		}
+		log.Printf("Driver station log message from Team %d: %s", dsConn.TeamId, message)
+		dsConn.LogMessages = append(dsConn.LogMessages, message)
+		if len(dsConn.LogMessages) > maxDsLogMessages {
+			dsConn.LogMessages = dsConn.LogMessages[len(dsConn.LogMessages)-maxDsLogMessages:]
+		}
+	}
+}
+
+// Returns the text in the given packet data, discarding any surrounding length or status bytes.
+func decodeDsString(data []byte) string {
+	return strings.TrimFunc(string(data), func(r rune) bool { return !unicode.IsPrint(r) || unicode.IsSpace(r) })
 }
 
 // Listens for TCP connection requests to Cheesy Arena from driver stations.
@@ -280,8 +391,16 @@ func (arena *Arena) listenForDriverStations() {
 	defer l.Close()
 
 	log.Printf("Listening for driver stations on TCP port %d\n", driverStationTcpListenPort)
+	arena.acceptDriverStations(l)
+}
+
+// Loops until the given listener is closed to accept and register driver station connections.
+func (arena *Arena) acceptDriverStations(l net.Listener) {
 	for {
 		tcpConn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
 		if err != nil {
 			log.Println("Error accepting driver station connection: ", err.Error())
 			continue
@@ -365,26 +484,30 @@ func (arena *Arena) listenForDriverStations() {
 func (dsConn *DriverStationConnection) handleTcpConnection(arena *Arena) {
 	buffer := make([]byte, maxTcpPacketBytes)
 	for {
+		// Each packet is preceded by its size, stored big-endian in two bytes, so that several packets arriving together
+		// or one packet arriving in pieces can be told apart.
 		dsConn.tcpConn.SetReadDeadline(time.Now().Add(time.Second * driverStationTcpLinkTimeoutSec))
-		_, err := dsConn.tcpConn.Read(buffer)
+		_, err := io.ReadFull(dsConn.tcpConn, buffer[:2])
+		packetSize := int(buffer[0])<<8 + int(buffer[1])
+		if err == nil && packetSize > maxTcpPacketBytes {
+			err = fmt.Errorf("packet size %d is too large", packetSize)
+		}
+		if err == nil {
+			_, err = io.ReadFull(dsConn.tcpConn, buffer[:packetSize])
+		}
 		if err != nil {
 			log.Printf("Error reading from connection for Team %d: %v", dsConn.TeamId, err)
 			dsConn.close()
 			arena.AllianceStations[dsConn.AllianceStation].DsConn = nil
 			break
 		}
-
-		packetType := int(buffer[2])
-		switch packetType {
-		case 28:
-			// DS keepalive packet; do nothing.
-		case 22:
-			// Robot status packet.
-			var statusPacket [36]byte
-			copy(statusPacket[:], buffer[2:38])
-			dsConn.decodeStatusPacket(statusPacket)
+		if packetSize == 0 {
+			continue
 		}
 
+		packetType := int(buffer[0])
+		dsConn.decodeTcpPacket(buffer[:packetSize])
+
 		// Log the packet if the match is in progress.
 		matchTimeSec := arena.MatchTimeSec()
 		if matchTimeSec > 0 && dsConn.log != nil {
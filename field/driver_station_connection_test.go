@@ -4,6 +4,7 @@
 package field
 
 import (
+	"fmt"
 	"github.com/FRCTeam1987/crimson-arena/network"
 	"github.com/stretchr/testify/assert"
 	"net"
@@ -159,6 +160,86 @@ func TestDecodeStatusPacket(t *testing.T) {
 	assert.Equal(t, 14, dsConn.DsRobotTripTimeMs)
 }
 
+func TestDecodeTcpPackets(t *testing.T) {
+	dsConn := &DriverStationConnection{TeamId: 254}
+
+	data := [36]byte{22, 28, 103, 19, 192, 1, 246, 42, 0xc4, 3, 128}
+	dsConn.decodeTcpPacket(data[:])
+	assert.Equal(t, 14, dsConn.DsRobotTripTimeMs)
+	assert.Equal(t, 103, dsConn.MissedPacketCount)
+	assert.True(t, dsConn.Brownout)
+	assert.Equal(t, 42, dsConn.CanUtilization)
+	assert.Equal(t, -60, dsConn.RadioSignalDb)
+	assert.Equal(t, 3.5, dsConn.RadioBandwidthMbps)
+
+	dsConn.decodeTcpPacket([]byte{21, 55, 70, 25, 0x01, 0x2c, 20, 0, 81})
+	assert.Equal(t, 55, dsConn.CpuUsage)
+	assert.Equal(t, 70, dsConn.RamUsage)
+	assert.Equal(t, 25, dsConn.DiskUsage)
+	assert.Equal(t, 30.0, dsConn.PdpTotalCurrent)
+	assert.Equal(t, []float64{10, 0, 40.5}, dsConn.PdpCurrents)
+
+	// A truncated usage report should be ignored.
+	dsConn.decodeTcpPacket([]byte{21, 10})
+	assert.Equal(t, 55, dsConn.CpuUsage)
+
+	dsConn.decodeTcpPacket(append([]byte{0, 0}, "2026.1.1"...))
+	dsConn.decodeTcpPacket(append([]byte{1, 0}, "FRC_roboRIO_2026_v1.2"...))
+	dsConn.decodeTcpPacket(append([]byte{2, 0}, "26.0 "...))
+	assert.Equal(t, "2026.1.1", dsConn.WpilibVersion)
+	assert.Equal(t, "FRC_roboRIO_2026_v1.2", dsConn.RioVersion)
+	assert.Equal(t, "26.0", dsConn.DsVersion)
+
+	for i := 0; i < 12; i++ {
+		dsConn.decodeTcpPacket(append([]byte{23}, fmt.Sprintf("Message %d\n", i)...))
+	}
+	dsConn.decodeTcpPacket([]byte{23})
+	if assert.Equal(t, maxDsLogMessages, len(dsConn.LogMessages)) {
+		assert.Equal(t, "Message 2", dsConn.LogMessages[0])
+		assert.Equal(t, "Message 11", dsConn.LogMessages[9])
+	}
+
+	// Keepalives and unknown packet types should be ignored.
+	dsConn.decodeTcpPacket([]byte{28})
+	dsConn.decodeTcpPacket([]byte{99, 1, 2, 3})
+	dsConn.decodeTcpPacket([]byte{})
+	assert.Equal(t, 14, dsConn.DsRobotTripTimeMs)
+}
+
+func TestHandleTcpConnectionFraming(t *testing.T) {
+	arena := setupTestArena(t)
+	fieldConn, dsConnEnd := net.Pipe()
+	defer dsConnEnd.Close()
+	dsConn := &DriverStationConnection{TeamId: 254, AllianceStation: "R1", tcpConn: fieldConn}
+	arena.AllianceStations["R1"].DsConn = dsConn
+	done := make(chan struct{})
+	go func() {
+		dsConn.handleTcpConnection(arena)
+		close(done)
+	}()
+
+	// Send two packets in a single write, followed by one split across two writes.
+	statusPacket := make([]byte, 38)
+	copy(statusPacket, []byte{0, 36, 22, 28, 7})
+	versionPacket := append([]byte{0, 6, 2, 0}, "DS 1"...)
+	dsConnEnd.Write(append(versionPacket, statusPacket...))
+	usagePacket := []byte{0, 6, 21, 50, 60, 70, 0, 100}
+	dsConnEnd.Write(usagePacket[:3])
+	dsConnEnd.Write(usagePacket[3:])
+	dsConnEnd.Write([]byte{0, 1, 28})
+
+	assert.Equal(t, "DS 1", dsConn.DsVersion)
+	assert.Equal(t, 14, dsConn.DsRobotTripTimeMs)
+	assert.Equal(t, 7, dsConn.MissedPacketCount)
+	assert.Equal(t, 50, dsConn.CpuUsage)
+	assert.Equal(t, 10.0, dsConn.PdpTotalCurrent)
+
+	// Closing the connection from the DS end should remove it from the station.
+	dsConnEnd.Close()
+	<-done
+	assert.Nil(t, arena.AllianceStations["R1"].DsConn)
+}
+
 func TestListenForDriverStations(t *testing.T) {
 	arena := setupTestArena(t)
 
@@ -0,0 +1,273 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Simulated driver stations for exercising the field without real laptops and robots.
+
+package field
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	simulatedDsUdpPeriodMs   = 100
+	simulatedDsTcpPeriodMs   = 500
+	simulatedDsRetryPeriodMs = 1000
+	simulatedDsVersion       = "Crimson Arena Simulated DS"
+)
+
+// The robot conditions that a simulated driver station reports to the field.
+type SimulatedRobot struct {
+	RobotLinked    bool
+	BatteryVoltage float64
+	TripTimeMs     int
+}
+
+// Pretends to be the driver stations of any number of teams, connecting to the field over the same TCP and UDP
+// protocols that the real Driver Station software uses.
+type DriverStationSimulator struct {
+	TcpAddress string
+	UdpAddress string
+	mutex      sync.Mutex
+	stations   map[int]*simulatedDriverStation
+}
+
+type simulatedDriverStation struct {
+	teamId          int
+	robot           SimulatedRobot
+	allianceStation string
+	stop            chan struct{}
+}
+
+// Creates a simulator that connects to the field at the given IP address on the standard driver station ports.
+func NewDriverStationSimulator(serverIpAddress string) *DriverStationSimulator {
+	return &DriverStationSimulator{
+		TcpAddress: fmt.Sprintf("%s:%d", serverIpAddress, driverStationTcpListenPort),
+		UdpAddress: fmt.Sprintf("%s:%d", serverIpAddress, driverStationUdpReceivePort),
+		stations:   make(map[int]*simulatedDriverStation),
+	}
+}
+
+// Returns the robot conditions that a newly simulated driver station starts out reporting.
+func DefaultSimulatedRobot() SimulatedRobot {
+	return SimulatedRobot{RobotLinked: true, BatteryVoltage: 12.5, TripTimeMs: 10}
+}
+
+// Starts simulating driver stations for exactly the given teams, disconnecting any others that were being simulated.
+func (simulator *DriverStationSimulator) SetTeams(teamIds []int) {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	for teamId, station := range simulator.stations {
+		if !slices.Contains(teamIds, teamId) {
+			close(station.stop)
+			delete(simulator.stations, teamId)
+		}
+	}
+	for _, teamId := range teamIds {
+		if _, ok := simulator.stations[teamId]; teamId == 0 || ok {
+			continue
+		}
+		station := &simulatedDriverStation{teamId: teamId, robot: DefaultSimulatedRobot(), stop: make(chan struct{})}
+		simulator.stations[teamId] = station
+		go simulator.run(station)
+	}
+}
+
+// Returns the teams whose driver stations are being simulated.
+func (simulator *DriverStationSimulator) Teams() []int {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	var teamIds []int
+	for teamId := range simulator.stations {
+		teamIds = append(teamIds, teamId)
+	}
+	slices.Sort(teamIds)
+	return teamIds
+}
+
+// Changes the robot conditions reported by the given team's simulated driver station.
+func (simulator *DriverStationSimulator) SetRobot(teamId int, robot SimulatedRobot) error {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	station, ok := simulator.stations[teamId]
+	if !ok {
+		return fmt.Errorf("team %d does not have a simulated driver station", teamId)
+	}
+	station.robot = robot
+	return nil
+}
+
+// Returns the robot conditions reported by the given team's simulated driver station, and whether there is one.
+func (simulator *DriverStationSimulator) GetRobot(teamId int) (SimulatedRobot, bool) {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	if station, ok := simulator.stations[teamId]; ok {
+		return station.robot, true
+	}
+	return SimulatedRobot{}, false
+}
+
+// Returns the alliance station that the field assigned to the given team's simulated driver station, or an empty
+// string if it isn't connected.
+func (simulator *DriverStationSimulator) GetAllianceStation(teamId int) string {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	if station, ok := simulator.stations[teamId]; ok {
+		return station.allianceStation
+	}
+	return ""
+}
+
+// Keeps the given simulated driver station connected to the field until it is stopped, reconnecting whenever the
+// field drops or rejects it.
+func (simulator *DriverStationSimulator) run(station *simulatedDriverStation) {
+	for {
+		if err := simulator.connect(station); err != nil {
+			log.Printf("Simulated driver station for Team %d disconnected: %v", station.teamId, err)
+		}
+		simulator.setAllianceStation(station, "")
+		select {
+		case <-station.stop:
+			return
+		case <-time.After(simulatedDsRetryPeriodMs * time.Millisecond):
+		}
+	}
+}
+
+// Connects the given simulated driver station to the field and sends it status packets until the connection fails or
+// the station is stopped.
+func (simulator *DriverStationSimulator) connect(station *simulatedDriverStation) error {
+	tcpConn, err := net.DialTimeout("tcp4", simulator.TcpAddress, time.Second)
+	if err != nil {
+		return err
+	}
+	defer tcpConn.Close()
+
+	// Identify the team and wait to be told which alliance station it is in.
+	if _, err = tcpConn.Write([]byte{0, 3, 24, byte(station.teamId >> 8), byte(station.teamId & 0xff)}); err != nil {
+		return err
+	}
+	var assignmentPacket [5]byte
+	tcpConn.SetReadDeadline(time.Now().Add(time.Second * driverStationTcpLinkTimeoutSec))
+	if _, err = tcpConn.Read(assignmentPacket[:]); err != nil {
+		return fmt.Errorf("field did not assign an alliance station: %v", err)
+	}
+	if assignmentPacket[2] != 25 {
+		return fmt.Errorf("invalid station assignment packet received: %v", assignmentPacket)
+	}
+	allianceStation := ""
+	for stationName, position := range allianceStationPositionMap {
+		if position == assignmentPacket[3] {
+			allianceStation = stationName
+		}
+	}
+	simulator.setAllianceStation(station, allianceStation)
+	log.Printf("Simulated driver station for Team %d connected in station %s.", station.teamId, allianceStation)
+
+	if _, err = tcpConn.Write(encodeSimulatedTcpVersionPacket(dsTcpDsVersionTag, simulatedDsVersion)); err != nil {
+		return err
+	}
+
+	udpConn, err := net.Dial("udp4", simulator.UdpAddress)
+	if err != nil {
+		return err
+	}
+	defer udpConn.Close()
+
+	// Drain anything the field sends over TCP so that its writes never block, and notice when it hangs up.
+	tcpClosed := make(chan struct{})
+	go func() {
+		buffer := make([]byte, maxTcpPacketBytes)
+		for {
+			tcpConn.SetReadDeadline(time.Time{})
+			if _, err := tcpConn.Read(buffer); err != nil {
+				close(tcpClosed)
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(simulatedDsUdpPeriodMs * time.Millisecond)
+	defer ticker.Stop()
+	var packetCount int
+	for {
+		select {
+		case <-station.stop:
+			return nil
+		case <-tcpClosed:
+			return fmt.Errorf("field closed the connection")
+		case <-ticker.C:
+		}
+
+		robot, _ := simulator.GetRobot(station.teamId)
+		if _, err = udpConn.Write(encodeSimulatedUdpPacket(station.teamId, packetCount, robot)); err != nil {
+			return err
+		}
+		if packetCount%(simulatedDsTcpPeriodMs/simulatedDsUdpPeriodMs) == 0 {
+			if _, err = tcpConn.Write(encodeSimulatedTcpStatusPacket(robot)); err != nil {
+				return err
+			}
+		}
+		packetCount++
+	}
+}
+
+func (simulator *DriverStationSimulator) setAllianceStation(station *simulatedDriverStation, allianceStation string) {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+	station.allianceStation = allianceStation
+}
+
+// Serializes the given robot conditions into the UDP status packet that a driver station sends to the field.
+func encodeSimulatedUdpPacket(teamId, packetCount int, robot SimulatedRobot) []byte {
+	packet := make([]byte, 50)
+
+	// Packet number, stored big-endian in two bytes.
+	packet[0] = byte((packetCount >> 8) & 0xff)
+	packet[1] = byte(packetCount & 0xff)
+
+	// Link status byte; the roboRIO and radio are treated as linked whenever the robot is.
+	if robot.RobotLinked {
+		packet[3] = 0x08 | 0x10 | 0x20
+	}
+
+	// Team number, stored big-endian in two bytes.
+	packet[4] = byte(teamId >> 8)
+	packet[5] = byte(teamId & 0xff)
+
+	// Robot battery voltage, stored as volts * 256.
+	if robot.RobotLinked {
+		packet[6] = byte(int(robot.BatteryVoltage))
+		packet[7] = byte(int((robot.BatteryVoltage - float64(int(robot.BatteryVoltage))) * 256))
+	}
+
+	return packet
+}
+
+// Serializes the given robot conditions into the TCP robot status packet that a driver station sends to the field.
+func encodeSimulatedTcpStatusPacket(robot SimulatedRobot) []byte {
+	packet := make([]byte, 38)
+	packet[0] = 0  // Packet size
+	packet[1] = 36 // Packet size
+	packet[2] = 22 // Packet type
+
+	// Average DS-robot trip time, stored as milliseconds * 2.
+	packet[3] = byte(min(max(robot.TripTimeMs, 0)*2, 255))
+
+	return packet
+}
+
+// Serializes the given version string into the TCP packet with the given tag that a driver station sends to the field.
+func encodeSimulatedTcpVersionPacket(tag byte, version string) []byte {
+	packet := []byte{0, byte(len(version) + 1), tag}
+	return append(packet, version...)
+}
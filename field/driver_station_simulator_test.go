@@ -0,0 +1,101 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDriverStationSimulator(t *testing.T) {
+	arena := setupTestArena(t)
+	arena.Database.CreateTeam(&model.Team{Id: 254})
+	arena.Database.CreateTeam(&model.Team{Id: 1114})
+	assert.Nil(t, arena.assignTeam(254, "R1"))
+	assert.Nil(t, arena.assignTeam(1114, "B3"))
+
+	// Have the arena listen for driver stations on spare local ports.
+	tcpListener, err := net.Listen("tcp4", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer tcpListener.Close()
+	go arena.acceptDriverStations(tcpListener)
+	udpListener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.Nil(t, err)
+	defer udpListener.Close()
+	go arena.handleDsUdpPackets(udpListener)
+
+	simulator := NewDriverStationSimulator("127.0.0.1")
+	simulator.TcpAddress = tcpListener.Addr().String()
+	simulator.UdpAddress = udpListener.LocalAddr().String()
+	simulator.SetTeams([]int{254, 1114, 9999})
+	defer simulator.SetTeams(nil)
+	assert.Equal(t, []int{254, 1114, 9999}, simulator.Teams())
+
+	waitFor := func(condition func() bool) bool {
+		for i := 0; i < 100; i++ {
+			if condition() {
+				return true
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		return false
+	}
+	assert.True(t, waitFor(func() bool {
+		red1, blue3 := arena.AllianceStations["R1"].DsConn, arena.AllianceStations["B3"].DsConn
+		return red1 != nil && red1.RobotLinked && red1.DsRobotTripTimeMs > 0 && blue3 != nil && blue3.RobotLinked
+	}))
+	dsConn := arena.AllianceStations["R1"].DsConn
+	assert.Equal(t, 254, dsConn.TeamId)
+	assert.Equal(t, simulatedDsVersion, dsConn.DsVersion)
+	assert.True(t, dsConn.DsLinked)
+	assert.Equal(t, 12.5, dsConn.BatteryVoltage)
+	assert.Equal(t, 10, dsConn.DsRobotTripTimeMs)
+	assert.Equal(t, "R1", simulator.GetAllianceStation(254))
+	assert.Equal(t, "B3", simulator.GetAllianceStation(1114))
+	assert.Equal(t, "", simulator.GetAllianceStation(9999))
+
+	// Check that the field sees changes in the simulated robot conditions.
+	assert.NotNil(t, simulator.SetRobot(9998, DefaultSimulatedRobot()))
+	assert.Nil(t, simulator.SetRobot(254, SimulatedRobot{RobotLinked: true, BatteryVoltage: 11.75, TripTimeMs: 40}))
+	assert.True(t, waitFor(func() bool { return dsConn.BatteryVoltage == 11.75 && dsConn.DsRobotTripTimeMs == 40 }))
+	arena.AllianceStations["R1"].Bypass = true
+	assert.Nil(t, simulator.SetRobot(1114, SimulatedRobot{}))
+	assert.True(t, waitFor(func() bool { return !arena.AllianceStations["B3"].DsConn.RobotLinked }))
+	err = arena.checkAllianceStationsReady("R1", "B3")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "until all robots are connected")
+	}
+	assert.Nil(t, simulator.SetRobot(1114, DefaultSimulatedRobot()))
+	assert.True(t, waitFor(func() bool { return arena.AllianceStations["B3"].DsConn.RobotLinked }))
+	err = arena.checkAllianceStationsReady("R1", "B3")
+	if assert.NotNil(t, err) {
+		assert.NotContains(t, err.Error(), "until all robots are connected")
+	}
+
+	// Check that removing a team from the simulation disconnects its driver station.
+	simulator.SetTeams([]int{254})
+	assert.Equal(t, []int{254}, simulator.Teams())
+	assert.True(t, waitFor(func() bool {
+		dsConn := arena.AllianceStations["B3"].DsConn
+		return dsConn == nil || !dsConn.DsLinked
+	}))
+}
+
+func TestEncodeSimulatedPackets(t *testing.T) {
+	packet := encodeSimulatedUdpPacket(1503, 258, SimulatedRobot{RobotLinked: true, BatteryVoltage: 12.25})
+	assert.Equal(t, []byte{1, 2, 0, 0x38, 5, 223, 12, 64}, packet[:8])
+	packet = encodeSimulatedUdpPacket(1503, 0, SimulatedRobot{BatteryVoltage: 12.25})
+	assert.Equal(t, []byte{0, 0, 0, 0, 5, 223, 0, 0}, packet[:8])
+
+	packet = encodeSimulatedTcpStatusPacket(SimulatedRobot{TripTimeMs: 14})
+	assert.Equal(t, []byte{0, 36, 22, 28}, packet[:4])
+	assert.Equal(t, 38, len(packet))
+	packet = encodeSimulatedTcpStatusPacket(SimulatedRobot{TripTimeMs: 500})
+	assert.Equal(t, byte(255), packet[3])
+
+	packet = encodeSimulatedTcpVersionPacket(dsTcpDsVersionTag, "DS 1.0")
+	assert.Equal(t, []byte{0, 7, 2, 'D', 'S', ' ', '1', '.', '0'}, packet)
+}
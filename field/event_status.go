@@ -7,6 +7,9 @@ package field
 
 import (
 	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/FRCTeam1987/crimson-arena/tournament"
+	"log"
 	"math"
 	"time"
 )
@@ -105,3 +108,81 @@ func (arena *Arena) getEarlyLateMessage() string {
 	}
 	return "Event is running on schedule"
 }
+
+// Shifts the scheduled times of the remaining matches so that the current match is scheduled for when it actually
+// started, or for now if it hasn't started yet, and notifies the displays that show the schedule. Returns the matches
+// whose times changed; it is up to the caller to republish them to TBA.
+func (arena *Arena) RetimeRemainingMatches() ([]model.Match, error) {
+	currentMatch := arena.CurrentMatch
+	if currentMatch.Type != "practice" && currentMatch.Type != "qualification" {
+		return nil, fmt.Errorf("only practice and qualification matches can be re-timed")
+	}
+	if currentMatch.IsComplete() {
+		return nil, fmt.Errorf("cannot re-time the schedule from a match that has already been played")
+	}
+	startTime := currentMatch.StartedAt
+	if arena.MatchState == PreMatch || startTime.IsZero() {
+		startTime = time.Now()
+	}
+
+	matches, err := arena.Database.GetMatchesByType(currentMatch.Type)
+	if err != nil {
+		return nil, err
+	}
+	scheduleBlocks, err := arena.Database.GetScheduleBlocksByMatchType(currentMatch.Type)
+	if err != nil {
+		return nil, err
+	}
+	index := -1
+	for i, match := range matches {
+		if match.Id == currentMatch.Id {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, fmt.Errorf("current match is not part of the %s schedule", currentMatch.Type)
+	}
+
+	retimedMatches := tournament.RetimeMatches(
+		matches, scheduleBlocks, index, startTime.Truncate(time.Second), arena.EventSettings.RetimeCycleTimeSec,
+	)
+	for _, match := range retimedMatches {
+		if err = arena.Database.UpdateMatch(&match); err != nil {
+			return nil, err
+		}
+		if match.Id == currentMatch.Id {
+			currentMatch.Time = match.Time
+		}
+	}
+	if len(retimedMatches) > 0 {
+		arena.ScheduleUpdateNotifier.NotifyWithMessage(retimedMatches)
+		arena.updateEarlyLateMessage()
+	}
+	return retimedMatches, nil
+}
+
+// Re-times the remaining matches if the current match started far enough off schedule to be flagged as early or late.
+// Publishing to TBA happens in the background so as not to hold up the match.
+func (arena *Arena) autoRetimeRemainingMatches() {
+	currentMatch := arena.CurrentMatch
+	if currentMatch.Type != "practice" && currentMatch.Type != "qualification" || currentMatch.IsComplete() {
+		return
+	}
+	if math.Abs(currentMatch.StartedAt.Sub(currentMatch.Time).Minutes()) <= earlyLateThresholdMin {
+		return
+	}
+
+	retimedMatches, err := arena.RetimeRemainingMatches()
+	if err != nil {
+		log.Printf("Failed to re-time remaining matches: %s", err.Error())
+		return
+	}
+	if len(retimedMatches) > 0 && arena.EventSettings.TbaPublishingEnabled && currentMatch.Type != "practice" {
+		go func() {
+			if err := arena.TbaClient.PublishMatches(arena.Database); err != nil {
+				log.Printf("Failed to publish re-timed matches: %s", err.Error())
+			}
+		}()
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/stretchr/testify/assert"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -115,6 +116,78 @@ func TestEarlyLateMessage(t *testing.T) {
 	assert.Equal(t, "", arena.getEarlyLateMessage())
 }
 
+func TestRetimeRemainingMatches(t *testing.T) {
+	arena := setupTestArena(t)
+
+	arena.LoadTestMatch()
+	_, err := arena.RetimeRemainingMatches()
+	assert.EqualError(t, err, "only practice and qualification matches can be re-timed")
+
+	blockStart := time.Now().UTC().Add(-30 * time.Minute).Truncate(time.Second)
+	arena.Database.CreateScheduleBlock(
+		&model.ScheduleBlock{MatchType: "qualification", StartTime: blockStart, NumMatches: 4, MatchSpacingSec: 360},
+	)
+	for i := 0; i < 4; i++ {
+		arena.Database.CreateMatch(&model.Match{
+			Type: "qualification", DisplayName: strconv.Itoa(i + 1), Time: blockStart.Add(time.Duration(i*6) * time.Minute),
+		})
+	}
+	matches, _ := arena.Database.GetMatchesByType("qualification")
+	setMatch(arena.Database, &matches[0], matches[0].Time, matches[0].Time, true)
+	arena.CurrentMatch = &matches[1]
+	arena.MatchState = PreMatch
+	arena.EventSettings.RetimeCycleTimeSec = 420
+
+	// The current match should be moved to now and the rest should follow at the configured cycle time.
+	retimedMatches, err := arena.RetimeRemainingMatches()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(retimedMatches))
+	assert.Equal(t, "Event is running on schedule", arena.EventStatus.EarlyLateMessage)
+	matches, _ = arena.Database.GetMatchesByType("qualification")
+	assert.Equal(t, blockStart, matches[0].Time)
+	assert.True(t, arena.CurrentMatch.Time.Equal(matches[1].Time))
+	assert.WithinDuration(t, time.Now(), matches[1].Time, 2*time.Second)
+	assert.Equal(t, matches[1].Time.Add(7*time.Minute), matches[2].Time)
+	assert.Equal(t, matches[1].Time.Add(14*time.Minute), matches[3].Time)
+
+	// A completed match can't anchor the schedule.
+	arena.CurrentMatch = &matches[0]
+	_, err = arena.RetimeRemainingMatches()
+	assert.EqualError(t, err, "cannot re-time the schedule from a match that has already been played")
+}
+
+func TestAutoRetimeRemainingMatches(t *testing.T) {
+	arena := setupTestArena(t)
+
+	blockStart := time.Now().UTC().Add(-30 * time.Minute).Truncate(time.Second)
+	arena.Database.CreateScheduleBlock(
+		&model.ScheduleBlock{MatchType: "qualification", StartTime: blockStart, NumMatches: 3, MatchSpacingSec: 360},
+	)
+	for i := 0; i < 3; i++ {
+		arena.Database.CreateMatch(&model.Match{
+			Type: "qualification", DisplayName: strconv.Itoa(i + 1), Time: blockStart.Add(time.Duration(i*6) * time.Minute),
+		})
+	}
+	matches, _ := arena.Database.GetMatchesByType("qualification")
+	arena.CurrentMatch = &matches[0]
+	arena.MatchState = AutoPeriod
+
+	// Starting close enough to the scheduled time should leave the schedule alone.
+	matches[0].StartedAt = blockStart.Add(2 * time.Minute)
+	arena.autoRetimeRemainingMatches()
+	matches, _ = arena.Database.GetMatchesByType("qualification")
+	assert.Equal(t, blockStart.Add(6*time.Minute), matches[1].Time)
+
+	// Starting well behind should shift the rest of the block back.
+	arena.CurrentMatch = &matches[0]
+	matches[0].StartedAt = blockStart.Add(10 * time.Minute)
+	arena.autoRetimeRemainingMatches()
+	matches, _ = arena.Database.GetMatchesByType("qualification")
+	assert.Equal(t, blockStart.Add(10*time.Minute), matches[0].Time)
+	assert.Equal(t, blockStart.Add(16*time.Minute), matches[1].Time)
+	assert.Equal(t, blockStart.Add(22*time.Minute), matches[2].Time)
+}
+
 func setMatch(database *model.Database, match *model.Match, matchTime time.Time, startedAt time.Time, isComplete bool) {
 	match.Time = matchTime
 	match.StartedAt = startedAt
@@ -0,0 +1,57 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Methods for changing the game definition that drives scoring for the event.
+
+package field
+
+import (
+	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"os"
+	"reflect"
+)
+
+// Reads the game definition from the given JSON or YAML file and makes it the event's scoring model.
+func (arena *Arena) LoadGameDefinitionFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	definition, err := game.ParseGameDefinition(data)
+	if err != nil {
+		return fmt.Errorf("invalid game definition in %s: %v", path, err)
+	}
+	return arena.SetGameDefinition(definition)
+}
+
+// Makes the given game definition the event's scoring model, unless doing so would change the meaning of results
+// that have already been recorded or leave the ranking rules or playoff tiebreakers referring to elements the game no
+// longer has.
+func (arena *Arena) SetGameDefinition(definition *game.GameDefinition) error {
+	if reflect.DeepEqual(definition, arena.EventSettings.GameDefinition) {
+		return nil
+	}
+	hasMatchResults, err := arena.Database.HasMatchResults()
+	if err != nil {
+		return err
+	}
+	if hasMatchResults {
+		return fmt.Errorf(
+			"the game definition can't be changed once match results have been recorded; clear the match data first",
+		)
+	}
+
+	if err = arena.EventSettings.RankingRules.Validate(definition); err != nil {
+		return fmt.Errorf("the ranking rules don't fit the new game definition (%v); update them first", err)
+	}
+	if err = game.ValidatePlayoffTiebreakers(arena.EventSettings.PlayoffTiebreakers, definition); err != nil {
+		return fmt.Errorf("the playoff tiebreakers don't fit the new game definition (%v); update them first", err)
+	}
+
+	settings := *arena.EventSettings
+	settings.GameDefinition = definition
+	if err = arena.Database.UpdateEventSettings(&settings); err != nil {
+		return err
+	}
+	return arena.LoadSettings()
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadGameDefinitionFile(t *testing.T) {
+	arena := setupTestArena(t)
+	defer func() { game.CurrentGame = game.DefaultGameDefinition() }()
+
+	// Check that the definitions shipped with the application can be loaded.
+	assert.Nil(t, arena.LoadGameDefinitionFile(filepath.Join(model.BaseDir, "game_definitions/generic.json")))
+	assert.Equal(t, "Generic", arena.EventSettings.GameDefinition.Name)
+	assert.Nil(t, arena.LoadGameDefinitionFile(filepath.Join(model.BaseDir, "game_definitions/2024_crescendo.yaml")))
+	assert.Equal(t, "CRESCENDO", arena.EventSettings.GameDefinition.Name)
+	assert.Equal(t, arena.EventSettings.GameDefinition, game.CurrentGame)
+	eventSettings, _ := arena.Database.GetEventSettings()
+	assert.Equal(t, "CRESCENDO", eventSettings.GameDefinition.Name)
+
+	assert.NotNil(t, arena.LoadGameDefinitionFile(filepath.Join(model.BaseDir, "game_definitions/missing.yaml")))
+	assert.Equal(t, "CRESCENDO", arena.EventSettings.GameDefinition.Name)
+}
+
+func TestSetGameDefinitionWithMatchResults(t *testing.T) {
+	arena := setupTestArena(t)
+	defer func() { game.CurrentGame = game.DefaultGameDefinition() }()
+	assert.Nil(t, arena.Database.CreateMatchResult(model.BuildTestMatchResult(1, 1)))
+
+	// Reloading the same definition, e.g. at startup, is allowed.
+	assert.Nil(t, arena.SetGameDefinition(game.DefaultGameDefinition()))
+
+	definition := game.DefaultGameDefinition()
+	definition.ScoringElements[0].Points = 2
+	err := arena.SetGameDefinition(definition)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "once match results have been recorded")
+	}
+	assert.Equal(t, 1, arena.EventSettings.GameDefinition.ScoringElements[0].Points)
+	assert.Equal(t, 1, game.CurrentGame.ScoringElements[0].Points)
+}
+
+func TestSetGameDefinitionWithRankingRules(t *testing.T) {
+	arena := setupTestArena(t)
+	defer func() {
+		game.CurrentGame = game.DefaultGameDefinition()
+		game.CurrentRankingRules = game.DefaultRankingRules()
+	}()
+	arena.EventSettings.RankingRules.BonusRankingPoints = []game.BonusRankingPoint{
+		{Name: "Teleop Bonus", Component: "teleop", Threshold: 10, RankingPoints: 1},
+	}
+
+	// A definition without the element that the bonus ranking point counts is rejected.
+	definition := &game.GameDefinition{
+		Name: "Test Game", ScoringElements: []game.ScoringElement{{Id: "note", Name: "Note", Period: game.AutoPeriod}},
+	}
+	err := arena.SetGameDefinition(definition)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "ranking rules")
+		assert.Contains(t, err.Error(), "teleop")
+	}
+	assert.Equal(t, "Generic", arena.EventSettings.GameDefinition.Name)
+
+	definition.ScoringElements = append(
+		definition.ScoringElements, game.ScoringElement{Id: "teleop", Name: "Teleop", Period: game.TeleopPeriod},
+	)
+	assert.Nil(t, arena.SetGameDefinition(definition))
+	assert.Equal(t, "Test Game", arena.EventSettings.GameDefinition.Name)
+}
+
+func TestSetGameDefinitionWithPlayoffTiebreakers(t *testing.T) {
+	arena := setupTestArena(t)
+	defer func() { game.CurrentGame = game.DefaultGameDefinition() }()
+	arena.EventSettings.PlayoffTiebreakers = []game.PlayoffTiebreaker{"endgame"}
+
+	// A definition without the element that a playoff tiebreaker compares is rejected.
+	definition := &game.GameDefinition{
+		Name: "Test Game", ScoringElements: []game.ScoringElement{{Id: "note", Name: "Note", Period: game.AutoPeriod}},
+	}
+	err := arena.SetGameDefinition(definition)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "playoff tiebreakers")
+		assert.Contains(t, err.Error(), "endgame")
+	}
+	assert.Equal(t, "Generic", arena.EventSettings.GameDefinition.Name)
+
+	arena.EventSettings.PlayoffTiebreakers = []game.PlayoffTiebreaker{"note"}
+	assert.Nil(t, arena.SetGameDefinition(definition))
+	assert.Equal(t, "Test Game", arena.EventSettings.GameDefinition.Name)
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Methods for choosing the game-specific message for each alliance and revealing it to the driver stations.
+
+package field
+
+import (
+	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"log"
+	"math/rand"
+)
+
+// Picks the game-specific messages for the given match at random from the configured choices, leaving any that were
+// entered by hand alone if there are no choices.
+func (arena *Arena) chooseGameData(match *model.Match) {
+	choices := arena.EventSettings.GameSpecificDataChoices()
+	if len(choices) == 0 {
+		return
+	}
+	match.RedGameData = choices[rand.Intn(len(choices))]
+	if arena.EventSettings.GameSpecificDataPerAlliance {
+		match.BlueGameData = choices[rand.Intn(len(choices))]
+	} else {
+		match.BlueGameData = match.RedGameData
+	}
+}
+
+// Sets the game-specific messages for the current match, replacing any that were chosen at random, and saves them with
+// the match.
+func (arena *Arena) SetGameData(redGameData, blueGameData string) error {
+	if arena.MatchState != PreMatch {
+		return fmt.Errorf("cannot change the game-specific message once the match has started")
+	}
+	for _, gameData := range []string{redGameData, blueGameData} {
+		if len(gameData) > model.MaxGameSpecificDataLength {
+			return fmt.Errorf(
+				"game-specific message '%s' is longer than %d characters", gameData, model.MaxGameSpecificDataLength,
+			)
+		}
+	}
+	arena.CurrentMatch.RedGameData = redGameData
+	arena.CurrentMatch.BlueGameData = blueGameData
+	if arena.CurrentMatch.Id != 0 {
+		if err := arena.Database.UpdateMatch(arena.CurrentMatch); err != nil {
+			return err
+		}
+	}
+	arena.MatchLoadNotifier.Notify()
+	arena.GameDataNotifier.Notify()
+	return nil
+}
+
+// Reveals the game-specific messages once the match reaches the configured time, and keeps each driver station
+// showing its alliance's message, or nothing before then.
+func (arena *Arena) updateGameData(matchTimeSec float64) {
+	isMatchRunning := arena.MatchState == AutoPeriod || arena.MatchState == PausePeriod ||
+		arena.MatchState == TeleopPeriod
+	revealTimeSec := float64(game.MatchTiming.WarmupDurationSec + arena.EventSettings.GameSpecificDataRevealSec)
+	if !arena.GameDataRevealed && isMatchRunning && matchTimeSec >= revealTimeSec {
+		arena.GameDataRevealed = true
+	}
+
+	for station, allianceStation := range arena.AllianceStations {
+		dsConn := allianceStation.DsConn
+		if dsConn == nil {
+			continue
+		}
+		gameData := ""
+		if arena.GameDataRevealed {
+			if station[0] == 'R' {
+				gameData = arena.CurrentMatch.RedGameData
+			} else {
+				gameData = arena.CurrentMatch.BlueGameData
+			}
+		}
+		if gameData == dsConn.sentGameData {
+			continue
+		}
+		if err := dsConn.sendGameDataPacket(gameData); err != nil {
+			log.Printf("Unable to send game-specific message to team %d: %v", dsConn.TeamId, err)
+			continue
+		}
+		dsConn.sentGameData = gameData
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestChooseGameData(t *testing.T) {
+	arena := setupTestArena(t)
+
+	// Without any choices configured, messages entered by hand should be left alone.
+	match := model.Match{RedGameData: "L", BlueGameData: "R"}
+	arena.chooseGameData(&match)
+	assert.Equal(t, "L", match.RedGameData)
+	assert.Equal(t, "R", match.BlueGameData)
+
+	arena.EventSettings.GameSpecificDataOptions = "LRL, RLR"
+	for i := 0; i < 10; i++ {
+		arena.chooseGameData(&match)
+		assert.Contains(t, []string{"LRL", "RLR"}, match.RedGameData)
+		assert.Equal(t, match.RedGameData, match.BlueGameData)
+	}
+
+	arena.EventSettings.GameSpecificDataPerAlliance = true
+	seenDifferent := false
+	for i := 0; i < 100; i++ {
+		arena.chooseGameData(&match)
+		assert.Contains(t, []string{"LRL", "RLR"}, match.RedGameData)
+		assert.Contains(t, []string{"LRL", "RLR"}, match.BlueGameData)
+		seenDifferent = seenDifferent || match.RedGameData != match.BlueGameData
+	}
+	assert.True(t, seenDifferent)
+
+	// Loading a match without messages should choose and save them, and hide them again.
+	arena.GameDataRevealed = true
+	arena.EventSettings.GameSpecificDataOptions = "Y"
+	match = model.Match{Type: "qualification", DisplayName: "1"}
+	assert.Nil(t, arena.Database.CreateMatch(&match))
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Equal(t, "Y", arena.CurrentMatch.RedGameData)
+	assert.Equal(t, "Y", arena.CurrentMatch.BlueGameData)
+	assert.False(t, arena.GameDataRevealed)
+	savedMatch, _ := arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, "Y", savedMatch.RedGameData)
+	assert.Equal(t, "Y", savedMatch.BlueGameData)
+
+	// Loading a match that already has messages should keep them.
+	arena.EventSettings.GameSpecificDataOptions = "Z"
+	assert.Nil(t, arena.LoadMatch(savedMatch))
+	assert.Equal(t, "Y", arena.CurrentMatch.RedGameData)
+	assert.Equal(t, "Y", arena.CurrentMatch.BlueGameData)
+	assert.Nil(t, arena.LoadMatch(&model.Match{Type: "test", RedGameData: "B"}))
+	assert.Equal(t, "B", arena.CurrentMatch.RedGameData)
+	assert.Equal(t, "", arena.CurrentMatch.BlueGameData)
+}
+
+func TestSetGameData(t *testing.T) {
+	arena := setupTestArena(t)
+
+	match := model.Match{Type: "qualification", DisplayName: "1"}
+	assert.Nil(t, arena.Database.CreateMatch(&match))
+	assert.Nil(t, arena.LoadMatch(&match))
+	assert.Nil(t, arena.SetGameData("LRL", "RRL"))
+	assert.Equal(t, "LRL", arena.CurrentMatch.RedGameData)
+	assert.Equal(t, "RRL", arena.CurrentMatch.BlueGameData)
+	savedMatch, _ := arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, "LRL", savedMatch.RedGameData)
+	assert.Equal(t, "RRL", savedMatch.BlueGameData)
+
+	err := arena.SetGameData(strings.Repeat("L", model.MaxGameSpecificDataLength+1), "")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "is longer than 64 characters")
+	}
+	assert.Equal(t, "LRL", arena.CurrentMatch.RedGameData)
+
+	arena.MatchState = AutoPeriod
+	err = arena.SetGameData("RRR", "RRR")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "once the match has started")
+	}
+	assert.Equal(t, "LRL", arena.CurrentMatch.RedGameData)
+}
+
+func TestUpdateGameData(t *testing.T) {
+	arena := setupTestArena(t)
+	game.MatchTiming.WarmupDurationSec = 3
+	arena.EventSettings.GameSpecificDataRevealSec = 5
+	arena.CurrentMatch.RedGameData = "LRL"
+	arena.CurrentMatch.BlueGameData = "RLR"
+	arena.AllianceStations["R2"].DsConn = &DriverStationConnection{TeamId: 254}
+	arena.AllianceStations["B3"].DsConn = &DriverStationConnection{TeamId: 1114}
+	red2, blue3 := arena.AllianceStations["R2"].DsConn, arena.AllianceStations["B3"].DsConn
+
+	// The messages should stay hidden before the match and until the reveal time.
+	arena.updateGameData(0)
+	assert.False(t, arena.GameDataRevealed)
+	arena.MatchState = WarmupPeriod
+	arena.updateGameData(2)
+	arena.MatchState = AutoPeriod
+	arena.updateGameData(7.9)
+	assert.False(t, arena.GameDataRevealed)
+	assert.Equal(t, "", red2.sentGameData)
+
+	arena.updateGameData(8)
+	assert.True(t, arena.GameDataRevealed)
+	assert.Equal(t, "LRL", red2.sentGameData)
+	assert.Equal(t, "RLR", blue3.sentGameData)
+
+	// Once revealed, the messages should stay up through the end of the match.
+	arena.MatchState = PostMatch
+	arena.updateGameData(0)
+	assert.Equal(t, "LRL", red2.sentGameData)
+
+	// Resetting for the next match should clear the messages from the driver stations.
+	assert.Nil(t, arena.ResetMatch())
+	arena.updateGameData(0)
+	assert.Equal(t, "", red2.sentGameData)
+	assert.Equal(t, "", blue3.sentGameData)
+}
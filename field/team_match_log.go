@@ -6,16 +6,24 @@
 package field
 
 import (
+	"encoding/csv"
 	"fmt"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/FRCTeam1987/crimson-arena/network"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
-const logsDir = "static/logs"
+const (
+	logsDir              = "static/logs"
+	logTimestampFormat   = "20060102150405"
+	logFilenameSeparator = "_Match_"
+)
 
 type TeamMatchLog struct {
 	logger     *log.Logger
@@ -23,6 +31,36 @@ type TeamMatchLog struct {
 	wifiStatus *network.TeamWifiStatus
 }
 
+// Identifying information for a log file, as encoded in its filename.
+type TeamMatchLogInfo struct {
+	Filename  string
+	StartedAt time.Time
+	MatchType string
+	MatchName string
+	TeamId    int
+}
+
+// A single line of a log file.
+type TeamMatchLogRow struct {
+	MatchTimeSec      float64
+	PacketType        int
+	DsLinked          bool
+	RadioLinked       bool
+	RioLinked         bool
+	RobotLinked       bool
+	Auto              bool
+	Enabled           bool
+	EmergencyStop     bool
+	BatteryVoltage    float64
+	MissedPacketCount int
+	DsRobotTripTimeMs int
+	RxRate            float64
+	TxRate            float64
+	SignalNoiseRatio  int
+	Brownout          bool
+	Message           string
+}
+
 // Creates a file to log to for the given match and team.
 func NewTeamMatchLog(teamId int, match *model.Match, wifiStatus *network.TeamWifiStatus) (*TeamMatchLog, error) {
 	err := os.MkdirAll(filepath.Join(model.BaseDir, logsDir), 0755)
@@ -31,23 +69,30 @@ func NewTeamMatchLog(teamId int, match *model.Match, wifiStatus *network.TeamWif
 	}
 
 	filename := fmt.Sprintf("%s/%s_%s_Match_%s_%d.csv", filepath.Join(model.BaseDir, logsDir),
-		time.Now().Format("20060102150405"), match.CapitalizedType(), match.DisplayName, teamId)
+		time.Now().Format(logTimestampFormat), match.CapitalizedType(), match.DisplayName, teamId)
 	logFile, err := os.Create(filename)
 	if err != nil {
 		return nil, err
 	}
 
 	log := TeamMatchLog{log.New(logFile, "", 0), logFile, wifiStatus}
-	log.logger.Println("matchTimeSec,packetType,teamId,allianceStation,dsLinked,radioLinked,rioLinked,robotLinked,auto,enabled," +
-		"emergencyStop,batteryVoltage,missedPacketCount,dsRobotTripTimeMs,rxRate,txRate,signalNoiseRatio")
+	log.logger.Println("matchTimeSec,packetType,teamId,allianceStation,dsLinked,radioLinked,rioLinked,robotLinked,auto," +
+		"enabled,emergencyStop,batteryVoltage,missedPacketCount,dsRobotTripTimeMs,rxRate,txRate,signalNoiseRatio," +
+		"brownout,canUtilization,cpuUsage,ramUsage,diskUsage,pdpTotalCurrent,radioSignalDb,radioBandwidthMbps,message")
 
 	return &log, nil
 }
 
 // Adds a line to the log when a packet is received.
 func (log *TeamMatchLog) LogDsPacket(matchTimeSec float64, packetType int, dsConn *DriverStationConnection) {
+	// Only record the log message on the line for the packet that carried it.
+	message := ""
+	if packetType == dsTcpLogMessageTag && len(dsConn.LogMessages) > 0 {
+		message = dsConn.LogMessages[len(dsConn.LogMessages)-1]
+	}
+
 	log.logger.Printf(
-		"%f,%d,%d,%s,%v,%v,%v,%v,%v,%v,%f,%d,%d,%f,%f,%d",
+		"%f,%d,%d,%s,%v,%v,%v,%v,%v,%v,%v,%f,%d,%d,%f,%f,%d,%v,%d,%d,%d,%d,%f,%d,%f,%s",
 		matchTimeSec,
 		packetType,
 		dsConn.TeamId,
@@ -65,9 +110,155 @@ func (log *TeamMatchLog) LogDsPacket(matchTimeSec float64, packetType int, dsCon
 		log.wifiStatus.RxRate,
 		log.wifiStatus.TxRate,
 		log.wifiStatus.SignalNoiseRatio,
+		dsConn.Brownout,
+		dsConn.CanUtilization,
+		dsConn.CpuUsage,
+		dsConn.RamUsage,
+		dsConn.DiskUsage,
+		dsConn.PdpTotalCurrent,
+		dsConn.RadioSignalDb,
+		dsConn.RadioBandwidthMbps,
+		quoteCsvField(message),
 	)
 }
 
 func (log *TeamMatchLog) Close() {
 	log.logFile.Close()
 }
+
+// Returns the given text as a single CSV field, quoting it if it contains any special characters.
+func quoteCsvField(text string) string {
+	if !strings.ContainsAny(text, ",\"\r\n") {
+		return text
+	}
+	return "\"" + strings.ReplaceAll(text, "\"", "\"\"") + "\""
+}
+
+// Returns the log files in the logs directory, newest first.
+func ListTeamMatchLogs() ([]TeamMatchLogInfo, error) {
+	paths, err := filepath.Glob(filepath.Join(model.BaseDir, logsDir, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make([]TeamMatchLogInfo, 0, len(paths))
+	for _, path := range paths {
+		info, err := GetTeamMatchLogInfo(filepath.Base(path))
+		if err != nil {
+			// Skip any files that weren't written by the arena.
+			continue
+		}
+		logs = append(logs, *info)
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		if !logs[i].StartedAt.Equal(logs[j].StartedAt) {
+			return logs[i].StartedAt.After(logs[j].StartedAt)
+		}
+		return logs[i].TeamId < logs[j].TeamId
+	})
+	return logs, nil
+}
+
+// Parses the match and team information out of the given log filename.
+func GetTeamMatchLogInfo(filename string) (*TeamMatchLogInfo, error) {
+	if filepath.Base(filename) != filename || !strings.HasSuffix(filename, ".csv") {
+		return nil, fmt.Errorf("invalid log filename '%s'", filename)
+	}
+	name := strings.TrimSuffix(filename, ".csv")
+	timestamp, rest, found := strings.Cut(name, "_")
+	lastSeparator := strings.LastIndex(rest, "_")
+	if !found || lastSeparator == -1 {
+		return nil, fmt.Errorf("invalid log filename '%s'", filename)
+	}
+	matchType, matchName, found := strings.Cut(rest[:lastSeparator]+"_", logFilenameSeparator)
+	if !found {
+		return nil, fmt.Errorf("invalid log filename '%s'", filename)
+	}
+	startedAt, err := time.ParseInLocation(logTimestampFormat, timestamp, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log filename '%s'", filename)
+	}
+	teamId, err := strconv.Atoi(rest[lastSeparator+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid log filename '%s'", filename)
+	}
+	if matchType == "" {
+		matchType = "Test"
+	}
+	return &TeamMatchLogInfo{
+		Filename:  filename,
+		StartedAt: startedAt,
+		MatchType: matchType,
+		MatchName: strings.TrimSuffix(matchName, "_"),
+		TeamId:    teamId,
+	}, nil
+}
+
+// Reads the rows of the given log file. Columns are looked up by name so that logs written before newer columns were
+// added can still be read.
+func ReadTeamMatchLog(filename string) ([]TeamMatchLogRow, error) {
+	if _, err := GetTeamMatchLogInfo(filename); err != nil {
+		return nil, err
+	}
+	logFile, err := os.Open(filepath.Join(model.BaseDir, logsDir, filename))
+	if err != nil {
+		return nil, err
+	}
+	defer logFile.Close()
+
+	reader := csv.NewReader(logFile)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return []TeamMatchLogRow{}, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	rows := make([]TeamMatchLogRow, 0, len(records)-1)
+	for _, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		floatField := func(name string) float64 {
+			value, _ := strconv.ParseFloat(field(name), 64)
+			return value
+		}
+		intField := func(name string) int {
+			value, _ := strconv.Atoi(field(name))
+			return value
+		}
+		boolField := func(name string) bool {
+			value, _ := strconv.ParseBool(field(name))
+			return value
+		}
+		rows = append(rows, TeamMatchLogRow{
+			MatchTimeSec:      floatField("matchTimeSec"),
+			PacketType:        intField("packetType"),
+			DsLinked:          boolField("dsLinked"),
+			RadioLinked:       boolField("radioLinked"),
+			RioLinked:         boolField("rioLinked"),
+			RobotLinked:       boolField("robotLinked"),
+			Auto:              boolField("auto"),
+			Enabled:           boolField("enabled"),
+			EmergencyStop:     boolField("emergencyStop"),
+			BatteryVoltage:    floatField("batteryVoltage"),
+			MissedPacketCount: intField("missedPacketCount"),
+			DsRobotTripTimeMs: intField("dsRobotTripTimeMs"),
+			RxRate:            floatField("rxRate"),
+			TxRate:            floatField("txRate"),
+			SignalNoiseRatio:  intField("signalNoiseRatio"),
+			Brownout:          boolField("brownout"),
+			Message:           field("message"),
+		})
+	}
+	return rows, nil
+}
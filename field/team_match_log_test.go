@@ -0,0 +1,114 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package field
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/FRCTeam1987/crimson-arena/network"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetTeamMatchLogInfo(t *testing.T) {
+	info, err := GetTeamMatchLogInfo("20260314093000_Qualification_Match_Q12_1987.csv")
+	if assert.Nil(t, err) {
+		assert.Equal(t, time.Date(2026, 3, 14, 9, 30, 0, 0, time.Local), info.StartedAt)
+		assert.Equal(t, "Qualification", info.MatchType)
+		assert.Equal(t, "Q12", info.MatchName)
+		assert.Equal(t, 1987, info.TeamId)
+	}
+
+	info, err = GetTeamMatchLogInfo("20260314093000__Match_Test Match_254.csv")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "Test", info.MatchType)
+		assert.Equal(t, "Test Match", info.MatchName)
+		assert.Equal(t, 254, info.TeamId)
+	}
+
+	for _, filename := range []string{
+		"../20260314093000_Qualification_Match_Q12_1987.csv",
+		"20260314093000_Qualification_Match_Q12_1987.txt",
+		"20260314093000_Qualification_Q12_1987.csv",
+		"2026_Qualification_Match_Q12_1987.csv",
+		"20260314093000_Qualification_Match_Q12_abc.csv",
+	} {
+		_, err = GetTeamMatchLogInfo(filename)
+		assert.NotNil(t, err, filename)
+	}
+}
+
+func TestTeamMatchLogReadBack(t *testing.T) {
+	model.BaseDir = ".."
+	match := model.Match{Type: "practice", DisplayName: "P3"}
+	teamMatchLog, err := NewTeamMatchLog(9871, &match, &network.TeamWifiStatus{RxRate: 1.5, TxRate: 2.5})
+	assert.Nil(t, err)
+	filename := filepath.Base(teamMatchLog.logFile.Name())
+	defer os.Remove(filepath.Join(model.BaseDir, logsDir, filename))
+
+	dsConn := &DriverStationConnection{TeamId: 9871, AllianceStation: "R1", DsLinked: true, RobotLinked: true,
+		Auto: true, BatteryVoltage: 12.5, DsRobotTripTimeMs: 7, MissedPacketCount: 3}
+	teamMatchLog.LogDsPacket(1.25, dsTcpRobotStatusTag, dsConn)
+	dsConn.LogMessages = []string{"Warning, \"brownout\""}
+	dsConn.Brownout = true
+	teamMatchLog.LogDsPacket(2.5, dsTcpLogMessageTag, dsConn)
+	teamMatchLog.Close()
+
+	rows, err := ReadTeamMatchLog(filename)
+	if assert.Nil(t, err) && assert.Equal(t, 2, len(rows)) {
+		assert.Equal(t, 1.25, rows[0].MatchTimeSec)
+		assert.True(t, rows[0].DsLinked)
+		assert.False(t, rows[0].RadioLinked)
+		assert.True(t, rows[0].RobotLinked)
+		assert.True(t, rows[0].Auto)
+		assert.Equal(t, 12.5, rows[0].BatteryVoltage)
+		assert.Equal(t, 7, rows[0].DsRobotTripTimeMs)
+		assert.Equal(t, 3, rows[0].MissedPacketCount)
+		assert.Equal(t, 1.5, rows[0].RxRate)
+		assert.Equal(t, 2.5, rows[0].TxRate)
+		assert.Equal(t, "", rows[0].Message)
+		assert.True(t, rows[1].Brownout)
+		assert.Equal(t, "Warning, \"brownout\"", rows[1].Message)
+	}
+
+	logs, err := ListTeamMatchLogs()
+	assert.Nil(t, err)
+	found := false
+	for _, info := range logs {
+		if info.Filename == filename {
+			found = true
+			assert.Equal(t, "Practice", info.MatchType)
+			assert.Equal(t, "P3", info.MatchName)
+			assert.Equal(t, 9871, info.TeamId)
+		}
+	}
+	assert.True(t, found)
+
+	_, err = ReadTeamMatchLog("../" + filename)
+	assert.NotNil(t, err)
+}
+
+func TestReadOlderTeamMatchLog(t *testing.T) {
+	model.BaseDir = ".."
+	filename := "20200101000000_Qualification_Match_Q1_9872.csv"
+	path := filepath.Join(model.BaseDir, logsDir, filename)
+	assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+	contents := "matchTimeSec,packetType,teamId,allianceStation,dsLinked,radioLinked,rioLinked,robotLinked,auto," +
+		"enabled,emergencyStop,batteryVoltage,missedPacketCount,dsRobotTripTimeMs,rxRate,txRate,signalNoiseRatio\n" +
+		"3.500000,22,9872,B2,true,true,true,true,false,true,false,11.800000,4,12,0.000000,0.000000,30\n"
+	assert.Nil(t, os.WriteFile(path, []byte(contents), 0644))
+	defer os.Remove(path)
+
+	rows, err := ReadTeamMatchLog(filename)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(rows)) {
+		assert.Equal(t, 3.5, rows[0].MatchTimeSec)
+		assert.True(t, rows[0].RioLinked)
+		assert.True(t, rows[0].Enabled)
+		assert.Equal(t, 11.8, rows[0].BatteryVoltage)
+		assert.Equal(t, 30, rows[0].SignalNoiseRatio)
+		assert.False(t, rows[0].Brownout)
+		assert.Equal(t, "", rows[0].Message)
+	}
+}
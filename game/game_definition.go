@@ -0,0 +1,129 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Model representing a declarative definition of a game's scoring elements, loaded from JSON or YAML.
+
+package game
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"regexp"
+)
+
+type Period string
+
+const (
+	AutoPeriod    Period = "auto"
+	TeleopPeriod  Period = "teleop"
+	EndgamePeriod Period = "endgame"
+)
+
+var Periods = []Period{AutoPeriod, TeleopPeriod, EndgamePeriod}
+
+// A single thing that an alliance can be credited with during a match, e.g. a game piece scored in a goal.
+type ScoringElement struct {
+	Id       string `yaml:"id"`
+	Name     string `yaml:"name"`
+	Period   Period `yaml:"period"`
+	Points   int    `yaml:"points"`
+	MaxCount int    `yaml:"maxCount"`
+}
+
+type GameDefinition struct {
+	Name            string           `yaml:"name"`
+	ScoringElements []ScoringElement `yaml:"scoringElements"`
+	FoulPoints      int              `yaml:"foulPoints"`
+	TechFoulPoints  int              `yaml:"techFoulPoints"`
+}
+
+var elementIdPattern = regexp.MustCompile("^[A-Za-z][A-Za-z0-9_]*$")
+
+// Element IDs that are reserved for the foul counts in the scoring API.
+var reservedElementIds = map[string]struct{}{"fouls": {}, "techFouls": {}}
+
+// The game definition in effect for the event; replaced when the event settings are loaded.
+var CurrentGame = DefaultGameDefinition()
+
+// Returns a generic game definition that simply tallies the points earned in each period.
+func DefaultGameDefinition() *GameDefinition {
+	return &GameDefinition{
+		Name: "Generic",
+		ScoringElements: []ScoringElement{
+			{Id: "auto", Name: "Auto", Period: AutoPeriod, Points: 1},
+			{Id: "teleop", Name: "Teleop", Period: TeleopPeriod, Points: 1},
+			{Id: "endgame", Name: "Endgame", Period: EndgamePeriod, Points: 1},
+		},
+		FoulPoints:     5,
+		TechFoulPoints: 12,
+	}
+}
+
+// Parses and validates a game definition from the given JSON or YAML document.
+func ParseGameDefinition(data []byte) (*GameDefinition, error) {
+	// YAML is a superset of JSON, so the same decoder handles both formats.
+	var definition GameDefinition
+	if err := yaml.Unmarshal(data, &definition); err != nil {
+		return nil, fmt.Errorf("could not parse game definition: %v", err)
+	}
+	if err := definition.Validate(); err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+// Returns an error if the game definition is missing required fields or is otherwise inconsistent.
+func (definition *GameDefinition) Validate() error {
+	if definition.Name == "" {
+		return fmt.Errorf("game definition must have a name")
+	}
+	if len(definition.ScoringElements) == 0 {
+		return fmt.Errorf("game definition must have at least one scoring element")
+	}
+	if definition.FoulPoints < 0 || definition.TechFoulPoints < 0 {
+		return fmt.Errorf("foul point values cannot be negative")
+	}
+	elementIds := make(map[string]struct{})
+	for _, element := range definition.ScoringElements {
+		if !elementIdPattern.MatchString(element.Id) {
+			return fmt.Errorf("scoring element ID %q must be alphanumeric and start with a letter", element.Id)
+		}
+		if _, ok := reservedElementIds[element.Id]; ok {
+			return fmt.Errorf("scoring element ID %q is reserved", element.Id)
+		}
+		if _, ok := elementIds[element.Id]; ok {
+			return fmt.Errorf("scoring element ID %q is used more than once", element.Id)
+		}
+		elementIds[element.Id] = struct{}{}
+		if element.Name == "" {
+			return fmt.Errorf("scoring element %q must have a name", element.Id)
+		}
+		if element.Period != AutoPeriod && element.Period != TeleopPeriod && element.Period != EndgamePeriod {
+			return fmt.Errorf("scoring element %q has invalid period %q", element.Id, element.Period)
+		}
+		if element.MaxCount < 0 {
+			return fmt.Errorf("scoring element %q cannot have a negative maximum count", element.Id)
+		}
+	}
+	return nil
+}
+
+// Returns the scoring element having the given ID, or nil if it doesn't exist.
+func (definition *GameDefinition) GetElement(id string) *ScoringElement {
+	for i := range definition.ScoringElements {
+		if definition.ScoringElements[i].Id == id {
+			return &definition.ScoringElements[i]
+		}
+	}
+	return nil
+}
+
+// Returns the scoring elements that are credited during the given period, in definition order.
+func (definition *GameDefinition) ElementsForPeriod(period Period) []ScoringElement {
+	var elements []ScoringElement
+	for _, element := range definition.ScoringElements {
+		if element.Period == period {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseGameDefinitionJson(t *testing.T) {
+	definition, err := ParseGameDefinition([]byte(`{"name": "Test Game", "scoringElements": [
+		{"id": "leave", "name": "Leave", "period": "auto", "points": 2, "maxCount": 3},
+		{"id": "note", "name": "Note", "period": "teleop", "points": 5}
+	]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "Test Game", definition.Name)
+	if assert.Equal(t, 2, len(definition.ScoringElements)) {
+		assert.Equal(t, ScoringElement{"leave", "Leave", AutoPeriod, 2, 3}, definition.ScoringElements[0])
+		assert.Equal(t, ScoringElement{"note", "Note", TeleopPeriod, 5, 0}, definition.ScoringElements[1])
+	}
+	assert.Equal(t, "note", definition.GetElement("note").Id)
+	assert.Nil(t, definition.GetElement("foo"))
+	assert.Equal(t, 1, len(definition.ElementsForPeriod(AutoPeriod)))
+	assert.Equal(t, 0, len(definition.ElementsForPeriod(EndgamePeriod)))
+}
+
+func TestParseGameDefinitionYaml(t *testing.T) {
+	definition, err := ParseGameDefinition([]byte(`
+name: Test Game
+scoringElements:
+  - id: park
+    name: Park
+    period: endgame
+    points: 3
+`))
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(definition.ScoringElements)) {
+		assert.Equal(t, ScoringElement{"park", "Park", EndgamePeriod, 3, 0}, definition.ScoringElements[0])
+	}
+}
+
+func TestParseGameDefinitionErrors(t *testing.T) {
+	_, err := ParseGameDefinition([]byte("{"))
+	assert.NotNil(t, err)
+
+	_, err = ParseGameDefinition([]byte(`{"scoringElements": [{"id": "a", "name": "A", "period": "auto"}]}`))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "game definition must have a name", err.Error())
+	}
+
+	_, err = ParseGameDefinition([]byte(`{"name": "Game"}`))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "game definition must have at least one scoring element", err.Error())
+	}
+
+	_, err = ParseGameDefinition([]byte(`{"name": "Game", "scoringElements": [
+		{"id": "a", "name": "A", "period": "auto"}, {"id": "a", "name": "A", "period": "teleop"}]}`))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "scoring element ID \"a\" is used more than once", err.Error())
+	}
+
+	_, err = ParseGameDefinition([]byte(`{"name": "Game", "scoringElements": [
+		{"id": "1a", "name": "A", "period": "auto"}]}`))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "scoring element ID \"1a\" must be alphanumeric and start with a letter", err.Error())
+	}
+
+	_, err = ParseGameDefinition([]byte(`{"name": "Game", "scoringElements": [
+		{"id": "a", "name": "A", "period": "overtime"}]}`))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "scoring element \"a\" has invalid period \"overtime\"", err.Error())
+	}
+}
+
+func TestScoreSummaryCustomGame(t *testing.T) {
+	CurrentGame = &GameDefinition{
+		Name: "Test Game",
+		ScoringElements: []ScoringElement{
+			{Id: "leave", Name: "Leave", Period: AutoPeriod, Points: 2},
+			{Id: "autoNote", Name: "Auto Note", Period: AutoPeriod, Points: 5},
+			{Id: "note", Name: "Note", Period: TeleopPeriod, Points: 2},
+			{Id: "climb", Name: "Climb", Period: EndgamePeriod, Points: 3},
+		},
+	}
+	defer func() { CurrentGame = DefaultGameDefinition() }()
+
+	score := &Score{ElementCounts: map[string]int{"leave": 3, "autoNote": 2, "note": 10, "climb": 2, "bogus": 100}}
+	summary := score.Summarize(&Score{})
+	assert.Equal(t, 16, summary.AutoPoints)
+	assert.Equal(t, 20, summary.TeleopPoints)
+	assert.Equal(t, 6, summary.EndgamePoints)
+	assert.Equal(t, 42, summary.Score)
+	assert.Equal(t, map[string]int{"leave": 6, "autoNote": 10, "note": 20, "climb": 6}, summary.ElementPoints)
+}
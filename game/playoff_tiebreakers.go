@@ -0,0 +1,107 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Model representing the configurable rules for deciding a playoff match in which both alliances scored the same.
+
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A criterion by which a tied playoff match is decided. Apart from FewerFoulPointsPlayoffTiebreaker, each tiebreaker
+// is a score component (a point total or a scoring element ID) and favors the alliance that scored more of it.
+type PlayoffTiebreaker string
+
+// Favors the alliance that committed fewer fouls, i.e. whose opponent was awarded fewer foul points.
+const FewerFoulPointsPlayoffTiebreaker PlayoffTiebreaker = "fewerFoulPoints"
+
+// Returns the tiebreakers used in FRC playoffs: fewer foul points, then more auto points, then more endgame points.
+func DefaultPlayoffTiebreakers() []PlayoffTiebreaker {
+	return []PlayoffTiebreaker{
+		FewerFoulPointsPlayoffTiebreaker,
+		PlayoffTiebreaker(AutoPointsComponent),
+		PlayoffTiebreaker(EndgamePointsComponent),
+	}
+}
+
+// Parses a comma-separated list of playoff tiebreakers, e.g. "fewerFoulPoints, autoPoints". An empty list is returned
+// as such rather than nil, since it means that tied playoff matches are replayed.
+func ParsePlayoffTiebreakers(value string) []PlayoffTiebreaker {
+	tiebreakers := []PlayoffTiebreaker{}
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			tiebreakers = append(tiebreakers, PlayoffTiebreaker(field))
+		}
+	}
+	return tiebreakers
+}
+
+// Returns the tiebreakers in the comma-separated form accepted by ParsePlayoffTiebreakers.
+func FormatPlayoffTiebreakers(tiebreakers []PlayoffTiebreaker) string {
+	fields := make([]string, len(tiebreakers))
+	for i, tiebreaker := range tiebreakers {
+		fields[i] = string(tiebreaker)
+	}
+	return strings.Join(fields, ", ")
+}
+
+// Returns an error if any of the tiebreakers is repeated or doesn't refer to a score component of the given game.
+func ValidatePlayoffTiebreakers(tiebreakers []PlayoffTiebreaker, definition *GameDefinition) error {
+	seenTiebreakers := make(map[PlayoffTiebreaker]struct{})
+	for _, tiebreaker := range tiebreakers {
+		switch tiebreaker {
+		case FewerFoulPointsPlayoffTiebreaker, AutoPointsComponent, TeleopPointsComponent, EndgamePointsComponent:
+		default:
+			if definition.GetElement(string(tiebreaker)) == nil {
+				return fmt.Errorf("invalid playoff tiebreaker %q", tiebreaker)
+			}
+		}
+		if _, ok := seenTiebreakers[tiebreaker]; ok {
+			return fmt.Errorf("playoff tiebreaker %q is used more than once", tiebreaker)
+		}
+		seenTiebreakers[tiebreaker] = struct{}{}
+	}
+	return nil
+}
+
+// Decides a match in which both alliances scored the same by applying each of the given tiebreakers in turn. Returns
+// the resulting status along with the tiebreaker that decided it, or TieMatch and an empty tiebreaker if the alliances
+// are still level after all of them.
+func BreakPlayoffTie(
+	redScoreSummary, blueScoreSummary *ScoreSummary, tiebreakers []PlayoffTiebreaker,
+) (MatchStatus, PlayoffTiebreaker) {
+	for _, tiebreaker := range tiebreakers {
+		var status MatchStatus
+		if tiebreaker == FewerFoulPointsPlayoffTiebreaker {
+			// Each alliance's foul points were committed by its opponent, so the cleaner alliance has more of them.
+			status = comparePoints(redScoreSummary.FoulPoints, blueScoreSummary.FoulPoints)
+		} else {
+			status = comparePoints(
+				redScoreSummary.ComponentValue(string(tiebreaker)), blueScoreSummary.ComponentValue(string(tiebreaker)),
+			)
+		}
+		if status != TieMatch {
+			return status, tiebreaker
+		}
+	}
+	return TieMatch, ""
+}
+
+// Returns a human-readable description of the tiebreaker, naming scoring elements as they appear in the given game.
+func (tiebreaker PlayoffTiebreaker) Description(definition *GameDefinition) string {
+	switch tiebreaker {
+	case FewerFoulPointsPlayoffTiebreaker:
+		return "Fewer foul points"
+	case AutoPointsComponent:
+		return "More auto points"
+	case TeleopPointsComponent:
+		return "More teleop points"
+	case EndgamePointsComponent:
+		return "More endgame points"
+	}
+	if element := definition.GetElement(string(tiebreaker)); element != nil {
+		return fmt.Sprintf("More %s points", element.Name)
+	}
+	return fmt.Sprintf("More %s points", tiebreaker)
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBreakPlayoffTie(t *testing.T) {
+	redScoreSummary := &ScoreSummary{AutoPoints: 10, EndgamePoints: 5, FoulPoints: 12, Score: 50}
+	blueScoreSummary := &ScoreSummary{AutoPoints: 15, EndgamePoints: 0, FoulPoints: 12, Score: 50}
+	tiebreakers := DefaultPlayoffTiebreakers()
+
+	// Equal foul points fall through to auto.
+	status, tiebreaker := BreakPlayoffTie(redScoreSummary, blueScoreSummary, tiebreakers)
+	assert.Equal(t, BlueWonMatch, status)
+	assert.Equal(t, PlayoffTiebreaker(AutoPointsComponent), tiebreaker)
+
+	// Blue committed fewer fouls since red was awarded more foul points.
+	redScoreSummary.FoulPoints = 17
+	status, tiebreaker = BreakPlayoffTie(redScoreSummary, blueScoreSummary, tiebreakers)
+	assert.Equal(t, RedWonMatch, status)
+	assert.Equal(t, FewerFoulPointsPlayoffTiebreaker, tiebreaker)
+
+	redScoreSummary.FoulPoints = 12
+	redScoreSummary.AutoPoints = 15
+	status, tiebreaker = BreakPlayoffTie(redScoreSummary, blueScoreSummary, tiebreakers)
+	assert.Equal(t, RedWonMatch, status)
+	assert.Equal(t, PlayoffTiebreaker(EndgamePointsComponent), tiebreaker)
+
+	// Scoring elements can be used as tiebreakers.
+	redScoreSummary.ElementPoints = map[string]int{"teleop": 3}
+	blueScoreSummary.ElementPoints = map[string]int{"teleop": 4}
+	status, tiebreaker = BreakPlayoffTie(redScoreSummary, blueScoreSummary, []PlayoffTiebreaker{"teleop"})
+	assert.Equal(t, BlueWonMatch, status)
+	assert.Equal(t, PlayoffTiebreaker("teleop"), tiebreaker)
+
+	status, tiebreaker = BreakPlayoffTie(redScoreSummary, blueScoreSummary, []PlayoffTiebreaker{"teleopPoints"})
+	assert.Equal(t, TieMatch, status)
+	assert.Equal(t, PlayoffTiebreaker(""), tiebreaker)
+	status, _ = BreakPlayoffTie(redScoreSummary, blueScoreSummary, nil)
+	assert.Equal(t, TieMatch, status)
+}
+
+func TestParsePlayoffTiebreakers(t *testing.T) {
+	tiebreakers := ParsePlayoffTiebreakers(" fewerFoulPoints,autoPoints , endgamePoints")
+	assert.Equal(t, DefaultPlayoffTiebreakers(), tiebreakers)
+	assert.Equal(t, "fewerFoulPoints, autoPoints, endgamePoints", FormatPlayoffTiebreakers(tiebreakers))
+	assert.Equal(t, []PlayoffTiebreaker{}, ParsePlayoffTiebreakers(" "))
+}
+
+func TestValidatePlayoffTiebreakers(t *testing.T) {
+	definition := DefaultGameDefinition()
+	assert.Nil(t, ValidatePlayoffTiebreakers(DefaultPlayoffTiebreakers(), definition))
+	assert.Nil(t, ValidatePlayoffTiebreakers([]PlayoffTiebreaker{"teleopPoints", "endgame"}, definition))
+	assert.Nil(t, ValidatePlayoffTiebreakers(nil, definition))
+	assert.EqualError(
+		t,
+		ValidatePlayoffTiebreakers([]PlayoffTiebreaker{"matchPoints"}, definition),
+		`invalid playoff tiebreaker "matchPoints"`,
+	)
+	assert.EqualError(
+		t,
+		ValidatePlayoffTiebreakers([]PlayoffTiebreaker{"autoPoints", "autoPoints"}, definition),
+		`playoff tiebreaker "autoPoints" is used more than once`,
+	)
+}
+
+func TestPlayoffTiebreakerDescription(t *testing.T) {
+	definition := DefaultGameDefinition()
+	assert.Equal(t, "Fewer foul points", FewerFoulPointsPlayoffTiebreaker.Description(definition))
+	assert.Equal(t, "More auto points", PlayoffTiebreaker("autoPoints").Description(definition))
+	element := definition.GetElement("endgame")
+	assert.Equal(t, "More "+element.Name+" points", PlayoffTiebreaker("endgame").Description(definition))
+}
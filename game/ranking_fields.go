@@ -5,18 +5,17 @@
 
 package game
 
-import "math/rand"
-
 type RankingFields struct {
-	RankingPoints int
-	AutoPoints    int
-	EndgamePoints int
-	TeleopPoints  int
-	Random        float64
-	Wins          int
-	Losses        int
-	Ties          int
-	Played        int
+	RankingPoints     int
+	AutoPoints        int
+	EndgamePoints     int
+	TeleopPoints      int
+	Random            float64
+	Wins              int
+	Losses            int
+	Ties              int
+	Played            int
+	Disqualifications int
 }
 
 type Ranking struct {
@@ -28,22 +27,32 @@ type Ranking struct {
 
 type Rankings []Ranking
 
-func (fields *RankingFields) AddScoreSummary(ownScore *ScoreSummary, opponentScore *ScoreSummary) {
+func (fields *RankingFields) AddScoreSummary(ownScore *ScoreSummary, opponentScore *ScoreSummary, disqualified bool) {
 	fields.Played += 1
 
-	// Store a random value to be used as the last tiebreaker if necessary.
-	fields.Random = rand.Float64()
+	if disqualified {
+		// A disqualified team earns no ranking points or tiebreaker points for the match.
+		fields.Disqualifications += 1
+		return
+	}
 
 	// Assign ranking points and wins/losses/ties.
+	rules := CurrentRankingRules
 	if ownScore.Score > opponentScore.Score {
-		fields.RankingPoints += 2
+		fields.RankingPoints += rules.WinRankingPoints
 		fields.Wins += 1
 	} else if ownScore.Score == opponentScore.Score {
-		fields.RankingPoints += 1
+		fields.RankingPoints += rules.TieRankingPoints
 		fields.Ties += 1
 	} else {
+		fields.RankingPoints += rules.LossRankingPoints
 		fields.Losses += 1
 	}
+	for _, bonus := range rules.BonusRankingPoints {
+		if ownScore.ComponentValue(bonus.Component) >= bonus.Threshold {
+			fields.RankingPoints += bonus.RankingPoints
+		}
+	}
 
 	// Assign tiebreaker points.
 	fields.AutoPoints += ownScore.AutoPoints
@@ -51,6 +60,19 @@ func (fields *RankingFields) AddScoreSummary(ownScore *ScoreSummary, opponentSco
 	fields.TeleopPoints += ownScore.TeleopPoints
 }
 
+// Returns the pseudo-random value in [0, 1) used as the last tiebreaker for the given team, which is derived from the
+// event's seed so that the rankings come out the same every time they are calculated.
+func RandomTiebreakerValue(seed int64, teamId int) float64 {
+	// Mix the seed and team number using the SplitMix64 finalizer so that adjacent team numbers get unrelated values.
+	value := uint64(seed) + uint64(teamId)*0x9e3779b97f4a7c15
+	value = (value ^ (value >> 30)) * 0xbf58476d1ce4e5b9
+	value = (value ^ (value >> 27)) * 0x94d049bb133111eb
+	value ^= value >> 31
+
+	// Use the top 53 bits so that every value is exactly representable as a float64.
+	return float64(value>>11) / (1 << 53)
+}
+
 // Helper function to implement the required interface for Sort.
 func (rankings Rankings) Len() int {
 	return len(rankings)
@@ -62,19 +84,14 @@ func (rankings Rankings) Less(i, j int) bool {
 	b := rankings[j]
 
 	// Use cross-multiplication to keep it in integer math.
-	if a.RankingPoints*b.Played == b.RankingPoints*a.Played {
-		if a.AutoPoints*b.Played == b.AutoPoints*a.Played {
-			if a.EndgamePoints*b.Played == b.EndgamePoints*a.Played {
-				if a.TeleopPoints*b.Played == b.TeleopPoints*a.Played {
-					return a.Random > b.Random
-				}
-				return a.TeleopPoints*b.Played > b.TeleopPoints*a.Played
-			}
-			return a.EndgamePoints*b.Played > b.EndgamePoints*a.Played
+	for _, tiebreaker := range CurrentRankingRules.Tiebreakers {
+		aValue := a.TiebreakerValue(tiebreaker) * b.Played
+		bValue := b.TiebreakerValue(tiebreaker) * a.Played
+		if aValue != bValue {
+			return aValue > bValue
 		}
-		return a.AutoPoints*b.Played > b.AutoPoints*a.Played
 	}
-	return a.RankingPoints*b.Played > b.RankingPoints*a.Played
+	return a.Random > b.Random
 }
 
 // Helper function to implement the required interface for Sort.
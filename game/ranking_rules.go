@@ -0,0 +1,218 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Model representing the configurable rules for awarding ranking points and breaking ties in the rankings.
+
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A ranking field by which teams with equal standing up to that point are ordered, averaged over matches played.
+type Tiebreaker string
+
+const (
+	RankingPointsTiebreaker Tiebreaker = "rankingPoints"
+	AutoPointsTiebreaker    Tiebreaker = "autoPoints"
+	TeleopPointsTiebreaker  Tiebreaker = "teleopPoints"
+	EndgamePointsTiebreaker Tiebreaker = "endgamePoints"
+	WinsTiebreaker          Tiebreaker = "wins"
+)
+
+var tiebreakerNames = map[Tiebreaker]string{
+	RankingPointsTiebreaker: "RP",
+	AutoPointsTiebreaker:    "Auto",
+	TeleopPointsTiebreaker:  "Teleop",
+	EndgamePointsTiebreaker: "Endgame",
+	WinsTiebreaker:          "Wins",
+}
+
+// Score summary values, other than scoring element IDs, that a bonus ranking point threshold can be based on.
+const (
+	AutoPointsComponent    = "autoPoints"
+	TeleopPointsComponent  = "teleopPoints"
+	EndgamePointsComponent = "endgamePoints"
+	MatchPointsComponent   = "matchPoints"
+)
+
+// An extra ranking point awarded to each team on an alliance whose score component meets the given threshold.
+type BonusRankingPoint struct {
+	Name          string
+	Component     string
+	Threshold     int
+	RankingPoints int
+}
+
+type RankingRules struct {
+	WinRankingPoints   int
+	TieRankingPoints   int
+	LossRankingPoints  int
+	Tiebreakers        []Tiebreaker
+	BonusRankingPoints []BonusRankingPoint
+}
+
+// The ranking rules in effect for the event; replaced when the event settings are loaded.
+var CurrentRankingRules = DefaultRankingRules()
+
+// Returns the traditional rules of 2/1/0 ranking points for a win/tie/loss, broken by auto, endgame and teleop points.
+func DefaultRankingRules() *RankingRules {
+	return &RankingRules{
+		WinRankingPoints:  2,
+		TieRankingPoints:  1,
+		LossRankingPoints: 0,
+		Tiebreakers: []Tiebreaker{
+			RankingPointsTiebreaker, AutoPointsTiebreaker, EndgamePointsTiebreaker, TeleopPointsTiebreaker,
+		},
+	}
+}
+
+// Parses a comma-separated list of tiebreakers, e.g. "rankingPoints, autoPoints".
+func ParseTiebreakers(value string) []Tiebreaker {
+	var tiebreakers []Tiebreaker
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			tiebreakers = append(tiebreakers, Tiebreaker(field))
+		}
+	}
+	return tiebreakers
+}
+
+// Parses bonus ranking points given one per line as "<name>, <score component>, <threshold>, <ranking points>".
+func ParseBonusRankingPoints(value string) ([]BonusRankingPoint, error) {
+	var bonuses []BonusRankingPoint
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("bonus ranking point %q must have a name, score component, threshold and value", line)
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		threshold, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("bonus ranking point %q has invalid threshold %q", fields[0], fields[2])
+		}
+		rankingPoints, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("bonus ranking point %q has invalid value %q", fields[0], fields[3])
+		}
+		bonuses = append(
+			bonuses,
+			BonusRankingPoint{Name: fields[0], Component: fields[1], Threshold: threshold, RankingPoints: rankingPoints},
+		)
+	}
+	return bonuses, nil
+}
+
+// Returns the tiebreakers in the comma-separated form accepted by ParseTiebreakers.
+func (rules *RankingRules) FormatTiebreakers() string {
+	tiebreakers := make([]string, len(rules.Tiebreakers))
+	for i, tiebreaker := range rules.Tiebreakers {
+		tiebreakers[i] = string(tiebreaker)
+	}
+	return strings.Join(tiebreakers, ", ")
+}
+
+// Returns the bonus ranking points in the line-based form accepted by ParseBonusRankingPoints.
+func (rules *RankingRules) FormatBonusRankingPoints() string {
+	lines := make([]string, len(rules.BonusRankingPoints))
+	for i, bonus := range rules.BonusRankingPoints {
+		lines[i] = fmt.Sprintf("%s, %s, %d, %d", bonus.Name, bonus.Component, bonus.Threshold, bonus.RankingPoints)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Returns the tiebreakers to show as columns in rankings reports, which always lead with the ranking points and leave
+// out the wins since those are shown as part of the win-loss-tie record.
+func (rules *RankingRules) DisplayTiebreakers() []Tiebreaker {
+	tiebreakers := []Tiebreaker{RankingPointsTiebreaker}
+	for _, tiebreaker := range rules.Tiebreakers {
+		if tiebreaker != RankingPointsTiebreaker && tiebreaker != WinsTiebreaker {
+			tiebreakers = append(tiebreakers, tiebreaker)
+		}
+	}
+	return tiebreakers
+}
+
+// Returns an error if the rules reference unknown tiebreakers or score components of the given game.
+func (rules *RankingRules) Validate(definition *GameDefinition) error {
+	if rules.WinRankingPoints < 0 || rules.TieRankingPoints < 0 || rules.LossRankingPoints < 0 {
+		return fmt.Errorf("ranking point values cannot be negative")
+	}
+	if len(rules.Tiebreakers) == 0 {
+		return fmt.Errorf("at least one tiebreaker must be specified")
+	}
+	seenTiebreakers := make(map[Tiebreaker]struct{})
+	for _, tiebreaker := range rules.Tiebreakers {
+		if _, ok := tiebreakerNames[tiebreaker]; !ok {
+			return fmt.Errorf("invalid tiebreaker %q", tiebreaker)
+		}
+		if _, ok := seenTiebreakers[tiebreaker]; ok {
+			return fmt.Errorf("tiebreaker %q is used more than once", tiebreaker)
+		}
+		seenTiebreakers[tiebreaker] = struct{}{}
+	}
+	for _, bonus := range rules.BonusRankingPoints {
+		if bonus.Name == "" {
+			return fmt.Errorf("bonus ranking point must have a name")
+		}
+		switch bonus.Component {
+		case AutoPointsComponent, TeleopPointsComponent, EndgamePointsComponent, MatchPointsComponent:
+		default:
+			if definition.GetElement(bonus.Component) == nil {
+				return fmt.Errorf("bonus ranking point %q has invalid score component %q", bonus.Name, bonus.Component)
+			}
+		}
+		if bonus.RankingPoints <= 0 {
+			return fmt.Errorf("bonus ranking point %q must be worth at least one ranking point", bonus.Name)
+		}
+	}
+	return nil
+}
+
+// Returns the short name of the tiebreaker for use as a column heading.
+func (tiebreaker Tiebreaker) Name() string {
+	return tiebreakerNames[tiebreaker]
+}
+
+// Returns the name of the RankingFields field holding the value for the tiebreaker.
+func (tiebreaker Tiebreaker) FieldName() string {
+	return strings.ToUpper(string(tiebreaker[:1])) + string(tiebreaker[1:])
+}
+
+// Returns the value of the given tiebreaker, summed over all matches played.
+func (fields RankingFields) TiebreakerValue(tiebreaker Tiebreaker) int {
+	switch tiebreaker {
+	case RankingPointsTiebreaker:
+		return fields.RankingPoints
+	case AutoPointsTiebreaker:
+		return fields.AutoPoints
+	case TeleopPointsTiebreaker:
+		return fields.TeleopPoints
+	case EndgamePointsTiebreaker:
+		return fields.EndgamePoints
+	case WinsTiebreaker:
+		return fields.Wins
+	}
+	return 0
+}
+
+// Returns the value of the given score component, which is either one of the point totals or a scoring element ID.
+func (summary *ScoreSummary) ComponentValue(component string) int {
+	switch component {
+	case AutoPointsComponent:
+		return summary.AutoPoints
+	case TeleopPointsComponent:
+		return summary.TeleopPoints
+	case EndgamePointsComponent:
+		return summary.EndgamePoints
+	case MatchPointsComponent:
+		return summary.Score
+	}
+	return summary.ElementPoints[component]
+}
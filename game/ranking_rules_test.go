@@ -0,0 +1,125 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+)
+
+func TestAddScoreSummaryCustomRules(t *testing.T) {
+	CurrentRankingRules = &RankingRules{
+		WinRankingPoints:  3,
+		TieRankingPoints:  1,
+		LossRankingPoints: 1,
+		Tiebreakers:       []Tiebreaker{RankingPointsTiebreaker},
+		BonusRankingPoints: []BonusRankingPoint{
+			{Name: "Auto Bonus", Component: AutoPointsComponent, Threshold: 40, RankingPoints: 1},
+			{Name: "Endgame Bonus", Component: "endgame", Threshold: 30, RankingPoints: 2},
+		},
+	}
+	defer func() { CurrentRankingRules = DefaultRankingRules() }()
+
+	redSummary := TestScore1().Summarize(TestScore2())
+	blueSummary := TestScore2().Summarize(TestScore1())
+	rankingFields := RankingFields{}
+	rankingFields.AddScoreSummary(redSummary, blueSummary, false)
+	assert.Equal(t, 6, rankingFields.RankingPoints)
+	rankingFields.AddScoreSummary(blueSummary, redSummary, false)
+	assert.Equal(t, 7, rankingFields.RankingPoints)
+	rankingFields.AddScoreSummary(blueSummary, blueSummary, false)
+	assert.Equal(t, 8, rankingFields.RankingPoints)
+	rankingFields.AddScoreSummary(redSummary, blueSummary, true)
+	assert.Equal(t, 8, rankingFields.RankingPoints)
+}
+
+func TestSortRankingsCustomTiebreakers(t *testing.T) {
+	CurrentRankingRules = DefaultRankingRules()
+	CurrentRankingRules.Tiebreakers = []Tiebreaker{WinsTiebreaker, TeleopPointsTiebreaker}
+	defer func() { CurrentRankingRules = DefaultRankingRules() }()
+
+	rankings := Rankings{
+		{1, 0, 0, RankingFields{50, 50, 50, 50, 0.50, 3, 2, 1, 10, 0}},
+		{2, 0, 0, RankingFields{10, 10, 10, 60, 0.50, 3, 2, 1, 10, 0}},
+		{3, 0, 0, RankingFields{60, 60, 60, 60, 0.50, 2, 2, 1, 10, 0}},
+		{4, 0, 0, RankingFields{50, 50, 50, 50, 0.60, 3, 2, 1, 10, 0}},
+	}
+	sort.Sort(rankings)
+	assert.Equal(t, 2, rankings[0].TeamId)
+	assert.Equal(t, 4, rankings[1].TeamId)
+	assert.Equal(t, 1, rankings[2].TeamId)
+	assert.Equal(t, 3, rankings[3].TeamId)
+}
+
+func TestRankingRulesValidate(t *testing.T) {
+	definition := DefaultGameDefinition()
+	assert.Nil(t, DefaultRankingRules().Validate(definition))
+
+	rules := DefaultRankingRules()
+	rules.BonusRankingPoints = []BonusRankingPoint{{Name: "Bonus", Component: "teleop", Threshold: 5, RankingPoints: 1}}
+	assert.Nil(t, rules.Validate(definition))
+
+	rules = DefaultRankingRules()
+	rules.TieRankingPoints = -1
+	assert.EqualError(t, rules.Validate(definition), "ranking point values cannot be negative")
+
+	rules = DefaultRankingRules()
+	rules.Tiebreakers = nil
+	assert.EqualError(t, rules.Validate(definition), "at least one tiebreaker must be specified")
+
+	rules.Tiebreakers = ParseTiebreakers("rankingPoints, coopertition")
+	assert.EqualError(t, rules.Validate(definition), "invalid tiebreaker \"coopertition\"")
+
+	rules.Tiebreakers = ParseTiebreakers("rankingPoints,wins,rankingPoints")
+	assert.EqualError(t, rules.Validate(definition), "tiebreaker \"rankingPoints\" is used more than once")
+
+	rules = DefaultRankingRules()
+	rules.BonusRankingPoints = []BonusRankingPoint{{Name: "Bonus", Component: "notes", Threshold: 5, RankingPoints: 1}}
+	assert.EqualError(
+		t, rules.Validate(definition), "bonus ranking point \"Bonus\" has invalid score component \"notes\"",
+	)
+
+	rules.BonusRankingPoints[0].Component = MatchPointsComponent
+	rules.BonusRankingPoints[0].RankingPoints = 0
+	assert.EqualError(
+		t, rules.Validate(definition), "bonus ranking point \"Bonus\" must be worth at least one ranking point",
+	)
+}
+
+func TestTiebreakerFieldName(t *testing.T) {
+	assert.Equal(t, "RankingPoints", RankingPointsTiebreaker.FieldName())
+	assert.Equal(t, "Wins", WinsTiebreaker.FieldName())
+	assert.Equal(t, 3, TestRanking1().TiebreakerValue(WinsTiebreaker))
+	assert.Equal(t, 554, TestRanking1().TiebreakerValue(TeleopPointsTiebreaker))
+}
+
+func TestParseBonusRankingPoints(t *testing.T) {
+	bonuses, err := ParseBonusRankingPoints("Melody, note, 18, 1\n\n  Ensemble,endgamePoints,10,2  \n")
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		[]BonusRankingPoint{
+			{Name: "Melody", Component: "note", Threshold: 18, RankingPoints: 1},
+			{Name: "Ensemble", Component: "endgamePoints", Threshold: 10, RankingPoints: 2},
+		},
+		bonuses,
+	)
+	rules := &RankingRules{BonusRankingPoints: bonuses, Tiebreakers: ParseTiebreakers("rankingPoints,wins")}
+	assert.Equal(t, "Melody, note, 18, 1\nEnsemble, endgamePoints, 10, 2", rules.FormatBonusRankingPoints())
+	assert.Equal(t, "rankingPoints, wins", rules.FormatTiebreakers())
+	assert.Equal(t, []Tiebreaker{RankingPointsTiebreaker}, rules.DisplayTiebreakers())
+	rules.Tiebreakers = ParseTiebreakers("wins, teleopPoints, rankingPoints, autoPoints")
+	assert.Equal(
+		t,
+		[]Tiebreaker{RankingPointsTiebreaker, TeleopPointsTiebreaker, AutoPointsTiebreaker},
+		rules.DisplayTiebreakers(),
+	)
+
+	_, err = ParseBonusRankingPoints("Melody, note, 18")
+	assert.EqualError(
+		t, err, "bonus ranking point \"Melody, note, 18\" must have a name, score component, threshold and value",
+	)
+	_, err = ParseBonusRankingPoints("Melody, note, lots, 1")
+	assert.EqualError(t, err, "bonus ranking point \"Melody\" has invalid threshold \"lots\"")
+}
@@ -5,31 +5,101 @@
 
 package game
 
+import "encoding/json"
+
+// Holds the number of times the alliance has been credited with each scoring element of the current game
+// definition, keyed by element ID, along with the fouls the alliance has committed.
 type Score struct {
-	AutoPoints    int
-	TeleopPoints  int
-	EndgamePoints int
+	ElementCounts map[string]int
+	Fouls         int
+	TechFouls     int
+	PlayoffDq     bool
+}
+
+// Decodes a score, converting records saved before scores were driven by the game definition. Those held the points
+// earned in each period, which map directly onto the counts of the generic definition's per-period elements.
+func (score *Score) UnmarshalJSON(data []byte) error {
+	type scoreFields Score
+	var fields struct {
+		scoreFields
+		AutoPoints    *int
+		TeleopPoints  *int
+		EndgamePoints *int
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*score = Score(fields.scoreFields)
+
+	legacyCounts := map[string]*int{
+		"auto": fields.AutoPoints, "teleop": fields.TeleopPoints, "endgame": fields.EndgamePoints,
+	}
+	for elementId, points := range legacyCounts {
+		if points != nil && score.ElementCounts[elementId] == 0 {
+			score.SetCount(elementId, *points)
+		}
+	}
+	return nil
+}
+
+// Returns the number of times the given scoring element has been credited.
+func (score *Score) Count(elementId string) int {
+	return score.ElementCounts[elementId]
 }
 
-// Calculates and returns the summary fields used for ranking and display.
-func (score *Score) Summarize() *ScoreSummary {
+// Sets the number of times the given scoring element has been credited.
+func (score *Score) SetCount(elementId string, count int) {
+	if score.ElementCounts == nil {
+		score.ElementCounts = make(map[string]int)
+	}
+	score.ElementCounts[elementId] = count
+}
+
+// Calculates and returns the summary fields used for ranking and display. Fouls committed by the opponent are
+// credited to this alliance.
+func (score *Score) Summarize(opponentScore *Score) *ScoreSummary {
 	summary := new(ScoreSummary)
+	summary.ElementPoints = make(map[string]int)
 
-	summary.AutoPoints = score.AutoPoints
-	summary.TeleopPoints = score.TeleopPoints
-	summary.EndgamePoints = score.EndgamePoints
-	summary.Score = summary.AutoPoints + summary.TeleopPoints + summary.EndgamePoints
+	// A disqualified alliance earns no points in a playoff match.
+	if score.PlayoffDq {
+		summary.PlayoffDq = true
+		return summary
+	}
+
+	for _, element := range CurrentGame.ScoringElements {
+		points := score.Count(element.Id) * element.Points
+		summary.ElementPoints[element.Id] = points
+		switch element.Period {
+		case AutoPeriod:
+			summary.AutoPoints += points
+		case TeleopPeriod:
+			summary.TeleopPoints += points
+		case EndgamePeriod:
+			summary.EndgamePoints += points
+		}
+	}
+	summary.FoulPoints = opponentScore.Fouls*CurrentGame.FoulPoints + opponentScore.TechFouls*CurrentGame.TechFoulPoints
+	summary.Score = summary.AutoPoints + summary.TeleopPoints + summary.EndgamePoints + summary.FoulPoints
 
 	return summary
 }
 
 // Returns true if and only if all fields of the two scores are equal.
 func (score *Score) Equals(other *Score) bool {
-	if score.AutoPoints != other.AutoPoints ||
-		score.TeleopPoints != other.TeleopPoints ||
-		score.EndgamePoints != other.EndgamePoints {
+	if score.Fouls != other.Fouls || score.TechFouls != other.TechFouls || score.PlayoffDq != other.PlayoffDq {
 		return false
 	}
+	for elementId, count := range score.ElementCounts {
+		if other.Count(elementId) != count {
+			return false
+		}
+	}
+	for elementId, count := range other.ElementCounts {
+		if score.Count(elementId) != count {
+			return false
+		}
+	}
 
 	return true
 }
@@ -9,7 +9,10 @@ type ScoreSummary struct {
 	AutoPoints    int
 	TeleopPoints  int
 	EndgamePoints int
+	FoulPoints    int
 	Score         int
+	ElementPoints map[string]int
+	PlayoffDq     bool
 }
 
 type MatchStatus string
@@ -23,6 +26,13 @@ const (
 
 // Determines the winner of the match given the score summaries for both alliances.
 func DetermineMatchStatus(redScoreSummary, blueScoreSummary *ScoreSummary) MatchStatus {
+	// A disqualified alliance forfeits the match unless both alliances were disqualified.
+	if redScoreSummary.PlayoffDq && !blueScoreSummary.PlayoffDq {
+		return BlueWonMatch
+	}
+	if blueScoreSummary.PlayoffDq && !redScoreSummary.PlayoffDq {
+		return RedWonMatch
+	}
 	return comparePoints(redScoreSummary.Score, blueScoreSummary.Score)
 }
 
@@ -18,4 +18,15 @@ func TestScoreSummaryDetermineMatchStatus(t *testing.T) {
 
 	blueScoreSummary.Score = 12
 	assert.Equal(t, BlueWonMatch, DetermineMatchStatus(redScoreSummary, blueScoreSummary))
+
+	// Check that a disqualified alliance forfeits the match regardless of score.
+	redScoreSummary.PlayoffDq = true
+	assert.Equal(t, BlueWonMatch, DetermineMatchStatus(redScoreSummary, blueScoreSummary))
+	blueScoreSummary.Score = 0
+	assert.Equal(t, BlueWonMatch, DetermineMatchStatus(redScoreSummary, blueScoreSummary))
+	redScoreSummary.PlayoffDq = false
+	blueScoreSummary.PlayoffDq = true
+	assert.Equal(t, RedWonMatch, DetermineMatchStatus(redScoreSummary, blueScoreSummary))
+	redScoreSummary.PlayoffDq = true
+	assert.Equal(t, RedWonMatch, DetermineMatchStatus(redScoreSummary, blueScoreSummary))
 }
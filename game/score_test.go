@@ -4,6 +4,7 @@
 package game
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -12,15 +13,31 @@ func TestScoreSummary(t *testing.T) {
 	redScore := TestScore1()
 	blueScore := TestScore2()
 
-	redSummary := redScore.Summarize()
+	redSummary := redScore.Summarize(blueScore)
 	assert.Equal(t, 45, redSummary.AutoPoints)
 	assert.Equal(t, 80, redSummary.TeleopPoints)
 	assert.Equal(t, 30, redSummary.EndgamePoints)
 
-	blueSummary := blueScore.Summarize()
+	blueSummary := blueScore.Summarize(redScore)
 	assert.Equal(t, 15, blueSummary.AutoPoints)
 	assert.Equal(t, 40, blueSummary.TeleopPoints)
 	assert.Equal(t, 25, blueSummary.EndgamePoints)
+
+	// Check that fouls committed by one alliance are credited to the other.
+	redScore.Fouls = 2
+	redScore.TechFouls = 1
+	blueSummary = blueScore.Summarize(redScore)
+	assert.Equal(t, 22, blueSummary.FoulPoints)
+	assert.Equal(t, 102, blueSummary.Score)
+	redSummary = redScore.Summarize(blueScore)
+	assert.Equal(t, 0, redSummary.FoulPoints)
+	assert.Equal(t, 155, redSummary.Score)
+
+	// Check that a disqualified alliance earns no points.
+	blueScore.PlayoffDq = true
+	blueSummary = blueScore.Summarize(redScore)
+	assert.True(t, blueSummary.PlayoffDq)
+	assert.Equal(t, 0, blueSummary.Score)
 }
 
 func TestScoreEquals(t *testing.T) {
@@ -34,17 +51,51 @@ func TestScoreEquals(t *testing.T) {
 	assert.False(t, score3.Equals(score1))
 
 	score2 = TestScore1()
-	score2.AutoPoints = 20
+	score2.SetCount("auto", 20)
 	assert.False(t, score1.Equals(score2))
 	assert.False(t, score2.Equals(score1))
 
 	score2 = TestScore1()
-	score2.TeleopPoints = 35
+	score2.SetCount("teleop", 35)
 	assert.False(t, score1.Equals(score2))
 	assert.False(t, score2.Equals(score1))
 
 	score2 = TestScore1()
-	score2.EndgamePoints = 15
+	score2.SetCount("endgame", 15)
 	assert.False(t, score1.Equals(score2))
 	assert.False(t, score2.Equals(score1))
+
+	score2 = TestScore1()
+	score2.Fouls = 1
+	assert.False(t, score1.Equals(score2))
+	assert.False(t, score2.Equals(score1))
+
+	score2 = TestScore1()
+	score2.TechFouls = 1
+	assert.False(t, score1.Equals(score2))
+	assert.False(t, score2.Equals(score1))
+
+	score2 = TestScore1()
+	score2.PlayoffDq = true
+	assert.False(t, score1.Equals(score2))
+	assert.False(t, score2.Equals(score1))
+}
+
+func TestScoreJson(t *testing.T) {
+	// Check that the current format survives a round trip.
+	score := TestScore1()
+	score.Fouls = 2
+	score.PlayoffDq = true
+	scoreJson, err := json.Marshal(score)
+	assert.Nil(t, err)
+	var decodedScore Score
+	assert.Nil(t, json.Unmarshal(scoreJson, &decodedScore))
+	assert.True(t, score.Equals(&decodedScore))
+
+	// Check that scores saved with per-period points are converted to element counts.
+	var legacyScore Score
+	assert.Nil(t, json.Unmarshal([]byte(`{"AutoPoints":10,"TeleopPoints":40,"EndgamePoints":5}`), &legacyScore))
+	assert.Equal(t, 10, legacyScore.Count("auto"))
+	assert.Equal(t, 40, legacyScore.Count("teleop"))
+	assert.Equal(t, 5, legacyScore.Count("endgame"))
 }
@@ -7,9 +7,11 @@
 package main
 
 import (
+	"flag"
 	"github.com/FRCTeam1987/crimson-arena/field"
 	"github.com/FRCTeam1987/crimson-arena/web"
 	"log"
+	"os"
 )
 
 const eventDbPath = "./event.db"
@@ -17,10 +19,25 @@ const httpPort = 8080
 
 // Main entry point for the application.
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "simulate-ds" {
+		simulateDriverStations(os.Args[2:])
+		return
+	}
+
+	gameDefinitionPath := flag.String(
+		"game-definition", "", "JSON or YAML game definition file to score the event with",
+	)
+	flag.Parse()
+
 	arena, err := field.NewArena(eventDbPath)
 	if err != nil {
 		log.Fatalln("Error during startup: ", err)
 	}
+	if *gameDefinitionPath != "" {
+		if err = arena.LoadGameDefinitionFile(*gameDefinitionPath); err != nil {
+			log.Fatalln("Error loading game definition: ", err)
+		}
+	}
 
 	// Start the web server in a separate goroutine.
 	web := web.NewWeb(arena)
@@ -5,12 +5,54 @@
 
 package model
 
-import "sort"
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// The number of timeouts and backup robots that each alliance may use over the course of the playoffs.
+const (
+	AllianceTimeoutAllowance = 1
+	AllianceBackupAllowance  = 1
+)
+
+// The number of digits in the PIN that an alliance captain submits lineups with.
+const AllianceLineupPinLength = 8
 
 type Alliance struct {
-	Id      int `db:"id,manual"`
-	TeamIds []int
-	Lineup  [3]int
+	Id                     int `db:"id,manual"`
+	TeamIds                []int
+	Lineup                 [3]int
+	TimeoutsUsed           int
+	BackupTeamIds          []int
+	LineupPin              string
+	SubmittedLineup        [3]int
+	SubmittedLineupMatchId int
+	DeclinedTeamIds        []int
+}
+
+// Returns a new random PIN for an alliance captain to submit lineups with.
+func NewAllianceLineupPin() (string, error) {
+	pin, err := rand.Int(rand.Reader, big.NewInt(0).Exp(big.NewInt(10), big.NewInt(AllianceLineupPinLength), nil))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", AllianceLineupPinLength, pin), nil
+}
+
+// Returns a copy of the given alliances with their lineup PINs removed, for showing outside of the admin pages.
+func WithoutLineupPins(alliances []Alliance) []Alliance {
+	if alliances == nil {
+		return nil
+	}
+	redacted := make([]Alliance, len(alliances))
+	copy(redacted, alliances)
+	for i := range redacted {
+		redacted[i].LineupPin = ""
+	}
+	return redacted
 }
 
 func (database *Database) CreateAlliance(alliance *Alliance) error {
@@ -44,6 +86,21 @@ func (database *Database) GetAllAlliances() ([]Alliance, error) {
 	return alliances, nil
 }
 
+// Returns the number of timeouts the alliance has yet to use.
+func (alliance *Alliance) TimeoutsRemaining() int {
+	return max(AllianceTimeoutAllowance-alliance.TimeoutsUsed, 0)
+}
+
+// Returns the number of backup robots the alliance has called onto the field.
+func (alliance *Alliance) BackupsUsed() int {
+	return len(alliance.BackupTeamIds)
+}
+
+// Returns the number of backup robots the alliance has yet to use.
+func (alliance *Alliance) BackupsRemaining() int {
+	return max(AllianceBackupAllowance-alliance.BackupsUsed(), 0)
+}
+
 // Updates the alliance, if necessary, to include whoever played in the match, in case there was a substitute.
 func (database *Database) UpdateAllianceFromMatch(allianceId int, matchTeamIds [3]int) error {
 	alliance, err := database.GetAllianceById(allianceId)
@@ -77,6 +134,55 @@ func (database *Database) UpdateAllianceFromMatch(allianceId int, matchTeamIds [
 	return nil
 }
 
+// Replaces the teams in the given playoff match with any lineups that the alliance captains have submitted for it,
+// also making them the alliances' current lineups so that they carry over to subsequent matches.
+func (database *Database) ApplySubmittedLineups(match *Match) error {
+	redLineup, err := database.takeSubmittedLineup(match.ElimRedAlliance, match.Id)
+	if err != nil {
+		return err
+	}
+	blueLineup, err := database.takeSubmittedLineup(match.ElimBlueAlliance, match.Id)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	if redLineup != nil && *redLineup != [3]int{match.Red1, match.Red2, match.Red3} {
+		match.Red1, match.Red2, match.Red3 = redLineup[0], redLineup[1], redLineup[2]
+		changed = true
+	}
+	if blueLineup != nil && *blueLineup != [3]int{match.Blue1, match.Blue2, match.Blue3} {
+		match.Blue1, match.Blue2, match.Blue3 = blueLineup[0], blueLineup[1], blueLineup[2]
+		changed = true
+	}
+	if changed {
+		return database.UpdateMatch(match)
+	}
+	return nil
+}
+
+// Returns the lineup that the given alliance's captain has submitted for the given match, if any, after making it the
+// alliance's current lineup.
+func (database *Database) takeSubmittedLineup(allianceId int, matchId int) (*[3]int, error) {
+	if allianceId == 0 {
+		return nil, nil
+	}
+	alliance, err := database.GetAllianceById(allianceId)
+	if err != nil {
+		return nil, err
+	}
+	if alliance == nil || alliance.SubmittedLineupMatchId == 0 || alliance.SubmittedLineupMatchId != matchId {
+		return nil, nil
+	}
+	if alliance.Lineup != alliance.SubmittedLineup {
+		alliance.Lineup = alliance.SubmittedLineup
+		if err = database.UpdateAlliance(alliance); err != nil {
+			return nil, err
+		}
+	}
+	return &alliance.SubmittedLineup, nil
+}
+
 // Returns two arrays containing the IDs of any teams for the red and blue alliances, respectively, who are part of the
 // elimination alliance but are not playing in the given match.
 // If the given match isn't an elimination match, empty arrays are returned.
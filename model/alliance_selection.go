@@ -0,0 +1,97 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Model and datastore read/write methods for the state of an in-progress alliance selection.
+
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// A team's place in the list of teams available to be picked during alliance selection.
+type RankedTeam struct {
+	Rank     int
+	TeamId   int
+	Picked   bool
+	Declined bool
+}
+
+// A copy of the alliances and ranked teams at one point during alliance selection.
+type AllianceSelectionSnapshot struct {
+	Alliances   []Alliance
+	RankedTeams []RankedTeam
+}
+
+type AllianceSelection struct {
+	Id           int `db:"id"`
+	Alliances    []Alliance
+	RankedTeams  []RankedTeam
+	UndoHistory  []AllianceSelectionSnapshot
+	PickDeadline time.Time
+}
+
+// Returns the saved alliance selection, creating an empty one if there isn't one yet.
+func (database *Database) GetAllianceSelection() (*AllianceSelection, error) {
+	allianceSelections, err := database.allianceSelectionTable.getAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(allianceSelections) == 1 {
+		return &allianceSelections[0], nil
+	}
+
+	allianceSelection := AllianceSelection{}
+	if err = database.allianceSelectionTable.create(&allianceSelection); err != nil {
+		return nil, err
+	}
+	return &allianceSelection, nil
+}
+
+func (database *Database) UpdateAllianceSelection(allianceSelection *AllianceSelection) error {
+	return database.allianceSelectionTable.update(allianceSelection)
+}
+
+// Discards the saved alliance selection and returns a new empty one in its place.
+func (database *Database) ResetAllianceSelection() (*AllianceSelection, error) {
+	if err := database.allianceSelectionTable.truncate(); err != nil {
+		return nil, err
+	}
+	return database.GetAllianceSelection()
+}
+
+// Returns a deep copy of the current alliances and ranked teams, which can be modified without affecting the
+// alliance selection.
+func (allianceSelection *AllianceSelection) Snapshot() AllianceSelectionSnapshot {
+	snapshot := AllianceSelectionSnapshot{
+		Alliances:   make([]Alliance, len(allianceSelection.Alliances)),
+		RankedTeams: slices.Clone(allianceSelection.RankedTeams),
+	}
+	for i, alliance := range allianceSelection.Alliances {
+		alliance.TeamIds = slices.Clone(alliance.TeamIds)
+		alliance.BackupTeamIds = slices.Clone(alliance.BackupTeamIds)
+		alliance.DeclinedTeamIds = slices.Clone(alliance.DeclinedTeamIds)
+		snapshot.Alliances[i] = alliance
+	}
+	return snapshot
+}
+
+// Replaces the current alliances and ranked teams with those in the given snapshot, recording the previous state so
+// that the change can be undone.
+func (allianceSelection *AllianceSelection) Apply(snapshot AllianceSelectionSnapshot) {
+	allianceSelection.UndoHistory = append(allianceSelection.UndoHistory, allianceSelection.Snapshot())
+	allianceSelection.Alliances = snapshot.Alliances
+	allianceSelection.RankedTeams = snapshot.RankedTeams
+}
+
+// Reverts the most recent change to the alliances and ranked teams. Returns false if there is nothing to undo.
+func (allianceSelection *AllianceSelection) Undo() bool {
+	if len(allianceSelection.UndoHistory) == 0 {
+		return false
+	}
+	last := len(allianceSelection.UndoHistory) - 1
+	allianceSelection.Alliances = allianceSelection.UndoHistory[last].Alliances
+	allianceSelection.RankedTeams = allianceSelection.UndoHistory[last].RankedTeams
+	allianceSelection.UndoHistory = allianceSelection.UndoHistory[:last]
+	return true
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAllianceSelectionReadWrite(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	allianceSelection, err := db.GetAllianceSelection()
+	assert.Nil(t, err)
+	assert.Empty(t, allianceSelection.Alliances)
+
+	allianceSelection.Alliances = []Alliance{{Id: 1, TeamIds: []int{254, 0, 0}}}
+	allianceSelection.RankedTeams = []RankedTeam{{Rank: 1, TeamId: 254, Picked: true}, {Rank: 2, TeamId: 1114}}
+	allianceSelection.PickDeadline = time.Unix(1000, 0).UTC()
+	assert.Nil(t, db.UpdateAllianceSelection(allianceSelection))
+	allianceSelection2, err := db.GetAllianceSelection()
+	assert.Nil(t, err)
+	assert.Equal(t, allianceSelection.Alliances, allianceSelection2.Alliances)
+	assert.Equal(t, allianceSelection.RankedTeams, allianceSelection2.RankedTeams)
+	assert.True(t, allianceSelection.PickDeadline.Equal(allianceSelection2.PickDeadline))
+
+	allianceSelection3, err := db.ResetAllianceSelection()
+	assert.Nil(t, err)
+	assert.Empty(t, allianceSelection3.Alliances)
+	allianceSelection3, _ = db.GetAllianceSelection()
+	assert.Empty(t, allianceSelection3.Alliances)
+	assert.Empty(t, allianceSelection3.RankedTeams)
+}
+
+func TestAllianceSelectionUndo(t *testing.T) {
+	allianceSelection := AllianceSelection{
+		Alliances:   []Alliance{{Id: 1, TeamIds: []int{254, 0, 0}}},
+		RankedTeams: []RankedTeam{{Rank: 1, TeamId: 254, Picked: true}, {Rank: 2, TeamId: 1114}},
+	}
+	assert.False(t, allianceSelection.Undo())
+
+	// Changing a snapshot shouldn't affect the selection it was taken from.
+	snapshot := allianceSelection.Snapshot()
+	snapshot.Alliances[0].TeamIds[1] = 1114
+	snapshot.RankedTeams[1].Picked = true
+	assert.Equal(t, []int{254, 0, 0}, allianceSelection.Alliances[0].TeamIds)
+	assert.False(t, allianceSelection.RankedTeams[1].Picked)
+
+	allianceSelection.Apply(snapshot)
+	assert.Equal(t, []int{254, 1114, 0}, allianceSelection.Alliances[0].TeamIds)
+	assert.True(t, allianceSelection.RankedTeams[1].Picked)
+	snapshot = allianceSelection.Snapshot()
+	snapshot.Alliances[0].DeclinedTeamIds = []int{1678}
+	allianceSelection.Apply(snapshot)
+	assert.Equal(t, 2, len(allianceSelection.UndoHistory))
+
+	assert.True(t, allianceSelection.Undo())
+	assert.Empty(t, allianceSelection.Alliances[0].DeclinedTeamIds)
+	assert.Equal(t, []int{254, 1114, 0}, allianceSelection.Alliances[0].TeamIds)
+	assert.True(t, allianceSelection.Undo())
+	assert.Equal(t, []int{254, 0, 0}, allianceSelection.Alliances[0].TeamIds)
+	assert.False(t, allianceSelection.RankedTeams[1].Picked)
+	assert.False(t, allianceSelection.Undo())
+}
@@ -39,6 +39,31 @@ func TestAllianceCrud(t *testing.T) {
 	assert.Nil(t, alliance2)
 }
 
+func TestAllianceTimeoutsAndBackupsRemaining(t *testing.T) {
+	alliance := Alliance{Id: 1, TeamIds: []int{254, 1114, 296}}
+	assert.Equal(t, AllianceTimeoutAllowance, alliance.TimeoutsRemaining())
+	assert.Equal(t, AllianceBackupAllowance, alliance.BackupsRemaining())
+
+	alliance.TimeoutsUsed = AllianceTimeoutAllowance
+	alliance.BackupTeamIds = []int{1503, 188}
+	assert.Equal(t, 0, alliance.TimeoutsRemaining())
+	assert.Equal(t, 0, alliance.BackupsRemaining())
+}
+
+func TestWithoutLineupPins(t *testing.T) {
+	alliances := []Alliance{{Id: 1, TeamIds: []int{254}, LineupPin: "12345678"}, {Id: 2, LineupPin: "87654321"}}
+	redacted := WithoutLineupPins(alliances)
+	if assert.Equal(t, 2, len(redacted)) {
+		assert.Equal(t, "", redacted[0].LineupPin)
+		assert.Equal(t, []int{254}, redacted[0].TeamIds)
+		assert.Equal(t, "", redacted[1].LineupPin)
+	}
+
+	// The original alliances should keep their PINs.
+	assert.Equal(t, "12345678", alliances[0].LineupPin)
+	assert.Nil(t, WithoutLineupPins(nil))
+}
+
 func TestUpdateAllianceFromMatch(t *testing.T) {
 	db := setupTestDb(t)
 	defer db.Close()
@@ -52,6 +77,35 @@ func TestUpdateAllianceFromMatch(t *testing.T) {
 	assert.Equal(t, [3]int{1503, 188, 296}, alliance2.Lineup)
 }
 
+func TestApplySubmittedLineups(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	db.CreateAlliance(&Alliance{Id: 1, TeamIds: []int{254, 1114, 296, 1503}, Lineup: [3]int{1114, 254, 296}})
+	db.CreateAlliance(&Alliance{Id: 2, TeamIds: []int{148, 118, 125}, Lineup: [3]int{118, 148, 125}})
+	match := Match{Type: "elimination", ElimRedAlliance: 2, ElimBlueAlliance: 1, Red1: 118, Red2: 148, Red3: 125,
+		Blue1: 1114, Blue2: 254, Blue3: 296}
+	assert.Nil(t, db.CreateMatch(&match))
+
+	// A lineup submitted for a different match is left alone.
+	alliance, _ := db.GetAllianceById(1)
+	alliance.SubmittedLineup = [3]int{1503, 254, 1114}
+	alliance.SubmittedLineupMatchId = match.Id + 1
+	assert.Nil(t, db.UpdateAlliance(alliance))
+	assert.Nil(t, db.ApplySubmittedLineups(&match))
+	assert.Equal(t, 1114, match.Blue1)
+
+	alliance.SubmittedLineupMatchId = match.Id
+	assert.Nil(t, db.UpdateAlliance(alliance))
+	assert.Nil(t, db.ApplySubmittedLineups(&match))
+	assert.Equal(t, [3]int{1503, 254, 1114}, [3]int{match.Blue1, match.Blue2, match.Blue3})
+	assert.Equal(t, [3]int{118, 148, 125}, [3]int{match.Red1, match.Red2, match.Red3})
+	match2, _ := db.GetMatchById(match.Id)
+	assert.Equal(t, match, *match2)
+	alliance, _ = db.GetAllianceById(1)
+	assert.Equal(t, [3]int{1503, 254, 1114}, alliance.Lineup)
+}
+
 func TestTruncateAllianceTeams(t *testing.T) {
 	db := setupTestDb(t)
 	defer db.Close()
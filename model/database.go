@@ -21,19 +21,20 @@ const backupsDir = "db/backups"
 var BaseDir = "." // Mutable for testing
 
 type Database struct {
-	Path               string
-	bolt               *bbolt.DB
-	allianceTable      *table[Alliance]
-	awardTable         *table[Award]
-	eventSettingsTable *table[EventSettings]
-	lowerThirdTable    *table[LowerThird]
-	matchTable         *table[Match]
-	matchResultTable   *table[MatchResult]
-	rankingTable       *table[game.Ranking]
-	scheduleBlockTable *table[ScheduleBlock]
-	sponsorSlideTable  *table[SponsorSlide]
-	teamTable          *table[Team]
-	userSessionTable   *table[UserSession]
+	Path                   string
+	bolt                   *bbolt.DB
+	allianceTable          *table[Alliance]
+	allianceSelectionTable *table[AllianceSelection]
+	awardTable             *table[Award]
+	eventSettingsTable     *table[EventSettings]
+	lowerThirdTable        *table[LowerThird]
+	matchTable             *table[Match]
+	matchResultTable       *table[MatchResult]
+	rankingTable           *table[game.Ranking]
+	scheduleBlockTable     *table[ScheduleBlock]
+	sponsorSlideTable      *table[SponsorSlide]
+	teamTable              *table[Team]
+	userSessionTable       *table[UserSession]
 }
 
 // Opens the Bolt database at the given path, creating it if it doesn't exist.
@@ -49,6 +50,9 @@ func OpenDatabase(filename string) (*Database, error) {
 	if database.allianceTable, err = newTable[Alliance](&database); err != nil {
 		return nil, err
 	}
+	if database.allianceSelectionTable, err = newTable[AllianceSelection](&database); err != nil {
+		return nil, err
+	}
 	if database.awardTable, err = newTable[Award](&database); err != nil {
 		return nil, err
 	}
@@ -5,42 +5,77 @@
 
 package model
 
-import "github.com/FRCTeam1987/crimson-arena/game"
+import (
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"math"
+	"math/rand"
+	"strings"
+)
 
 type EventSettings struct {
-	Id                          int `db:"id"`
-	Name                        string
-	ElimType                    string
-	NumElimAlliances            int
-	SelectionRound2Order        string
-	SelectionRound3Order        string
-	TBADownloadEnabled          bool
-	TbaPublishingEnabled        bool
-	TbaEventCode                string
-	TbaSecretId                 string
-	TbaSecret                   string
-	NetworkSecurityEnabled      bool
-	ApAddress                   string
-	ApPassword                  string
-	ApChannel                   int
-	SwitchAddress               string
-	SwitchPassword              string
-	PlcAddress                  string
-	AdminPassword               string
-	WarmupDurationSec           int
-	AutoDurationSec             int
-	PauseDurationSec            int
-	TeleopDurationSec           int
-	WarningRemainingDurationSec int
+	Id                               int `db:"id"`
+	Name                             string
+	ElimType                         string
+	NumElimAlliances                 int
+	ElimRoundBestOf                  map[int]int
+	ElimRoundRobinMatchesPerAlliance int
+	ElimRoundRobinRankBy             string
+	ElimMinRestSec                   int
+	ElimRestEnforced                 bool
+	ElimLineupCutoffSec              int
+	SelectionRound2Order             string
+	SelectionRound3Order             string
+	SelectionPickTimeSec             int
+	TBADownloadEnabled               bool
+	TbaPublishingEnabled             bool
+	TbaEventCode                     string
+	TbaSecretId                      string
+	TbaSecret                        string
+	NetworkSecurityEnabled           bool
+	ApAddress                        string
+	ApPassword                       string
+	ApChannel                        int
+	SwitchAddress                    string
+	SwitchPassword                   string
+	PlcAddress                       string
+	AdminPassword                    string
+	WarmupDurationSec                int
+	AutoDurationSec                  int
+	PauseDurationSec                 int
+	TeleopDurationSec                int
+	WarningRemainingDurationSec      int
+	GameDefinition                   *game.GameDefinition
+	RankingRules                     *game.RankingRules
+	PlayoffTiebreakers               []game.PlayoffTiebreaker
+	TiebreakerSeed                   int64
+	RetimeCycleTimeSec               int
+	AutoRetimeEnabled                bool
+	GameSpecificDataOptions          string
+	GameSpecificDataPerAlliance      bool
+	GameSpecificDataRevealSec        int
 }
 
+// The longest game-specific message that can be sent to a driver station.
+const MaxGameSpecificDataLength = 64
+
 func (database *Database) GetEventSettings() (*EventSettings, error) {
 	allEventSettings, err := database.eventSettingsTable.getAll()
 	if err != nil {
 		return nil, err
 	}
 	if len(allEventSettings) == 1 {
-		return &allEventSettings[0], nil
+		eventSettings := &allEventSettings[0]
+		if eventSettings.GameDefinition == nil {
+			// The record predates game definitions being configurable; fall back to the generic one.
+			eventSettings.GameDefinition = game.DefaultGameDefinition()
+		}
+		if eventSettings.RankingRules == nil {
+			eventSettings.RankingRules = game.DefaultRankingRules()
+		}
+		if eventSettings.PlayoffTiebreakers == nil {
+			eventSettings.PlayoffTiebreakers = game.DefaultPlayoffTiebreakers()
+		}
+		return eventSettings, nil
 	}
 
 	// Database record doesn't exist yet; create it now.
@@ -57,6 +92,10 @@ func (database *Database) GetEventSettings() (*EventSettings, error) {
 		PauseDurationSec:            game.MatchTiming.PauseDurationSec,
 		TeleopDurationSec:           game.MatchTiming.TeleopDurationSec,
 		WarningRemainingDurationSec: game.MatchTiming.WarningRemainingDurationSec,
+		GameDefinition:              game.DefaultGameDefinition(),
+		RankingRules:                game.DefaultRankingRules(),
+		PlayoffTiebreakers:          game.DefaultPlayoffTiebreakers(),
+		TiebreakerSeed:              NewTiebreakerSeed(),
 	}
 
 	if err := database.eventSettingsTable.create(&eventSettings); err != nil {
@@ -65,6 +104,23 @@ func (database *Database) GetEventSettings() (*EventSettings, error) {
 	return &eventSettings, nil
 }
 
+// Returns a new non-zero seed for the random ranking tiebreaker.
+func NewTiebreakerSeed() int64 {
+	return rand.Int63n(math.MaxInt64-1) + 1
+}
+
+// Returns the game-specific messages that one is chosen from at random for each match, or none if they are to be
+// entered by hand.
+func (eventSettings *EventSettings) GameSpecificDataChoices() []string {
+	var choices []string
+	for _, choice := range strings.Split(eventSettings.GameSpecificDataOptions, ",") {
+		if choice = strings.TrimSpace(choice); choice != "" {
+			choices = append(choices, choice)
+		}
+	}
+	return choices
+}
+
 func (database *Database) UpdateEventSettings(eventSettings *EventSettings) error {
 	return database.eventSettingsTable.update(eventSettings)
 }
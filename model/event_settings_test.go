@@ -4,6 +4,7 @@
 package model
 
 import (
+	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -14,6 +15,7 @@ func TestEventSettingsReadWrite(t *testing.T) {
 
 	eventSettings, err := db.GetEventSettings()
 	assert.Nil(t, err)
+	assert.NotEqual(t, int64(0), eventSettings.TiebreakerSeed)
 	assert.Equal(
 		t,
 		EventSettings{
@@ -30,6 +32,10 @@ func TestEventSettingsReadWrite(t *testing.T) {
 			PauseDurationSec:            3,
 			TeleopDurationSec:           135,
 			WarningRemainingDurationSec: 20,
+			GameDefinition:              game.DefaultGameDefinition(),
+			RankingRules:                game.DefaultRankingRules(),
+			PlayoffTiebreakers:          game.DefaultPlayoffTiebreakers(),
+			TiebreakerSeed:              eventSettings.TiebreakerSeed,
 		},
 		*eventSettings,
 	)
@@ -44,3 +50,30 @@ func TestEventSettingsReadWrite(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, eventSettings, eventSettings2)
 }
+
+func TestEventSettingsTiebreakerSeedIsStable(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	eventSettings, err := db.GetEventSettings()
+	assert.Nil(t, err)
+	seed := eventSettings.TiebreakerSeed
+	eventSettings2, err := db.GetEventSettings()
+	assert.Nil(t, err)
+	assert.Equal(t, seed, eventSettings2.TiebreakerSeed)
+
+	// Reading a record saved before the seed existed shouldn't change it; the seed is filled in when it is loaded.
+	eventSettings.TiebreakerSeed = 0
+	assert.Nil(t, db.UpdateEventSettings(eventSettings))
+	eventSettings, err = db.GetEventSettings()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), eventSettings.TiebreakerSeed)
+}
+
+func TestEventSettingsGameSpecificDataChoices(t *testing.T) {
+	eventSettings := EventSettings{}
+	assert.Empty(t, eventSettings.GameSpecificDataChoices())
+
+	eventSettings.GameSpecificDataOptions = " LRL,RLR , ,LLL,"
+	assert.Equal(t, []string{"LRL", "RLR", "LLL"}, eventSettings.GameSpecificDataChoices())
+}
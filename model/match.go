@@ -37,6 +37,9 @@ type Match struct {
 	StartedAt        time.Time
 	ScoreCommittedAt time.Time
 	Status           game.MatchStatus
+	TiebreakReason   string
+	RedGameData      string
+	BlueGameData     string
 }
 
 func (database *Database) CreateMatch(match *Match) error {
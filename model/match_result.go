@@ -7,6 +7,12 @@ package model
 
 import (
 	"github.com/FRCTeam1987/crimson-arena/game"
+	"strconv"
+)
+
+const (
+	YellowCard = "yellow"
+	RedCard    = "red"
 )
 
 type MatchResult struct {
@@ -16,6 +22,11 @@ type MatchResult struct {
 	MatchType  string
 	RedScore   *game.Score
 	BlueScore  *game.Score
+	RedCards   map[string]string
+	BlueCards  map[string]string
+	// Teams whose red card was upgraded from a yellow card because they were already carrying one, so that the upgrade
+	// can be undone if the earlier card is later changed.
+	CarryoverRedCards map[string]bool
 }
 
 // Returns a new match result object with empty slices instead of nil.
@@ -23,6 +34,9 @@ func NewMatchResult() *MatchResult {
 	matchResult := new(MatchResult)
 	matchResult.RedScore = new(game.Score)
 	matchResult.BlueScore = new(game.Score)
+	matchResult.RedCards = make(map[string]string)
+	matchResult.BlueCards = make(map[string]string)
+	matchResult.CarryoverRedCards = make(map[string]bool)
 	return matchResult
 }
 
@@ -46,6 +60,15 @@ func (database *Database) GetMatchResultForMatch(matchId int) (*MatchResult, err
 	return mostRecentMatchResult, nil
 }
 
+// Returns true if the results of any match have been recorded.
+func (database *Database) HasMatchResults() (bool, error) {
+	matchResults, err := database.matchResultTable.getAll()
+	if err != nil {
+		return false, err
+	}
+	return len(matchResults) > 0, nil
+}
+
 func (database *Database) UpdateMatchResult(matchResult *MatchResult) error {
 	return database.matchResultTable.update(matchResult)
 }
@@ -60,10 +83,31 @@ func (database *Database) TruncateMatchResults() error {
 
 // Calculates and returns the summary fields used for ranking and display for the red alliance.
 func (matchResult *MatchResult) RedScoreSummary() *game.ScoreSummary {
-	return matchResult.RedScore.Summarize()
+	return matchResult.RedScore.Summarize(matchResult.BlueScore)
 }
 
 // Calculates and returns the summary fields used for ranking and display for the blue alliance.
 func (matchResult *MatchResult) BlueScoreSummary() *game.ScoreSummary {
-	return matchResult.BlueScore.Summarize()
+	return matchResult.BlueScore.Summarize(matchResult.RedScore)
+}
+
+// Returns true if the given team received a red card in the match and is therefore disqualified from it.
+func (matchResult *MatchResult) IsDisqualified(teamId int) bool {
+	teamIdString := strconv.Itoa(teamId)
+	return matchResult.RedCards[teamIdString] == RedCard || matchResult.BlueCards[teamIdString] == RedCard
+}
+
+// Disqualifies any alliance in a playoff match having a team that received a red card, forfeiting the match.
+func (matchResult *MatchResult) CorrectPlayoffScore() {
+	matchResult.RedScore.PlayoffDq = hasRedCard(matchResult.RedCards)
+	matchResult.BlueScore.PlayoffDq = hasRedCard(matchResult.BlueCards)
+}
+
+func hasRedCard(cards map[string]string) bool {
+	for _, card := range cards {
+		if card == RedCard {
+			return true
+		}
+	}
+	return false
 }
@@ -5,6 +5,7 @@ package model
 
 import (
 	"github.com/stretchr/testify/assert"
+	"go.etcd.io/bbolt"
 	"testing"
 )
 
@@ -27,7 +28,7 @@ func TestMatchResultCrud(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, matchResult, matchResult2)
 
-	matchResult.BlueScore.EndgamePoints = 1234
+	matchResult.BlueScore.SetCount("endgame", 1234)
 	assert.Nil(t, db.UpdateMatchResult(matchResult))
 	matchResult2, err = db.GetMatchResultForMatch(254)
 	assert.Nil(t, err)
@@ -67,3 +68,48 @@ func TestGetMatchResultForMatch(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, matchResult2, matchResult4)
 }
+
+func TestGetLegacyMatchResult(t *testing.T) {
+	db := setupTestDb(t)
+	defer db.Close()
+
+	// Write a result in the format used before scores were driven by the game definition.
+	legacyJson := `{"Id":1,"MatchId":254,"PlayNumber":1,"MatchType":"qualification",` +
+		`"RedScore":{"AutoPoints":10,"TeleopPoints":40,"EndgamePoints":5},` +
+		`"BlueScore":{"AutoPoints":15,"TeleopPoints":30,"EndgamePoints":0},"RedCards":{},"BlueCards":{}}`
+	err := db.bolt.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte("MatchResult")).Put(idToKey(1), []byte(legacyJson))
+	})
+	assert.Nil(t, err)
+
+	matchResult, err := db.GetMatchResultForMatch(254)
+	assert.Nil(t, err)
+	if assert.NotNil(t, matchResult) {
+		redSummary := matchResult.RedScoreSummary()
+		assert.Equal(t, 10, redSummary.AutoPoints)
+		assert.Equal(t, 40, redSummary.TeleopPoints)
+		assert.Equal(t, 5, redSummary.EndgamePoints)
+		assert.Equal(t, 55, redSummary.Score)
+		assert.Equal(t, 45, matchResult.BlueScoreSummary().Score)
+	}
+}
+
+func TestMatchResultCards(t *testing.T) {
+	matchResult := BuildTestMatchResult(254, 1)
+	assert.False(t, matchResult.IsDisqualified(1868))
+	assert.False(t, matchResult.IsDisqualified(254))
+
+	matchResult.RedCards["254"] = RedCard
+	assert.True(t, matchResult.IsDisqualified(254))
+	assert.Equal(t, 155, matchResult.RedScoreSummary().Score)
+
+	matchResult.CorrectPlayoffScore()
+	assert.True(t, matchResult.RedScore.PlayoffDq)
+	assert.False(t, matchResult.BlueScore.PlayoffDq)
+	assert.Equal(t, 0, matchResult.RedScoreSummary().Score)
+	assert.Equal(t, 80, matchResult.BlueScoreSummary().Score)
+
+	delete(matchResult.RedCards, "254")
+	matchResult.CorrectPlayoffScore()
+	assert.False(t, matchResult.RedScore.PlayoffDq)
+}
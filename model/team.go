@@ -5,7 +5,10 @@
 
 package model
 
-import "sort"
+import (
+	"sort"
+	"time"
+)
 
 type Team struct {
 	Id              int `db:"id,manual"`
@@ -20,6 +23,26 @@ type Team struct {
 	WpaKey          string
 	HasConnected    bool
 	FtaNotes        string
+	AvailableFrom   time.Time
+	AvailableUntil  time.Time
+	Withdrawn       bool
+}
+
+// Returns true if the team is at the event and able to play a match starting at the given time. A zero time for
+// either end of the team's availability window leaves that end unconstrained.
+func (team *Team) IsAvailableAt(matchTime time.Time) bool {
+	if !team.AvailableFrom.IsZero() && matchTime.Before(team.AvailableFrom) {
+		return false
+	}
+	if !team.AvailableUntil.IsZero() && matchTime.After(team.AvailableUntil) {
+		return false
+	}
+	return true
+}
+
+// Returns true if the team has a restricted availability window.
+func (team *Team) HasAvailabilityWindow() bool {
+	return !team.AvailableFrom.IsZero() || !team.AvailableUntil.IsZero()
 }
 
 func (database *Database) CreateTeam(team *Team) error {
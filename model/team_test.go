@@ -6,6 +6,7 @@ package model
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestGetNonexistentTeam(t *testing.T) {
@@ -72,3 +73,19 @@ func TestGetAllTeams(t *testing.T) {
 		assert.Equal(t, i+1, teams[i].Id)
 	}
 }
+
+func TestTeamIsAvailableAt(t *testing.T) {
+	team := Team{Id: 254}
+	assert.False(t, team.HasAvailabilityWindow())
+	assert.True(t, team.IsAvailableAt(time.Unix(1000, 0)))
+
+	team.AvailableFrom = time.Unix(1000, 0)
+	assert.True(t, team.HasAvailabilityWindow())
+	assert.False(t, team.IsAvailableAt(time.Unix(999, 0)))
+	assert.True(t, team.IsAvailableAt(time.Unix(1000, 0)))
+	assert.True(t, team.IsAvailableAt(time.Unix(5000, 0)))
+
+	team.AvailableUntil = time.Unix(2000, 0)
+	assert.True(t, team.IsAvailableAt(time.Unix(2000, 0)))
+	assert.False(t, team.IsAvailableAt(time.Unix(2001, 0)))
+}
@@ -27,6 +27,8 @@ func BuildTestMatchResult(matchId int, playNumber int) *MatchResult {
 	matchResult := &MatchResult{MatchId: matchId, PlayNumber: playNumber, MatchType: "qualification"}
 	matchResult.RedScore = game.TestScore1()
 	matchResult.BlueScore = game.TestScore2()
+	matchResult.RedCards = map[string]string{}
+	matchResult.BlueCards = map[string]string{"1868": YellowCard}
 	return matchResult
 }
 
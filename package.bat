@@ -8,4 +8,4 @@ go clean
 
 go build
 
-zip -r -X crimson-arena.zip LICENSE README.md access_point_config.tar.gz crimson-arena.exe db font schedules static switch_config.txt templates
+zip -r -X crimson-arena.zip LICENSE README.md access_point_config.tar.gz brackets crimson-arena.exe db font schedules static switch_config.txt templates
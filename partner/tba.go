@@ -11,11 +11,12 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/bracket"
+	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"io/ioutil"
 	"net/http"
 	"strconv"
-	"strings"
 )
 
 const (
@@ -33,13 +34,14 @@ type TbaClient struct {
 }
 
 type TbaMatch struct {
-	CompLevel   string                  `json:"comp_level"`
-	SetNumber   int                     `json:"set_number"`
-	MatchNumber int                     `json:"match_number"`
-	Alliances   map[string]*TbaAlliance `json:"alliances"`
-	TimeString  string                  `json:"time_string"`
-	TimeUtc     string                  `json:"time_utc"`
-	DisplayName string                  `json:"display_name"`
+	CompLevel       string                  `json:"comp_level"`
+	SetNumber       int                     `json:"set_number"`
+	MatchNumber     int                     `json:"match_number"`
+	Alliances       map[string]*TbaAlliance `json:"alliances"`
+	TimeString      string                  `json:"time_string"`
+	TimeUtc         string                  `json:"time_utc"`
+	DisplayName     string                  `json:"display_name"`
+	WinningAlliance string                  `json:"winning_alliance,omitempty"`
 }
 
 type TbaAlliance struct {
@@ -50,17 +52,14 @@ type TbaAlliance struct {
 }
 
 type TbaRanking struct {
-	TeamKey string `json:"team_key"`
-	Rank    int    `json:"rank"`
-	RP      float32
-	Auto    int
-	Endgame int
-	Teleop  int
-	Wins    int `json:"wins"`
-	Losses  int `json:"losses"`
-	Ties    int `json:"ties"`
-	Dqs     int `json:"dqs"`
-	Played  int `json:"played"`
+	TeamKey    string         `json:"team_key"`
+	Rank       int            `json:"rank"`
+	Wins       int            `json:"wins"`
+	Losses     int            `json:"losses"`
+	Ties       int            `json:"ties"`
+	Dqs        int            `json:"dqs"`
+	Played     int            `json:"played"`
+	Breakdowns map[string]any `json:"-"`
 }
 
 type TbaRankings struct {
@@ -105,33 +104,6 @@ type TbaPublishedAward struct {
 	Awardee string `json:"awardee"`
 }
 
-type elimMatchKey struct {
-	elimRound int
-	elimGroup int
-}
-
-type tbaElimMatchKey struct {
-	compLevel string
-	setNumber int
-}
-
-var doubleEliminationMatchKeyMapping = map[elimMatchKey]tbaElimMatchKey{
-	{1, 1}: {"ef", 1},
-	{1, 2}: {"ef", 2},
-	{1, 3}: {"ef", 3},
-	{1, 4}: {"ef", 4},
-	{2, 1}: {"ef", 5},
-	{2, 2}: {"ef", 6},
-	{2, 3}: {"qf", 1},
-	{2, 4}: {"qf", 2},
-	{3, 1}: {"qf", 3},
-	{3, 2}: {"qf", 4},
-	{4, 1}: {"sf", 1},
-	{4, 2}: {"sf", 2},
-	{5, 1}: {"f", 1},
-	{6, 1}: {"f", 2},
-}
-
 func NewTbaClient(eventCode, secretId, secret string) *TbaClient {
 	return &TbaClient{BaseUrl: tbaBaseUrl, eventCode: eventCode, secretId: secretId, secret: secret,
 		eventNamesCache: make(map[string]string)}
@@ -303,6 +275,7 @@ func (client *TbaClient) PublishMatches(database *model.Database) error {
 	}
 	matches := append(qualMatches, elimMatches...)
 	tbaMatches := make([]TbaMatch, len(matches))
+	var bracketDefinition *bracket.BracketDefinition
 
 	// Build a JSON array of TBA-format matches.
 	for i, match := range matches {
@@ -311,27 +284,26 @@ func (client *TbaClient) PublishMatches(database *model.Database) error {
 		// Fill in scores if the match has been played.
 		var redScoreSummary, blueScoreSummary int
 		var redScore, blueScore *int
+		var redCards, blueCards map[string]string
 		if match.IsComplete() {
 			matchResult, err := database.GetMatchResultForMatch(match.Id)
 			if err != nil {
 				return err
 			}
 			if matchResult != nil {
-				redScoreSummary = matchResult.RedScore.AutoPoints +
-					matchResult.RedScore.TeleopPoints +
-					matchResult.RedScore.EndgamePoints
-				blueScoreSummary = matchResult.BlueScore.AutoPoints +
-					matchResult.BlueScore.TeleopPoints +
-					matchResult.BlueScore.EndgamePoints
+				redScoreSummary = matchResult.RedScoreSummary().Score
+				blueScoreSummary = matchResult.BlueScoreSummary().Score
 				redScore = &redScoreSummary
 				blueScore = &blueScoreSummary
+				redCards = matchResult.RedCards
+				blueCards = matchResult.BlueCards
 			}
 		}
 		alliances := make(map[string]*TbaAlliance)
 		alliances["red"] = createTbaAlliance([3]int{match.Red1, match.Red2, match.Red3}, [3]bool{match.Red1IsSurrogate,
-			match.Red2IsSurrogate, match.Red3IsSurrogate}, redScore)
+			match.Red2IsSurrogate, match.Red3IsSurrogate}, redScore, redCards)
 		alliances["blue"] = createTbaAlliance([3]int{match.Blue1, match.Blue2, match.Blue3},
-			[3]bool{match.Blue1IsSurrogate, match.Blue2IsSurrogate, match.Blue3IsSurrogate}, blueScore)
+			[3]bool{match.Blue1IsSurrogate, match.Blue2IsSurrogate, match.Blue3IsSurrogate}, blueScore, blueCards)
 
 		tbaMatches[i] = TbaMatch{
 			CompLevel:   "qm",
@@ -342,7 +314,17 @@ func (client *TbaClient) PublishMatches(database *model.Database) error {
 			TimeUtc:     match.Time.UTC().Format("2006-01-02T15:04:05"),
 		}
 		if match.Type == "elimination" {
-			setElimMatchKey(&tbaMatches[i], &match, eventSettings.ElimType)
+			if bracketDefinition == nil {
+				if bracketDefinition, err = bracket.GetBracketDefinition(eventSettings.ElimType); err != nil {
+					return err
+				}
+			}
+			setElimMatchKey(&tbaMatches[i], &match, bracketDefinition)
+
+			// TBA would otherwise infer a tie from the equal scores of a match decided by a playoff tiebreaker.
+			if match.TiebreakReason != "" {
+				tbaMatches[i].WinningAlliance = getTbaWinningAlliance(match.Status)
+			}
 		}
 	}
 	jsonBody, err := json.Marshal(tbaMatches)
@@ -362,6 +344,23 @@ func (client *TbaClient) PublishMatches(database *model.Database) error {
 	return nil
 }
 
+// Flattens the breakdown values into the ranking object alongside the standard fields, as TBA expects.
+func (ranking TbaRanking) MarshalJSON() ([]byte, error) {
+	type standardFields TbaRanking
+	fields, err := json.Marshal(standardFields(ranking))
+	if err != nil {
+		return nil, err
+	}
+	flattened := make(map[string]any)
+	if err = json.Unmarshal(fields, &flattened); err != nil {
+		return nil, err
+	}
+	for key, value := range ranking.Breakdowns {
+		flattened[key] = value
+	}
+	return json.Marshal(flattened)
+}
+
 // Uploads the team standings to The Blue Alliance.
 func (client *TbaClient) PublishRankings(database *model.Database) error {
 	rankings, err := database.GetAllRankings()
@@ -369,21 +368,37 @@ func (client *TbaClient) PublishRankings(database *model.Database) error {
 		return err
 	}
 
-	// Build a JSON object of TBA-format rankings.
-	breakdowns := []string{"RP", "Auto", "Endgame", "Teleop"}
+	eventSettings, err := database.GetEventSettings()
+	if err != nil {
+		return err
+	}
+
+	// Build a JSON object of TBA-format rankings, with a breakdown for each of the event's tiebreakers.
+	tiebreakers := eventSettings.RankingRules.DisplayTiebreakers()
+	breakdowns := make([]string, len(tiebreakers))
+	for i, tiebreaker := range tiebreakers {
+		breakdowns[i] = tiebreaker.Name()
+	}
 	tbaRankings := make([]TbaRanking, len(rankings))
 	for i, ranking := range rankings {
 		tbaRankings[i] = TbaRanking{
-			TeamKey: getTbaTeam(ranking.TeamId),
-			Rank:    ranking.Rank,
-			RP:      float32(ranking.RankingPoints) / float32(ranking.Played),
-			Auto:    ranking.AutoPoints,
-			Endgame: ranking.EndgamePoints,
-			Teleop:  ranking.TeleopPoints,
-			Wins:    ranking.Wins,
-			Losses:  ranking.Losses,
-			Ties:    ranking.Ties,
-			Played:  ranking.Played,
+			TeamKey:    getTbaTeam(ranking.TeamId),
+			Rank:       ranking.Rank,
+			Wins:       ranking.Wins,
+			Losses:     ranking.Losses,
+			Ties:       ranking.Ties,
+			Dqs:        ranking.Disqualifications,
+			Played:     ranking.Played,
+			Breakdowns: make(map[string]any, len(tiebreakers)),
+		}
+		for _, tiebreaker := range tiebreakers {
+			if tiebreaker == game.RankingPointsTiebreaker {
+				// TBA expects the ranking score to be averaged over the matches played.
+				tbaRankings[i].Breakdowns[tiebreaker.Name()] =
+					float32(ranking.RankingPoints) / float32(ranking.Played)
+			} else {
+				tbaRankings[i].Breakdowns[tiebreaker.Name()] = ranking.TiebreakerValue(tiebreaker)
+			}
 		}
 	}
 	jsonBody, err := json.Marshal(TbaRankings{breakdowns, tbaRankings})
@@ -505,7 +520,7 @@ func (client *TbaClient) postRequest(resource string, action string, body []byte
 	return httpClient.Do(request)
 }
 
-func createTbaAlliance(teamIds [3]int, surrogates [3]bool, score *int) *TbaAlliance {
+func createTbaAlliance(teamIds [3]int, surrogates [3]bool, score *int, cards map[string]string) *TbaAlliance {
 	alliance := TbaAlliance{Surrogates: []string{}, Dqs: []string{}, Score: score}
 	for i, teamId := range teamIds {
 		teamKey := getTbaTeam(teamId)
@@ -513,6 +528,9 @@ func createTbaAlliance(teamIds [3]int, surrogates [3]bool, score *int) *TbaAllia
 		if surrogates[i] {
 			alliance.Surrogates = append(alliance.Surrogates, teamKey)
 		}
+		if cards[strconv.Itoa(teamId)] == model.RedCard {
+			alliance.Dqs = append(alliance.Dqs, teamKey)
+		}
 	}
 
 	return &alliance
@@ -549,20 +567,27 @@ func (client *TbaClient) PublishAwards(database *model.Database) error {
 	return nil
 }
 
-// Sets the match key attributes on TbaMatch based on the match and bracket type.
-func setElimMatchKey(tbaMatch *TbaMatch, match *model.Match, elimType string) {
-	if elimType == "single" {
-		tbaMatch.CompLevel = map[int]string{1: "ef", 2: "qf", 3: "sf", 4: "f"}[match.ElimRound]
-		tbaMatch.SetNumber = match.ElimGroup
-		tbaMatch.MatchNumber = match.ElimInstance
-	} else if elimType == "double" {
-		if tbaKey, ok := doubleEliminationMatchKeyMapping[elimMatchKey{match.ElimRound, match.ElimGroup}]; ok {
-			tbaMatch.CompLevel = tbaKey.compLevel
-			tbaMatch.SetNumber = tbaKey.setNumber
-		}
-		tbaMatch.MatchNumber = match.ElimInstance
-		if !strings.HasPrefix(match.DisplayName, "F") {
-			tbaMatch.DisplayName = "Match " + match.DisplayName
-		}
+// Sets the match key attributes on TbaMatch based on the match and the definition of the bracket it belongs to.
+func setElimMatchKey(tbaMatch *TbaMatch, match *model.Match, bracketDefinition *bracket.BracketDefinition) {
+	tbaMatch.MatchNumber = match.ElimInstance
+	matchup := bracketDefinition.GetMatchup(match.ElimRound, match.ElimGroup)
+	if matchup == nil {
+		return
+	}
+	tbaMatch.CompLevel, tbaMatch.SetNumber = bracketDefinition.TbaMatchKey(matchup)
+	if _, err := strconv.Atoi(matchup.DisplayName); err == nil {
+		// Matchups that are only numbered read better on TBA with a prefix.
+		tbaMatch.DisplayName = "Match " + match.DisplayName
+	}
+}
+
+// Returns the TBA alliance color corresponding to the winner recorded in the given match status.
+func getTbaWinningAlliance(status game.MatchStatus) string {
+	switch status {
+	case game.RedWonMatch:
+		return "red"
+	case game.BlueWonMatch:
+		return "blue"
 	}
+	return ""
 }
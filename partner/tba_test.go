@@ -6,6 +6,7 @@ package partner
 import (
 	"bytes"
 	"encoding/json"
+	"github.com/FRCTeam1987/crimson-arena/bracket"
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/stretchr/testify/assert"
@@ -44,9 +45,13 @@ func TestPublishMatches(t *testing.T) {
 	match1 := model.Match{Type: "qualification", DisplayName: "2", Time: time.Unix(600, 0), Red1: 7, Red2: 8, Red3: 9,
 		Blue1: 10, Blue2: 11, Blue3: 12, Status: game.RedWonMatch}
 	match2 := model.Match{Type: "elimination", DisplayName: "SF2-2", ElimRound: 3, ElimGroup: 2, ElimInstance: 2}
+	match3 := model.Match{Type: "elimination", DisplayName: "SF1-1", ElimRound: 3, ElimGroup: 1, ElimInstance: 1,
+		Status: game.BlueWonMatch, TiebreakReason: "Fewer foul points"}
 	database.CreateMatch(&match1)
 	database.CreateMatch(&match2)
+	database.CreateMatch(&match3)
 	matchResult1 := model.BuildTestMatchResult(match1.Id, 1)
+	matchResult1.BlueCards["11"] = model.RedCard
 	database.CreateMatchResult(matchResult1)
 
 	// Mock the TBA server.
@@ -54,9 +59,14 @@ func TestPublishMatches(t *testing.T) {
 		body, _ := ioutil.ReadAll(r.Body)
 		var matches []*TbaMatch
 		json.Unmarshal(body, &matches)
-		assert.Equal(t, 2, len(matches))
+		assert.Equal(t, 3, len(matches))
 		assert.Equal(t, "qm", matches[0].CompLevel)
 		assert.Equal(t, "sf", matches[1].CompLevel)
+		assert.Equal(t, "", matches[0].WinningAlliance)
+		assert.Equal(t, "blue", matches[1].WinningAlliance)
+		assert.Equal(t, "", matches[2].WinningAlliance)
+		assert.Equal(t, []string{}, matches[0].Alliances["red"].Dqs)
+		assert.Equal(t, []string{"frc11"}, matches[0].Alliances["blue"].Dqs)
 	}))
 	defer tbaServer.Close()
 	client := NewTbaClient("my_event_code", "my_secret_id", "my_secret")
@@ -65,6 +75,47 @@ func TestPublishMatches(t *testing.T) {
 	assert.Nil(t, client.PublishMatches(database))
 }
 
+func TestSetElimMatchKey(t *testing.T) {
+	model.BaseDir = ".."
+	singleDefinition, err := bracket.GetBracketDefinition("single")
+	assert.Nil(t, err)
+	doubleDefinition, err := bracket.GetBracketDefinition("double")
+	assert.Nil(t, err)
+
+	var tbaMatch TbaMatch
+	setElimMatchKey(
+		&tbaMatch, &model.Match{DisplayName: "QF3-2", ElimRound: 2, ElimGroup: 3, ElimInstance: 2}, singleDefinition,
+	)
+	assert.Equal(t, TbaMatch{CompLevel: "qf", SetNumber: 3, MatchNumber: 2}, tbaMatch)
+
+	// A round shortened to a single match is keyed the same way as the first match of a longer series.
+	tbaMatch = TbaMatch{}
+	setElimMatchKey(
+		&tbaMatch, &model.Match{DisplayName: "SF2", ElimRound: 3, ElimGroup: 2, ElimInstance: 1}, singleDefinition,
+	)
+	assert.Equal(t, TbaMatch{CompLevel: "sf", SetNumber: 2, MatchNumber: 1}, tbaMatch)
+
+	tbaMatch = TbaMatch{}
+	setElimMatchKey(
+		&tbaMatch, &model.Match{DisplayName: "10", ElimRound: 3, ElimGroup: 2, ElimInstance: 1}, doubleDefinition,
+	)
+	assert.Equal(t, TbaMatch{CompLevel: "qf", SetNumber: 4, MatchNumber: 1, DisplayName: "Match 10"}, tbaMatch)
+
+	tbaMatch = TbaMatch{}
+	setElimMatchKey(
+		&tbaMatch, &model.Match{DisplayName: "F-3", ElimRound: 6, ElimGroup: 1, ElimInstance: 3}, doubleDefinition,
+	)
+	assert.Equal(t, TbaMatch{CompLevel: "f", SetNumber: 2, MatchNumber: 3}, tbaMatch)
+
+	roundRobinDefinition, err := bracket.GetBracketDefinition("round_robin")
+	assert.Nil(t, err)
+	tbaMatch = TbaMatch{}
+	setElimMatchKey(
+		&tbaMatch, &model.Match{DisplayName: "7", ElimRound: 1, ElimGroup: 7, ElimInstance: 1}, roundRobinDefinition,
+	)
+	assert.Equal(t, TbaMatch{CompLevel: "qf", SetNumber: 7, MatchNumber: 1, DisplayName: "Match 7"}, tbaMatch)
+}
+
 func TestPublishRankings(t *testing.T) {
 	database := setupTestDb(t)
 
@@ -76,9 +127,15 @@ func TestPublishRankings(t *testing.T) {
 		body, _ := ioutil.ReadAll(r.Body)
 		var response TbaRankings
 		json.Unmarshal(body, &response)
+		assert.Equal(t, []string{"RP", "Auto", "Endgame", "Teleop"}, response.Breakdowns)
 		assert.Equal(t, 2, len(response.Rankings))
 		assert.Equal(t, "frc254", response.Rankings[0].TeamKey)
 		assert.Equal(t, "frc1114", response.Rankings[1].TeamKey)
+		var rawResponse struct{ Rankings []map[string]any }
+		json.Unmarshal(body, &rawResponse)
+		assert.Equal(t, 2.0, rawResponse.Rankings[0]["RP"])
+		assert.Equal(t, 625.0, rawResponse.Rankings[0]["Auto"])
+		assert.Equal(t, 3.0, rawResponse.Rankings[0]["wins"])
 	}))
 	defer tbaServer.Close()
 	client := NewTbaClient("my_event_code", "my_secret_id", "my_secret")
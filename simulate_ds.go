@@ -0,0 +1,59 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Command-line mode for running simulated driver stations against a field.
+
+package main
+
+import (
+	"flag"
+	"github.com/FRCTeam1987/crimson-arena/field"
+	"github.com/FRCTeam1987/crimson-arena/network"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Connects simulated driver stations for the given teams to the field and keeps them running until the process is
+// killed. Usage: crimson-arena simulate-ds -teams 254,1114,2056 [-server 10.0.100.5] [-battery 12.5] [-trip 10]
+// [-robotLinked=false]
+func simulateDriverStations(args []string) {
+	flags := flag.NewFlagSet("simulate-ds", flag.ExitOnError)
+	server := flags.String("server", network.ServerIpAddress, "IP address of the field to connect to")
+	teams := flags.String("teams", "", "comma-separated list of teams to simulate driver stations for")
+	robotLinked := flags.Bool("robotLinked", true, "whether the simulated robots are linked")
+	battery := flags.Float64("battery", 12.5, "battery voltage reported by the simulated robots")
+	tripTimeMs := flags.Int("trip", 10, "DS-robot trip time in milliseconds reported by the simulated robots")
+	flags.Parse(args)
+
+	var teamIds []int
+	for _, team := range strings.Split(*teams, ",") {
+		teamId, err := strconv.Atoi(strings.TrimSpace(team))
+		if err != nil {
+			log.Fatalf("Invalid team number '%s'.", team)
+		}
+		teamIds = append(teamIds, teamId)
+	}
+
+	simulator := field.NewDriverStationSimulator(*server)
+	simulator.SetTeams(teamIds)
+	robot := field.SimulatedRobot{RobotLinked: *robotLinked, BatteryVoltage: *battery, TripTimeMs: *tripTimeMs}
+	for _, teamId := range teamIds {
+		simulator.SetRobot(teamId, robot)
+	}
+	log.Printf("Simulating driver stations for teams %v against %s.", teamIds, *server)
+
+	// Periodically report which stations the field has put each team in.
+	for {
+		time.Sleep(5 * time.Second)
+		var statuses []string
+		for _, teamId := range teamIds {
+			station := simulator.GetAllianceStation(teamId)
+			if station == "" {
+				station = "not connected"
+			}
+			statuses = append(statuses, strconv.Itoa(teamId)+": "+station)
+		}
+		log.Println(strings.Join(statuses, ", "))
+	}
+}
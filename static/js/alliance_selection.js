@@ -0,0 +1,69 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Client-side logic for making live picks on the Alliance Selection page.
+
+var websocket;
+var lastAlliances;
+var pickTimerInterval;
+
+// Sends a websocket message to place the entered team in the next open alliance selection spot.
+var pickTeam = function() {
+  websocket.send("pick", { TeamId: parseInt($("#liveTeamId").val()) });
+};
+
+// Sends a websocket message to record that the entered team declined the picking alliance's invitation.
+var declineTeam = function() {
+  websocket.send("decline", { TeamId: parseInt($("#liveTeamId").val()) });
+};
+
+// Sends a websocket message to revert the most recent change to the alliance selection.
+var undo = function() {
+  websocket.send("undo");
+};
+
+// Sends a websocket message to start the pick countdown over from the beginning.
+var startTimer = function() {
+  websocket.send("startTimer");
+};
+
+// Sends a websocket message to stop the pick countdown.
+var stopTimer = function() {
+  websocket.send("stopTimer");
+};
+
+// Handles a websocket message to update the alliance selection state and pick countdown.
+var handleAllianceSelection = function(data) {
+  // Reload the page to show the new state if the alliances have been changed from anywhere.
+  var alliances = JSON.stringify(data.Alliances);
+  if (lastAlliances !== undefined && alliances !== lastAlliances) {
+    location.reload();
+    return;
+  }
+  lastAlliances = alliances;
+
+  clearInterval(pickTimerInterval);
+  $("#pickTimer").text("");
+  if (data.ShowTimer) {
+    var receivedAt = Date.now();
+    var update = function() {
+      var remainingSec = Math.max(data.TimeRemainingSec - Math.floor((Date.now() - receivedAt) / 1000), 0);
+      var secondsString = String(remainingSec % 60);
+      if (secondsString.length === 1) {
+        secondsString = "0" + secondsString;
+      }
+      $("#pickTimer").text(Math.floor(remainingSec / 60) + ":" + secondsString);
+      if (remainingSec === 0) {
+        clearInterval(pickTimerInterval);
+      }
+    };
+    pickTimerInterval = setInterval(update, 1000);
+    update();
+  }
+};
+
+$(function() {
+  // Set up the websocket back to the server.
+  websocket = new CheesyWebsocket("/alliance_selection/websocket", {
+    allianceSelection: function(event) { handleAllianceSelection(event.data); }
+  });
+});
@@ -74,6 +74,14 @@ var handleMatchLoad = function(data) {
   }
 };
 
+// Handles a websocket message to update the countdown of the rest still owed to this station's playoff alliance.
+var handleStationAllianceRest = function(data) {
+  handleAllianceRest(data, function(redCountdown, blueCountdown) {
+    var countdown = station[0] === "B" ? blueCountdown : redCountdown;
+    $("#allianceRest").text(countdown && station[0] !== "N" ? "Rest " + countdown : "");
+  });
+};
+
 // Handles a websocket message to update the team connection status.
 var handleArenaStatus = function(data) {
   stationStatus = data.AllianceStations[station];
@@ -131,6 +139,7 @@ $(function() {
 
   // Set up the websocket back to the server.
   websocket = new CheesyWebsocket("/displays/alliance_station/websocket", {
+    allianceRest: function(event) { handleStationAllianceRest(event.data); },
     allianceStationDisplayMode: function(event) { handleAllianceStationDisplayMode(event.data); },
     arenaStatus: function(event) { handleArenaStatus(event.data); },
     matchLoad: function(event) { handleMatchLoad(event.data); },
@@ -4,6 +4,7 @@
 // Client-side logic for the announcer display.
 
 var websocket;
+var currentMatchId;
 var teamTemplate = Handlebars.compile($("#teamTemplate").html());
 var matchResultTemplate = Handlebars.compile($("#matchResultTemplate").html());
 Handlebars.registerHelper("eachMapEntry", function(context, options) {
@@ -23,9 +24,48 @@ var handleAudienceDisplayMode = function(targetScreen) {
   }
 };
 
+// Shows the time that the given match is scheduled for, if it is part of a timed schedule.
+var setScheduledTime = function(match) {
+  if (match.Type === "practice" || match.Type === "qualification") {
+    $("#scheduledTime").text("Scheduled for " + moment(match.Time).format("h:mm A"));
+  } else {
+    $("#scheduledTime").text("");
+  }
+};
+
+// Handles a websocket message to update the scheduled time of the current match after the schedule is re-timed.
+var handleScheduleUpdate = function(data) {
+  $.each(data, function(i, match) {
+    if (match.Id === currentMatchId) {
+      setScheduledTime(match);
+    }
+  });
+};
+
+// Handles a websocket message to update the event status message.
+var handleEventStatus = function(data) {
+  $("#earlyLateMessage").text(data.EarlyLateMessage);
+};
+
+// Handles a websocket message to update the countdown of the rest still owed to the alliances in the current match.
+var handleAnnouncerAllianceRest = function(data) {
+  handleAllianceRest(data, function(redCountdown, blueCountdown) {
+    var countdowns = [];
+    if (redCountdown) {
+      countdowns.push("Alliance " + data.RedAllianceId + " rests for another " + redCountdown);
+    }
+    if (blueCountdown) {
+      countdowns.push("Alliance " + data.BlueAllianceId + " rests for another " + blueCountdown);
+    }
+    $("#allianceRest").text(countdowns.join("; "));
+  });
+};
+
 // Handles a websocket message to update the teams for the current match.
 var handleMatchLoad = function(data) {
   $("#matchName").text(data.MatchType + " Match " + data.Match.DisplayName);
+  currentMatchId = data.Match.Id;
+  setScheduledTime(data.Match);
 
   const teams = $("#teams");
   teams.empty();
@@ -76,7 +116,11 @@ var handleScorePosted = function(data) {
   blueRankings[data.Match.Blue2] = getRankingText(data.Match.Blue2, data.Rankings);
   blueRankings[data.Match.Blue3] = getRankingText(data.Match.Blue3, data.Rankings);
 
-  $("#scoreMatchName").text(data.MatchType + " Match " + data.Match.DisplayName);
+  var matchName = data.MatchType + " Match " + data.Match.DisplayName;
+  if (data.Match.TiebreakReason) {
+    matchName += " (won on tiebreaker: " + data.Match.TiebreakReason.toLowerCase() + ")";
+  }
+  $("#scoreMatchName").text(matchName);
   $("#redScoreDetails").html(matchResultTemplate({score: data.RedScoreSummary, rankings: redRankings}));
   $("#blueScoreDetails").html(matchResultTemplate({score: data.BlueScoreSummary, rankings: blueRankings}));
   $("#matchResult").modal("show");
@@ -125,11 +169,14 @@ var getRankingText = function(teamId, rankings) {
 $(function() {
   // Set up the websocket back to the server.
   websocket = new CheesyWebsocket("/displays/announcer/websocket", {
+    allianceRest: function(event) { handleAnnouncerAllianceRest(event.data); },
     audienceDisplayMode: function(event) { handleAudienceDisplayMode(event.data); },
+    eventStatus: function(event) { handleEventStatus(event.data); },
     matchLoad: function(event) { handleMatchLoad(event.data); },
     matchTime: function(event) { handleMatchTime(event.data); },
     matchTiming: function(event) { handleMatchTiming(event.data); },
     realtimeScore: function(event) { handleRealtimeScore(event.data); },
+    scheduleUpdate: function(event) { handleScheduleUpdate(event.data); },
     scorePosted: function(event) { handleScorePosted(event.data); }
   });
 
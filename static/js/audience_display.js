@@ -15,6 +15,7 @@ var currentMatch;
 var overlayCenteringHideParams;
 var overlayCenteringShowParams;
 var allianceSelectionTemplate = Handlebars.compile($("#allianceSelectionTemplate").html());
+var allianceSelectionTimerInterval;
 var sponsorImageTemplate = Handlebars.compile($("#sponsorImageTemplate").html());
 var sponsorTextTemplate = Handlebars.compile($("#sponsorTextTemplate").html());
 var slideshowTemplate = Handlebars.compile($("#slideshowTemplate").html());
@@ -153,9 +154,7 @@ var handleScorePosted = function(data) {
   $("#" + redSide + "FinalTeam1Avatar").attr("src", getAvatarUrl(data.Match.Red1));
   $("#" + redSide + "FinalTeam2Avatar").attr("src", getAvatarUrl(data.Match.Red2));
   $("#" + redSide + "FinalTeam3Avatar").attr("src", getAvatarUrl(data.Match.Red3));
-  $("#" + redSide + "FinalAutoPoints").text(data.RedScoreSummary.AutoPoints);
-  $("#" + redSide + "FinalTeleopPoints").text(data.RedScoreSummary.TeleopPoints);
-  $("#" + redSide + "FinalEndgamePoints").text(data.RedScoreSummary.EndgamePoints);
+  setFinalElementPoints(redSide, data.RedScoreSummary);
   $("#" + blueSide + "FinalScore").text(data.BlueScoreSummary.Score);
   $("#" + blueSide + "FinalTeam1").html(getRankingText(data.Match.Blue1, data.Rankings) + "" + data.Match.Blue1);
   $("#" + blueSide + "FinalTeam2").html(getRankingText(data.Match.Blue2, data.Rankings) + "" + data.Match.Blue2);
@@ -163,9 +162,8 @@ var handleScorePosted = function(data) {
   $("#" + blueSide + "FinalTeam1Avatar").attr("src", getAvatarUrl(data.Match.Blue1));
   $("#" + blueSide + "FinalTeam2Avatar").attr("src", getAvatarUrl(data.Match.Blue2));
   $("#" + blueSide + "FinalTeam3Avatar").attr("src", getAvatarUrl(data.Match.Blue3));
-  $("#" + blueSide + "FinalAutoPoints").text(data.BlueScoreSummary.AutoPoints);
-  $("#" + blueSide + "FinalTeleopPoints").text(data.BlueScoreSummary.TeleopPoints);
-  $("#" + blueSide + "FinalEndgamePoints").text(data.BlueScoreSummary.EndgamePoints);
+  setFinalElementPoints(blueSide, data.BlueScoreSummary);
+  setFinalTiebreak(data.Match);
   $("#finalSeriesStatus").text(data.SeriesStatus);
   $("#finalSeriesStatus").attr("data-leader", data.SeriesLeader);
   $("#finalMatchName").text(data.MatchType + " " + data.Match.DisplayName);
@@ -174,6 +172,23 @@ var handleScorePosted = function(data) {
   $("#bracketSvg").attr("src", "/api/bracket/svg?activeMatch=saved&v=" + new Date().getTime());
 };
 
+// Fills in the per-element and foul point breakdown on the given side of the final score screen.
+var setFinalElementPoints = function(side, scoreSummary) {
+  var elementPoints = scoreSummary.ElementPoints || {};
+  $("#" + side + "FinalBreakdown .final-element-points").each(function() {
+    $(this).text(elementPoints[$(this).attr("data-element")] || 0);
+  });
+  $("#" + side + "FinalBreakdown .final-foul-points").text(scoreSummary.FoulPoints);
+};
+
+// Shows the tiebreaker that decided a tied playoff match, if any, marking the winning side of the final score screen.
+var setFinalTiebreak = function(match) {
+  $(".final-tiebreak").toggle(match.TiebreakReason !== "");
+  $("#centerFinalBreakdown .final-tiebreak-text").text(match.TiebreakReason);
+  $("#" + redSide + "FinalBreakdown .final-tiebreak-text").text(match.Status === "R" ? "Won" : "");
+  $("#" + blueSide + "FinalBreakdown .final-tiebreak-text").text(match.Status === "B" ? "Won" : "");
+};
+
 // Handles a websocket message to play a sound to signal match start/stop/etc.
 var handlePlaySound = function(sound) {
   $("audio").each(function(k, v) {
@@ -185,13 +200,35 @@ var handlePlaySound = function(sound) {
 };
 
 // Handles a websocket message to update the alliance selection screen.
-var handleAllianceSelection = function(alliances) {
+var handleAllianceSelection = function(data) {
+  var alliances = data.Alliances;
   if (alliances && alliances.length > 0) {
-    var numColumns = alliances[0].TeamIds.length + 1;
+    // Leave a column on either side of the teams for the alliance number and any teams that declined its invitation.
+    var numColumns = alliances[0].TeamIds.length + 2;
     $.each(alliances, function(k, v) {
       v.Index = k + 1;
     });
-    $("#allianceSelection").html(allianceSelectionTemplate({alliances: alliances, numColumns: numColumns}));
+    $("#allianceSelection").html(allianceSelectionTemplate({alliances: alliances, numColumns: numColumns,
+        showTimer: data.ShowTimer}));
+  }
+
+  // Count down the time remaining for the current pick locally between updates from the server.
+  clearInterval(allianceSelectionTimerInterval);
+  if (data.ShowTimer) {
+    var receivedAt = Date.now();
+    var update = function() {
+      var remainingSec = Math.max(data.TimeRemainingSec - Math.floor((Date.now() - receivedAt) / 1000), 0);
+      var secondsString = String(remainingSec % 60);
+      if (secondsString.length === 1) {
+        secondsString = "0" + secondsString;
+      }
+      $("#allianceSelectionTimer").text(Math.floor(remainingSec / 60) + ":" + secondsString);
+      if (remainingSec === 0) {
+        clearInterval(allianceSelectionTimerInterval);
+      }
+    };
+    allianceSelectionTimerInterval = setInterval(update, 1000);
+    update();
   }
 };
 
@@ -27,6 +27,7 @@ var handleArenaStatus = function(data) {
     var teamRadioTextElement = $(teamElementPrefix + "Radio span");
     var teamRobotElement = $(teamElementPrefix + "Robot");
     var teamBypassElement = $(teamElementPrefix + "Bypass");
+    var teamDiagnosticsElement = $(teamElementPrefix + "Diagnostics");
 
     teamNotesTextElement.attr("data-station", station);
 
@@ -108,6 +109,8 @@ var handleArenaStatus = function(data) {
       }
     }
 
+    updateDiagnostics(teamDiagnosticsElement, stationStatus.DsConn);
+
     if (stationStatus.Estop) {
       teamBypassElement.attr("data-status-ok", false);
       teamBypassElement.text("ES");
@@ -121,6 +124,43 @@ var handleArenaStatus = function(data) {
   });
 };
 
+// Fills in the robot diagnostics that the given driver station has reported, or blanks them out if there is none.
+var updateDiagnostics = function(diagnosticsElement, dsConn) {
+  if (!dsConn || !dsConn.DsLinked) {
+    diagnosticsElement.attr("data-brownout", "");
+    diagnosticsElement.find("span:not(.diagnostic-brownout), div").text("");
+    diagnosticsElement.find(".diagnostic-log").attr("title", "");
+    return;
+  }
+
+  diagnosticsElement.attr("data-brownout", dsConn.Brownout);
+  diagnosticsElement.find(".diagnostic-cpu").text("CPU " + dsConn.CpuUsage + "%");
+  diagnosticsElement.find(".diagnostic-ram").text("RAM " + dsConn.RamUsage + "%");
+  diagnosticsElement.find(".diagnostic-disk").text("Disk " + dsConn.DiskUsage + "%");
+  diagnosticsElement.find(".diagnostic-can").text("CAN " + dsConn.CanUtilization + "%");
+  diagnosticsElement.find(".diagnostic-pdp").text("PDP " + dsConn.PdpTotalCurrent.toFixed(1) + "A");
+  diagnosticsElement.find(".diagnostic-radio").text(
+    "Radio " + dsConn.RadioSignalDb + "dB " + dsConn.RadioBandwidthMbps.toFixed(1) + "Mb"
+  );
+
+  var versions = [];
+  if (dsConn.DsVersion) {
+    versions.push("DS " + dsConn.DsVersion);
+  }
+  if (dsConn.RioVersion) {
+    versions.push("RIO " + dsConn.RioVersion);
+  }
+  if (dsConn.WpilibVersion) {
+    versions.push("WPILib " + dsConn.WpilibVersion);
+  }
+  diagnosticsElement.find(".diagnostic-versions").text(versions.join(" / "));
+
+  // Show the most recent log message, with the rest available on hover.
+  var logMessages = dsConn.LogMessages || [];
+  diagnosticsElement.find(".diagnostic-log").text(logMessages.length > 0 ? logMessages[logMessages.length - 1] : "");
+  diagnosticsElement.find(".diagnostic-log").attr("title", logMessages.join("\n"));
+};
+
 // Handles a websocket message to update the event status message.
 var handleEventStatus = function(data) {
   if (data.CycleTime === "") {
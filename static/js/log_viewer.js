@@ -0,0 +1,206 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Client-side methods for charting a driver station log on the log viewer page.
+
+var chartWidth = 1000;
+var chartHeight = 140;
+var chartMargin = {left: 50, right: 10, top: 20, bottom: 20};
+var lowBatteryThreshold = 8;
+var highTripTimeThresholdMs = 20;
+var linkStates = [
+  {name: "DS", field: "DsLinked", offColor: "#d9534f"},
+  {name: "Radio", field: "RadioLinked", offColor: "#d9534f"},
+  {name: "RIO", field: "RioLinked", offColor: "#d9534f"},
+  {name: "Robot", field: "RobotLinked", offColor: "#d9534f"},
+  {name: "Enabled", field: "Enabled", offColor: "#ccc"},
+];
+
+// Fetches the given log from the server and draws it.
+var loadLog = function(path) {
+  $.getJSON("/api/logs/" + path, function(data) {
+    renderLog(data);
+  }).fail(function(response) {
+    $("#logCharts").html($("<div class='alert alert-danger'></div>").text(response.responseText));
+  });
+};
+
+// Draws the summary, charts and messages for the given log data.
+var renderLog = function(data) {
+  var rows = data.Rows || [];
+  var periods = data.Periods;
+  var maxTimeSec = periods.TeleopEndSec;
+  if (rows.length > 0) {
+    maxTimeSec = Math.max(maxTimeSec, rows[rows.length - 1].MatchTimeSec);
+  }
+  var scale = {maxTimeSec: Math.ceil(maxTimeSec), periods: periods};
+
+  renderSummary(rows, periods);
+
+  var charts = $("#logCharts");
+  charts.empty();
+  charts.append(renderLinkChart(rows, scale));
+  charts.append(renderLineChart("Battery Voltage (V)", rows, scale, [{field: "BatteryVoltage", color: "#d9534f"}], 14));
+  charts.append(renderLineChart("Trip Time (ms)", rows, scale, [{field: "DsRobotTripTimeMs", color: "#337ab7"}], 20));
+  charts.append(renderLineChart("Missed Packets", rows, scale, [{field: "MissedPacketCount", color: "#f0ad4e"}], 10));
+  charts.append(renderLineChart("RX / TX Rate (Mbps)", rows, scale,
+      [{field: "RxRate", color: "#5cb85c", label: "RX"}, {field: "TxRate", color: "#5bc0de", label: "TX"}], 10));
+  charts.append(renderLineChart("Signal/Noise Ratio (dB)", rows, scale, [{field: "SignalNoiseRatio", color: "#777"}],
+      50));
+
+  var messages = $("#logMessages");
+  messages.empty();
+  $.each(rows, function(i, row) {
+    if (row.Message) {
+      var line = $("<tr></tr>");
+      line.append($("<td></td>").text(row.MatchTimeSec.toFixed(1) + " s"));
+      line.append($("<td></td>").text(row.Message));
+      messages.append(line);
+    }
+  });
+  if (messages.children().length === 0) {
+    messages.append("<tr><td colspan='2'>No messages were received from the driver station.</td></tr>");
+  }
+};
+
+// Draws a table showing at a glance whether anything went wrong during each period of the match.
+var renderSummary = function(rows, periods) {
+  var summaryPeriods = [
+    {name: "Auto", startSec: periods.AutoStartSec, endSec: periods.AutoEndSec},
+    {name: "Teleop", startSec: periods.TeleopStartSec, endSec: periods.TeleopEndSec},
+  ];
+  var table = $("<table class='table table-condensed'></table>");
+  table.append("<thead><tr><th>Period</th><th>DS Link</th><th>Robot Link</th><th>Brownout</th>" +
+      "<th>Min Battery</th><th>Max Trip Time</th><th>Packets Missed</th></tr></thead>");
+  var body = $("<tbody></tbody>");
+  $.each(summaryPeriods, function(i, period) {
+    var periodRows = $.grep(rows, function(row) {
+      return row.MatchTimeSec >= period.startSec && row.MatchTimeSec < period.endSec;
+    });
+    var line = $("<tr></tr>").append($("<td></td>").text(period.name));
+    if (periodRows.length === 0) {
+      line.append("<td colspan='6'>No data</td>");
+      body.append(line);
+      return;
+    }
+
+    var lostDs = false, lostRobot = false, brownout = false, minBattery = Infinity, maxTrip = 0;
+    $.each(periodRows, function(j, row) {
+      lostDs = lostDs || !row.DsLinked;
+      lostRobot = lostRobot || !row.RobotLinked;
+      brownout = brownout || row.Brownout;
+      minBattery = Math.min(minBattery, row.BatteryVoltage);
+      maxTrip = Math.max(maxTrip, row.DsRobotTripTimeMs);
+    });
+    var missed = periodRows[periodRows.length - 1].MissedPacketCount - periodRows[0].MissedPacketCount;
+    line.append(summaryCell(lostDs ? "Lost" : "OK", lostDs));
+    line.append(summaryCell(lostRobot ? "Lost" : "OK", lostRobot));
+    line.append(summaryCell(brownout ? "Yes" : "No", brownout));
+    line.append(summaryCell(minBattery.toFixed(2) + " V", minBattery < lowBatteryThreshold));
+    line.append(summaryCell(maxTrip + " ms", maxTrip > highTripTimeThresholdMs));
+    line.append(summaryCell(Math.max(missed, 0), missed > 0));
+    body.append(line);
+  });
+  table.append(body);
+  $("#logSummary").empty().append(table);
+};
+
+// Returns a summary table cell, highlighted if it indicates a problem.
+var summaryCell = function(text, isProblem) {
+  return $("<td></td>").text(text).addClass(isProblem ? "danger" : "success");
+};
+
+// Returns the SVG x-coordinate for the given match time.
+var timeToX = function(timeSec, scale) {
+  var width = chartWidth - chartMargin.left - chartMargin.right;
+  return chartMargin.left + width * timeSec / Math.max(scale.maxTimeSec, 1);
+};
+
+// Returns the opening markup for a chart, including the title, the shaded match periods and the time axis.
+var startChart = function(title, scale) {
+  var bottom = chartHeight - chartMargin.bottom;
+  var svg = "<svg xmlns='http://www.w3.org/2000/svg' viewBox='0 0 " + chartWidth + " " + chartHeight + "'>";
+  var periods = scale.periods;
+  var shading = [
+    {startSec: periods.AutoStartSec, endSec: periods.AutoEndSec, color: "#d9edf7"},
+    {startSec: periods.AutoEndSec, endSec: periods.TeleopStartSec, color: "#eee"},
+    {startSec: periods.TeleopStartSec, endSec: periods.TeleopEndSec, color: "#dff0d8"},
+  ];
+  $.each(shading, function(i, period) {
+    var x = timeToX(period.startSec, scale);
+    svg += "<rect x='" + x + "' y='" + chartMargin.top + "' width='" + (timeToX(period.endSec, scale) - x) +
+        "' height='" + (bottom - chartMargin.top) + "' fill='" + period.color + "' />";
+  });
+  for (var timeSec = 0; timeSec <= scale.maxTimeSec; timeSec += 15) {
+    var x = timeToX(timeSec, scale);
+    svg += "<line x1='" + x + "' y1='" + bottom + "' x2='" + x + "' y2='" + (bottom + 4) + "' stroke='#999' />";
+    svg += "<text x='" + x + "' y='" + (chartHeight - 4) + "' text-anchor='middle'>" + timeSec + "s</text>";
+  }
+  svg += "<line x1='" + chartMargin.left + "' y1='" + bottom + "' x2='" + (chartWidth - chartMargin.right) +
+      "' y2='" + bottom + "' stroke='#999' />";
+  svg += "<text class='log-chart-title' x='" + chartMargin.left + "' y='14'>" + title + "</text>";
+  return svg;
+};
+
+// Wraps the given SVG markup in a chart container.
+var finishChart = function(svg) {
+  return $("<div class='log-chart'></div>").html(svg + "</svg>");
+};
+
+// Draws a line chart of the given numeric fields over match time.
+var renderLineChart = function(title, rows, scale, series, minMaxValue) {
+  var maxValue = minMaxValue;
+  $.each(rows, function(i, row) {
+    $.each(series, function(j, line) {
+      maxValue = Math.max(maxValue, row[line.field]);
+    });
+  });
+  var top = chartMargin.top;
+  var bottom = chartHeight - chartMargin.bottom;
+  var valueToY = function(value) {
+    return bottom - (bottom - top) * value / maxValue;
+  };
+
+  var svg = startChart(title, scale);
+  $.each([0, maxValue / 2, maxValue], function(i, value) {
+    var y = valueToY(value);
+    svg += "<line x1='" + chartMargin.left + "' y1='" + y + "' x2='" + (chartWidth - chartMargin.right) + "' y2='" +
+        y + "' stroke='#ddd' />";
+    svg += "<text x='" + (chartMargin.left - 4) + "' y='" + (y + 4) + "' text-anchor='end'>" +
+        Math.round(value * 10) / 10 + "</text>";
+  });
+  var legendX = chartWidth - chartMargin.right;
+  $.each(series, function(i, line) {
+    var points = $.map(rows, function(row) {
+      return timeToX(row.MatchTimeSec, scale).toFixed(1) + "," + valueToY(row[line.field]).toFixed(1);
+    });
+    svg += "<polyline fill='none' stroke='" + line.color + "' stroke-width='1.5' points='" + points.join(" ") + "' />";
+    if (line.label) {
+      svg += "<text x='" + legendX + "' y='14' text-anchor='end' style='fill: " + line.color + "'>" + line.label +
+          "</text>";
+      legendX -= 30;
+    }
+  });
+  return finishChart(svg);
+};
+
+// Draws a band for each link state showing when it was up (green) or down over match time.
+var renderLinkChart = function(rows, scale) {
+  var top = chartMargin.top;
+  var bandHeight = (chartHeight - chartMargin.bottom - top) / linkStates.length;
+
+  var svg = startChart("Link States", scale);
+  $.each(linkStates, function(i, state) {
+    var y = top + i * bandHeight;
+    svg += "<text x='" + (chartMargin.left - 4) + "' y='" + (y + bandHeight / 2 + 4) + "' text-anchor='end'>" +
+        state.name + "</text>";
+    $.each(rows, function(j, row) {
+      var x = timeToX(row.MatchTimeSec, scale);
+      var endSec = j + 1 < rows.length ? rows[j + 1].MatchTimeSec : row.MatchTimeSec;
+      var width = Math.max(timeToX(endSec, scale) - x, 1);
+      var color = row[state.field] ? "#5cb85c" : state.offColor;
+      svg += "<rect x='" + x.toFixed(1) + "' y='" + (y + 2) + "' width='" + width.toFixed(1) + "' height='" +
+          (bandHeight - 4) + "' fill='" + color + "' />";
+    });
+  });
+  return finishChart(svg);
+};
@@ -58,32 +58,61 @@ var setFieldLights = function() {
   websocket.send("setFieldLights", $("input[name=fieldLights]:checked").val());
 };
 
-// Sends a websocket message to start the timeout.
-var startTimeout = function() {
+// Sends a websocket message to bring the next available backup team onto the given playoff alliance in place of the
+// team chosen from its lineup.
+var invokeBackup = function(allianceId) {
+  var teamId = parseInt($("#backupReplacedTeam" + allianceId).val());
+  if (confirm("Replace team " + teamId + " on alliance " + allianceId + " with the next available backup?")) {
+    websocket.send("invokeBackup", { allianceId: allianceId, teamId: teamId });
+  }
+};
+
+// Returns the timeout duration entered in minutes and seconds, in seconds.
+var getTimeoutDurationSec = function() {
   var duration = $("#timeoutDuration").val().split(":");
   var durationSec = parseFloat(duration[0]);
   if (duration.length > 1) {
     durationSec = durationSec * 60 + parseFloat(duration[1]);
   }
-  websocket.send("startTimeout", durationSec);
+  return durationSec;
+};
+
+// Sends a websocket message to start the timeout.
+var startTimeout = function() {
+  websocket.send("startTimeout", getTimeoutDurationSec());
+};
+
+// Sends a websocket message to start a timeout called by the given playoff alliance, using up one of its timeouts.
+var startAllianceTimeout = function(allianceId) {
+  websocket.send("startAllianceTimeout", { allianceId: allianceId, durationSec: getTimeoutDurationSec() });
+};
+
+// Sends a websocket message to shift the scheduled times of the remaining matches to match reality.
+var retimeSchedule = function() {
+  websocket.send("retimeSchedule");
 };
 
 // Sends a websocket message to update the realtime score
 var updateRealtimeScore = function() {
-  websocket.send("updateRealtimeScore", {
-    blueAuto: parseInt($("#blueAutoScore").val()),
-    redAuto: parseInt($("#redAutoScore").val()),
-    blueTeleop: parseInt($("#blueTeleopScore").val()),
-    redTeleop: parseInt($("#redTeleopScore").val()),
-    blueEndgame: parseInt($("#blueEndgameScore").val()),
-    redEndgame: parseInt($("#redEndgameScore").val())
-  })
+  var scores = { red: { ElementCounts: {} }, blue: { ElementCounts: {} } };
+  $(".score-input").each(function() {
+    var count = parseInt($(this).val());
+    var score = scores[$(this).attr("data-alliance")];
+    if ($(this).attr("data-field")) {
+      score[$(this).attr("data-field")] = isNaN(count) ? 0 : count;
+    } else {
+      score.ElementCounts[$(this).attr("data-element")] = isNaN(count) ? 0 : count;
+    }
+  });
+  websocket.send("updateRealtimeScore", scores);
 };
 
+// Moves the focus to the next scoring input, wrapping around to the first, when enter is pressed.
 var scoreKeyHandler = function(e) {
   var keycode = (event.keyCode ? event.keyCode : event.which);
   if (keycode == 13) {
-    $('#' + $(event.target).attr("data-next")).focus().select();
+    var inputs = $(".score-input");
+    inputs.eq((inputs.index(event.target) + 1) % inputs.length).focus().select();
   }
 };
 
@@ -102,6 +131,21 @@ var setTestMatchName = function() {
   websocket.send("setTestMatchName", $("#testMatchName").val());
 };
 
+// Sends a websocket message to set the game-specific message that each alliance will be shown.
+var setGameData = function() {
+  websocket.send("setGameData", { Red: $("#redGameData").val(), Blue: $("#blueGameData").val() });
+};
+
+// Handles a websocket message to show the game-specific messages set from another scorekeeper station, leaving alone
+// whichever one is being edited here.
+var handleGameData = function(data) {
+  $.each({"#redGameData": data.RedGameData, "#blueGameData": data.BlueGameData}, function(selector, gameData) {
+    if (!$(selector).is(":focus")) {
+      $(selector).val(gameData);
+    }
+  });
+};
+
 // Handles a websocket message to update the team connection status.
 var handleArenaStatus = function(data) {
   // If getting data for the wrong match (e.g. after a server restart), reload the page.
@@ -176,18 +220,12 @@ var handleArenaStatus = function(data) {
       $("#discardResults").prop("disabled", true);
       $("#editResults").prop("disabled", true);
       $("#startTimeout").prop("disabled", false);
-      $("#blueAutoScore").val("0");
-      $("#redAutoScore").val("0");
-      $("#blueTeleopScore").val("0");
-      $("#redTeleopScore").val("0");
-      $("#blueEndgameScore").val("0");
-      $("#redEndgameScore").val("0");
-      $("#blueAutoScore").prop("disabled", true);
-      $("#redAutoScore").prop("disabled", true);
-      $("#blueTeleopScore").prop("disabled", true);
-      $("#redTeleopScore").prop("disabled", true);
-      $("#blueEndgameScore").prop("disabled", true);
-      $("#redEndgameScore").prop("disabled", true);
+      $(".alliance-timeout, .alliance-backup").each(function() {
+        $(this).prop("disabled", $(this).attr("data-remaining") === "0");
+      });
+      $(".score-input").val("0");
+      $(".score-input").prop("disabled", true);
+      $(".game-data-input").prop("disabled", false);
       break;
     case "START_MATCH":
     case "WARMUP_PERIOD":
@@ -201,12 +239,9 @@ var handleArenaStatus = function(data) {
       $("#discardResults").prop("disabled", true);
       $("#editResults").prop("disabled", true);
       $("#startTimeout").prop("disabled", true);
-      $("#blueAutoScore").prop("disabled", false);
-      $("#redAutoScore").prop("disabled", false);
-      $("#blueTeleopScore").prop("disabled", false);
-      $("#redTeleopScore").prop("disabled", false);
-      $("#blueEndgameScore").prop("disabled", false);
-      $("#redEndgameScore").prop("disabled", false);
+      $(".alliance-timeout, .alliance-backup").prop("disabled", true);
+      $(".score-input").prop("disabled", false);
+      $(".game-data-input").prop("disabled", true);
       break;
     case "POST_MATCH":
       $("#startMatch").prop("disabled", true);
@@ -216,12 +251,9 @@ var handleArenaStatus = function(data) {
       $("#discardResults").prop("disabled", false);
       $("#editResults").prop("disabled", false);
       $("#startTimeout").prop("disabled", true);
-      $("#blueAutoScore").prop("disabled", false);
-      $("#redAutoScore").prop("disabled", false);
-      $("#blueTeleopScore").prop("disabled", false);
-      $("#redTeleopScore").prop("disabled", false);
-      $("#blueEndgameScore").prop("disabled", false);
-      $("#redEndgameScore").prop("disabled", false);
+      $(".alliance-timeout, .alliance-backup").prop("disabled", true);
+      $(".score-input").prop("disabled", false);
+      $(".game-data-input").prop("disabled", true);
       break;
     case "TIMEOUT_ACTIVE":
       $("#startMatch").prop("disabled", true);
@@ -231,12 +263,9 @@ var handleArenaStatus = function(data) {
       $("#discardResults").prop("disabled", true);
       $("#editResults").prop("disabled", true);
       $("#startTimeout").prop("disabled", true);
-      $("#blueAutoScore").prop("disabled", false);
-      $("#redAutoScore").prop("disabled", false);
-      $("#blueTeleopScore").prop("disabled", false);
-      $("#redTeleopScore").prop("disabled", false);
-      $("#blueEndgameScore").prop("disabled", false);
-      $("#redEndgameScore").prop("disabled", false);
+      $(".alliance-timeout, .alliance-backup").prop("disabled", true);
+      $(".score-input").prop("disabled", false);
+      $(".game-data-input").prop("disabled", true);
       break;
     case "POST_TIMEOUT":
       $("#startMatch").prop("disabled", true);
@@ -246,15 +275,15 @@ var handleArenaStatus = function(data) {
       $("#discardResults").prop("disabled", true);
       $("#editResults").prop("disabled", true);
       $("#startTimeout").prop("disabled", true);
-      $("#blueAutoScore").prop("disabled", false);
-      $("#redAutoScore").prop("disabled", false);
-      $("#blueTeleopScore").prop("disabled", false);
-      $("#redTeleopScore").prop("disabled", false);
-      $("#blueEndgameScore").prop("disabled", false);
-      $("#redEndgameScore").prop("disabled", false);
+      $(".alliance-timeout, .alliance-backup").prop("disabled", true);
+      $(".score-input").prop("disabled", false);
+      $(".game-data-input").prop("disabled", true);
       break;
   }
 
+  $("#gameDataStatus").text(data.GameDataRevealed ? "Revealed" : "Hidden");
+  $("#gameDataStatus").attr("data-revealed", data.GameDataRevealed);
+
   $("#accessPointStatus").attr("data-status", data.AccessPointStatus);
   $("#switchStatus").attr("data-status", data.SwitchStatus);
 
@@ -299,30 +328,18 @@ var handleMatchTime = function(data) {
 var handleRealtimeScore = function(data) {
   $("#redScore").text(data.Red.ScoreSummary.Score);
   $("#blueScore").text(data.Blue.ScoreSummary.Score);
-  if (parseInt($("#redAutoScore").val()) != data.Red.Score.AutoPoints) {
-    $("#redAutoScore").val(data.Red.Score.AutoPoints);
-  }
-  if (parseInt($("#redTeleopScore").val()) != data.Red.Score.TeleopPoints) {
-    $("#redTeleopScore").val(data.Red.Score.TeleopPoints);
-  }
-  if (parseInt($("#redEndgameScore").val()) != data.Red.Score.EndgamePoints) {
-    $("#redEndgameScore").val(data.Red.Score.EndgamePoints);
-  }
-  if (parseInt($("#blueAutoScore").val()) != data.Blue.Score.AutoPoints) {
-    $("#blueAutoScore").val(data.Blue.Score.AutoPoints);
-  }
-  if (parseInt($("#blueTeleopScore").val()) != data.Blue.Score.TeleopPoints) {
-    $("#blueTeleopScore").val(data.Blue.Score.TeleopPoints);
-  }
-  if (parseInt($("#blueEndgameScore").val()) != data.Blue.Score.EndgamePoints) {
-    $("#blueEndgameScore").val(data.Blue.Score.EndgamePoints);
-  }
-}
-
-// Handles a websocket message to update the audience display screen selector.
-var handleAudienceDisplayMode = function(data) {
-  $("input[name=audienceDisplay]:checked").prop("checked", false);
-  $("input[name=audienceDisplay][value=" + data + "]").prop("checked", true);
+  $(".score-input").each(function() {
+    var score = $(this).attr("data-alliance") === "red" ? data.Red.Score : data.Blue.Score;
+    var count;
+    if ($(this).attr("data-field")) {
+      count = score[$(this).attr("data-field")];
+    } else {
+      count = (score.ElementCounts || {})[$(this).attr("data-element")] || 0;
+    }
+    if (parseInt($(this).val()) !== count) {
+      $(this).val(count);
+    }
+  });
 };
 
 // Handles a websocket message to update the alliance station display screen selector.
@@ -337,6 +354,18 @@ var handleFieldLights = function(data) {
   $("input[name=fieldLights][value=" + data.Lights + "]").prop("checked", true);
 };
 
+// Handles a websocket message to update the number of timeouts an alliance has left after it has called one.
+var handleAllianceTimeouts = function(data) {
+  $("#allianceTimeouts" + data.AllianceId).text(data.TimeoutsRemaining);
+  $("#allianceTimeouts" + data.AllianceId).siblings(".alliance-timeout").attr("data-remaining", data.TimeoutsRemaining);
+};
+
+// Handles a websocket message announcing the backup team that has joined an alliance, reloading the page to show it.
+var handleBackupInvoked = function(data) {
+  alert("Team " + data.TeamId + " has joined alliance " + data.AllianceId + " as a backup.");
+  location.reload();
+};
+
 // Handles a websocket message to update the event status message.
 var handleEventStatus = function(data) {
   if (data.CycleTime === "") {
@@ -354,9 +383,12 @@ $(function() {
   // Set up the websocket back to the server.
   websocket = new CheesyWebsocket("/match_play/websocket", {
     allianceStationDisplayMode: function(event) { handleAllianceStationDisplayMode(event.data); },
+    allianceTimeouts: function(event) { handleAllianceTimeouts(event.data); },
     arenaStatus: function(event) { handleArenaStatus(event.data); },
     audienceDisplayMode: function(event) { handleAudienceDisplayMode(event.data); },
+    backupInvoked: function(event) { handleBackupInvoked(event.data); },
     eventStatus: function(event) { handleEventStatus(event.data); },
+    gameData: function(event) { handleGameData(event.data); },
     matchTime: function(event) { handleMatchTime(event.data); },
     matchTiming: function(event) { handleMatchTiming(event.data); },
     realtimeScore: function(event) { handleRealtimeScore(event.data); },
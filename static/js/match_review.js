@@ -14,6 +14,10 @@ $("form").submit(function() {
 
   matchResult.RedScore = allianceResults["red"].score;
   matchResult.BlueScore = allianceResults["blue"].score;
+  matchResult.RedCards = allianceResults["red"].cards;
+  matchResult.BlueCards = allianceResults["blue"].cards;
+  // The cards are edited as issued, so the server works out which yellow cards become red cards again.
+  matchResult.CarryoverRedCards = {};
   var matchResultJson = JSON.stringify(matchResult);
 
   // Inject the JSON data into the form as hidden inputs.
@@ -28,9 +32,13 @@ var renderResults = function(alliance) {
   var scoreContent = scoreTemplate(result);
   $("#" + alliance + "Score").html(scoreContent);
 
-  getInputElement(alliance, "AutoPoints").val(result.score.AutoPoints);
-  getInputElement(alliance, "TeleopPoints").val(result.score.TeleopPoints);
-  getInputElement(alliance, "EndgamePoints").val(result.score.EndgamePoints);
+  var elementCounts = result.score.ElementCounts || {};
+  $("#" + alliance + "Score input[data-element]").each(function() {
+    $(this).val(elementCounts[$(this).attr("data-element")] || 0);
+  });
+  $("#" + alliance + "Score select[data-team]").each(function() {
+    $(this).val(result.cards[$(this).attr("data-team")] || "");
+  });
 };
 
 // Converts the current form values back into JSON structures and caches them.
@@ -41,9 +49,21 @@ var updateResults = function(alliance) {
     formData[v.name] = v.value;
   });
 
-  result.score.AutoPoints = parseInt(formData[alliance + "AutoPoints"]);
-  result.score.TeleopPoints = parseInt(formData[alliance + "TeleopPoints"]);
-  result.score.EndgamePoints = parseInt(formData[alliance + "EndgamePoints"]);
+  result.score.ElementCounts = {};
+  $("#" + alliance + "Score input[data-element]").each(function() {
+    var elementId = $(this).attr("data-element");
+    result.score.ElementCounts[elementId] = parseInt(formData[alliance + "Element_" + elementId]) || 0;
+  });
+  result.score.Fouls = parseInt(formData[alliance + "Fouls"]) || 0;
+  result.score.TechFouls = parseInt(formData[alliance + "TechFouls"]) || 0;
+
+  result.cards = {};
+  $("#" + alliance + "Score select[data-team]").each(function() {
+    var card = formData[alliance + "Card" + $(this).attr("name").slice(-1)];
+    if (card) {
+      result.cards[$(this).attr("data-team")] = card;
+    }
+  });
 };
 
 // Returns the form input element having the given parameters.
@@ -71,3 +71,35 @@ var getCountdown = function(matchState, matchTimeSec) {
       return 0;
   }
 };
+
+var allianceRestInterval;
+
+// Handles a websocket message containing how much longer each alliance in the loaded playoff match needs to rest.
+// Calls the provided callback every second with the red and blue countdowns, which are empty once rested.
+var handleAllianceRest = function(data, callback) {
+  clearInterval(allianceRestInterval);
+  var receivedAt = Date.now();
+  var update = function() {
+    var elapsedSec = Math.floor((Date.now() - receivedAt) / 1000);
+    var redSec = Math.max(data.RedRemainingSec - elapsedSec, 0);
+    var blueSec = Math.max(data.BlueRemainingSec - elapsedSec, 0);
+    callback(formatRestCountdown(redSec), formatRestCountdown(blueSec));
+    if (redSec === 0 && blueSec === 0) {
+      clearInterval(allianceRestInterval);
+    }
+  };
+  allianceRestInterval = setInterval(update, 1000);
+  update();
+};
+
+// Formats the given number of seconds of rest as minutes and seconds, or as an empty string if there is none left.
+var formatRestCountdown = function(restSec) {
+  if (restSec <= 0) {
+    return "";
+  }
+  var secondsString = String(restSec % 60);
+  if (secondsString.length === 1) {
+    secondsString = "0" + secondsString;
+  }
+  return Math.floor(restSec / 60) + ":" + secondsString;
+};
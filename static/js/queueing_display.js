@@ -28,6 +28,20 @@ var handleMatchTime = function(data) {
   });
 };
 
+// Handles a websocket message to update the countdown of the rest still owed to the alliances in the match on the field.
+var handleQueueingAllianceRest = function(data) {
+  handleAllianceRest(data, function(redCountdown, blueCountdown) {
+    var countdowns = [];
+    if (redCountdown) {
+      countdowns.push("A" + data.RedAllianceId + " rest " + redCountdown);
+    }
+    if (blueCountdown) {
+      countdowns.push("A" + data.BlueAllianceId + " rest " + blueCountdown);
+    }
+    $("#allianceRest").text(countdowns.join(" / "));
+  });
+};
+
 // Handles a websocket message to update the event status message.
 var handleEventStatus = function(data) {
   $("#earlyLateMessage").text(data.EarlyLateMessage);
@@ -36,9 +50,11 @@ var handleEventStatus = function(data) {
 $(function() {
   // Set up the websocket back to the server.
   websocket = new CheesyWebsocket("/displays/queueing/websocket", {
+    allianceRest: function(event) { handleQueueingAllianceRest(event.data); },
     eventStatus: function(event) { handleEventStatus(event.data); },
     matchLoad: function(event) { handleMatchLoad(event.data); },
     matchTime: function(event) { handleMatchTime(event.data); },
     matchTiming: function(event) { handleMatchTiming(event.data); },
+    scheduleUpdate: function(event) { location.reload(); },
   });
 });
@@ -27,9 +27,59 @@ var handlePlcIoChange = function(data) {
   });
 };
 
+// Sends a websocket message to start or stop simulating the driver stations of the teams in the current match.
+var setDriverStationSimulatorEnabled = function(enabled) {
+  websocket.send("setDriverStationSimulatorEnabled", enabled);
+};
+
+// Sends a websocket message to change what the simulated driver station in the given station reports.
+var setSimulatedRobot = function(station) {
+  websocket.send("setSimulatedRobot", {
+    Station: station,
+    RobotLinked: $("#simulatedRobotLinked" + station).prop("checked"),
+    BatteryVoltage: parseFloat($("#simulatedBattery" + station).val()),
+    TripTimeMs: parseInt($("#simulatedTripTime" + station).val()),
+  });
+};
+
+// Handles a websocket message to update the controls for the simulated driver stations.
+var handleDriverStationSimulator = function(data) {
+  $("#simulatorStartButton").prop("disabled", data.Enabled);
+  $("#simulatorStopButton").prop("disabled", !data.Enabled);
+  $.each(["R1", "R2", "R3", "B1", "B2", "B3"], function(index, station) {
+    const simulatedStation = data.Stations[station];
+    $("#simulatedTeam" + station).text(simulatedStation ? simulatedStation.TeamId : "");
+    $("#simulatedRobotLinked" + station).prop("disabled", !simulatedStation);
+    $("#simulatedBattery" + station).prop("disabled", !simulatedStation);
+    $("#simulatedTripTime" + station).prop("disabled", !simulatedStation);
+    if (simulatedStation) {
+      $("#simulatedRobotLinked" + station).prop("checked", simulatedStation.RobotLinked);
+      $("#simulatedBattery" + station).val(simulatedStation.BatteryVoltage);
+      $("#simulatedTripTime" + station).val(simulatedStation.TripTimeMs);
+    }
+  });
+};
+
+// Handles a websocket message to show what the field is receiving from each driver station.
+var handleArenaStatus = function(data) {
+  $.each(data.AllianceStations, function(station, allianceStation) {
+    const dsConn = allianceStation.DsConn;
+    let status = "";
+    if (dsConn && dsConn.DsLinked) {
+      status = "DS";
+      if (dsConn.RobotLinked) {
+        status += ", Robot " + dsConn.BatteryVoltage.toFixed(1) + "V " + dsConn.DsRobotTripTimeMs + "ms";
+      }
+    }
+    $("#simulatedStatus" + station).text(status);
+  });
+};
+
 $(function() {
   // Set up the websocket back to the server.
   websocket = new CheesyWebsocket("/setup/field_testing/websocket", {
-    plcIoChange: function(event) { handlePlcIoChange(event.data); }
+    arenaStatus: function(event) { handleArenaStatus(event.data); },
+    driverStationSimulator: function(event) { handleDriverStationSimulator(event.data); },
+    plcIoChange: function(event) { handlePlcIoChange(event.data); },
   });
 });
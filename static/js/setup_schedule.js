@@ -74,8 +74,9 @@ var deleteBlock = function(blockNumber) {
   updateStats();
 };
 
-// Dynamically generates and posts a form containing the schedule blocks to the server for population.
-var generateSchedule = function() {
+// Dynamically generates and posts a form containing the schedule blocks to the server for population, optionally keeping
+// the previously generated schedules for comparison.
+var generateSchedule = function(addCandidate) {
   var form = $("#scheduleForm");
   form.attr("method", "POST");
   form.attr("action", "/setup/schedule/generate");
@@ -94,6 +95,7 @@ var generateSchedule = function() {
     i++;
   });
   addField("numScheduleBlocks", i);
+  addField("addCandidate", addCandidate ? "true" : "false");
   form.submit();
 };
 
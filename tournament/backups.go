@@ -0,0 +1,80 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Functions for calling a backup robot onto a playoff alliance.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/model"
+)
+
+// Brings the next available backup team onto the given playoff alliance in place of the given team in its lineup,
+// using up one of the alliance's backups. Returns the ID of the backup team.
+func InvokeBackup(database *model.Database, allianceId, replacedTeamId int) (int, error) {
+	alliance, err := database.GetAllianceById(allianceId)
+	if err != nil {
+		return 0, err
+	}
+	if alliance == nil {
+		return 0, fmt.Errorf("alliance %d does not exist", allianceId)
+	}
+	if alliance.BackupsRemaining() == 0 {
+		return 0, fmt.Errorf("alliance %d has no backups remaining", allianceId)
+	}
+	lineupPosition := -1
+	for i, teamId := range alliance.Lineup {
+		if teamId == replacedTeamId {
+			lineupPosition = i
+			break
+		}
+	}
+	if replacedTeamId == 0 || lineupPosition == -1 {
+		return 0, fmt.Errorf("team %d is not in the lineup of alliance %d", replacedTeamId, allianceId)
+	}
+
+	backupTeamId, err := NextBackupTeam(database)
+	if err != nil {
+		return 0, err
+	}
+	if backupTeamId == 0 {
+		return 0, fmt.Errorf("there are no backup teams available")
+	}
+
+	alliance.TeamIds = append(alliance.TeamIds, backupTeamId)
+	alliance.BackupTeamIds = append(alliance.BackupTeamIds, backupTeamId)
+	alliance.Lineup[lineupPosition] = backupTeamId
+	if err = database.UpdateAlliance(alliance); err != nil {
+		return 0, err
+	}
+	return backupTeamId, nil
+}
+
+// Returns the highest-ranked team that isn't already a member of a playoff alliance and didn't decline an invitation
+// during alliance selection, or zero if there is none.
+func NextBackupTeam(database *model.Database) (int, error) {
+	alliances, err := database.GetAllAlliances()
+	if err != nil {
+		return 0, err
+	}
+	rankings, err := database.GetAllRankings()
+	if err != nil {
+		return 0, err
+	}
+
+	allianceTeams := make(map[int]struct{})
+	for _, alliance := range alliances {
+		for _, teamId := range alliance.TeamIds {
+			allianceTeams[teamId] = struct{}{}
+		}
+		for _, teamId := range alliance.DeclinedTeamIds {
+			allianceTeams[teamId] = struct{}{}
+		}
+	}
+	for _, ranking := range rankings {
+		if _, ok := allianceTeams[ranking.TeamId]; !ok {
+			return ranking.TeamId, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package tournament
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInvokeBackup(t *testing.T) {
+	database := setupTestDb(t)
+	CreateTestAlliances(database, 2)
+	database.CreateRanking(&game.Ranking{TeamId: 101, Rank: 1})
+	database.CreateRanking(&game.Ranking{TeamId: 1503, Rank: 4})
+	database.CreateRanking(&game.Ranking{TeamId: 254, Rank: 3})
+	database.CreateRanking(&game.Ranking{TeamId: 202, Rank: 2})
+
+	backupTeamId, err := NextBackupTeam(database)
+	assert.Nil(t, err)
+	assert.Equal(t, 254, backupTeamId)
+
+	_, err = InvokeBackup(database, 3, 301)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "alliance 3 does not exist", err.Error())
+	}
+	_, err = InvokeBackup(database, 2, 104)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "team 104 is not in the lineup of alliance 2", err.Error())
+	}
+
+	backupTeamId, err = InvokeBackup(database, 2, 201)
+	assert.Nil(t, err)
+	assert.Equal(t, 254, backupTeamId)
+	alliance, _ := database.GetAllianceById(2)
+	assert.Equal(t, []int{201, 202, 203, 204, 254}, alliance.TeamIds)
+	assert.Equal(t, []int{254}, alliance.BackupTeamIds)
+	assert.Equal(t, [3]int{202, 254, 203}, alliance.Lineup)
+
+	// The backup that was used is no longer available to anyone else, and the alliance has no backups left.
+	backupTeamId, err = NextBackupTeam(database)
+	assert.Nil(t, err)
+	assert.Equal(t, 1503, backupTeamId)
+	_, err = InvokeBackup(database, 2, 202)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "alliance 2 has no backups remaining", err.Error())
+	}
+
+	assert.Nil(t, database.DeleteRanking(1503))
+	_, err = InvokeBackup(database, 1, 101)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "there are no backup teams available", err.Error())
+	}
+	alliance, _ = database.GetAllianceById(1)
+	assert.Equal(t, 0, alliance.BackupsUsed())
+}
+
+func TestNextBackupTeamSkipsDeclinedTeams(t *testing.T) {
+	database := setupTestDb(t)
+	CreateTestAlliances(database, 2)
+	database.CreateRanking(&game.Ranking{TeamId: 254, Rank: 3})
+	database.CreateRanking(&game.Ranking{TeamId: 1114, Rank: 4})
+
+	alliance, _ := database.GetAllianceById(1)
+	alliance.DeclinedTeamIds = []int{254}
+	assert.Nil(t, database.UpdateAlliance(alliance))
+	backupTeamId, err := NextBackupTeam(database)
+	assert.Nil(t, err)
+	assert.Equal(t, 1114, backupTeamId)
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Functions for tracking the yellow and red cards that teams carry from match to match.
+
+package tournament
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"strconv"
+)
+
+// Returns the set of teams carrying a yellow card into the given match, i.e. those that received a yellow or red card
+// in a completed match of the same type that precedes it in the schedule.
+func GetTeamsCarryingYellowCards(database *model.Database, match *model.Match) (map[int]bool, error) {
+	teams := make(map[int]bool)
+	if !match.ShouldUpdateCards() {
+		return teams, nil
+	}
+
+	matches, err := database.GetMatchesByType(match.Type)
+	if err != nil {
+		return nil, err
+	}
+	for _, otherMatch := range matches {
+		if otherMatch.Id == match.Id {
+			break
+		}
+		if !otherMatch.IsComplete() {
+			continue
+		}
+		matchResult, err := database.GetMatchResultForMatch(otherMatch.Id)
+		if err != nil {
+			return nil, err
+		}
+		if matchResult == nil {
+			continue
+		}
+		for _, cards := range []map[string]string{matchResult.RedCards, matchResult.BlueCards} {
+			for teamIdString, card := range cards {
+				if card == model.YellowCard || card == model.RedCard {
+					teamId, _ := strconv.Atoi(teamIdString)
+					teams[teamId] = true
+				}
+			}
+		}
+	}
+	return teams, nil
+}
+
+// Upgrades any yellow card in the given match result to a red card if the team was already carrying a yellow card
+// from an earlier match, first undoing any upgrade made when the result was previously saved.
+func ApplyYellowCardCarryover(database *model.Database, match *model.Match, matchResult *model.MatchResult) error {
+	carryingTeams, err := GetTeamsCarryingYellowCards(database, match)
+	if err != nil {
+		return err
+	}
+	carryoverRedCards := make(map[string]bool)
+	for _, cards := range []map[string]string{matchResult.RedCards, matchResult.BlueCards} {
+		for teamIdString, card := range cards {
+			if card == model.RedCard && matchResult.CarryoverRedCards[teamIdString] {
+				card = model.YellowCard
+			}
+			teamId, _ := strconv.Atoi(teamIdString)
+			if card == model.YellowCard && carryingTeams[teamId] {
+				card = model.RedCard
+				carryoverRedCards[teamIdString] = true
+			}
+			cards[teamIdString] = card
+		}
+	}
+	matchResult.CarryoverRedCards = carryoverRedCards
+	return nil
+}
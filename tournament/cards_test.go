@@ -0,0 +1,90 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package tournament
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestYellowCardCarryover(t *testing.T) {
+	database := setupTestDb(t)
+
+	match1 := model.Match{Type: "qualification", DisplayName: "1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6, Status: game.RedWonMatch}
+	database.CreateMatch(&match1)
+	matchResult1 := model.BuildTestMatchResult(match1.Id, 1)
+	matchResult1.RedCards = map[string]string{"1": model.YellowCard}
+	matchResult1.BlueCards = map[string]string{"5": model.RedCard}
+	database.CreateMatchResult(matchResult1)
+
+	match2 := model.Match{Type: "qualification", DisplayName: "2", Red1: 1, Red2: 3, Red3: 5, Blue1: 2, Blue2: 4,
+		Blue3: 6}
+	database.CreateMatch(&match2)
+	match3 := model.Match{Type: "elimination", DisplayName: "F-1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6}
+	database.CreateMatch(&match3)
+
+	// Cards from an earlier match should carry over to a later one but not back to the same match.
+	carryingTeams, err := GetTeamsCarryingYellowCards(database, &match1)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]bool{}, carryingTeams)
+	carryingTeams, err = GetTeamsCarryingYellowCards(database, &match2)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]bool{1: true, 5: true}, carryingTeams)
+
+	// Cards shouldn't carry over between qualification and playoff matches.
+	carryingTeams, err = GetTeamsCarryingYellowCards(database, &match3)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]bool{}, carryingTeams)
+
+	// A second yellow card should be upgraded to a red card.
+	matchResult2 := model.NewMatchResult()
+	matchResult2.MatchId = match2.Id
+	matchResult2.RedCards = map[string]string{"1": model.YellowCard, "3": model.YellowCard, "5": model.YellowCard}
+	matchResult2.BlueCards = map[string]string{"2": model.YellowCard}
+	assert.Nil(t, ApplyYellowCardCarryover(database, &match2, matchResult2))
+	assert.Equal(t, map[string]string{"1": model.RedCard, "3": model.YellowCard, "5": model.RedCard},
+		matchResult2.RedCards)
+	assert.Equal(t, map[string]string{"2": model.YellowCard}, matchResult2.BlueCards)
+	assert.Equal(t, map[string]bool{"1": true, "5": true}, matchResult2.CarryoverRedCards)
+
+	// An upgrade should be undone if the earlier card no longer carries over, but not a red card given directly.
+	matchResult1.RedCards = map[string]string{}
+	database.UpdateMatchResult(matchResult1)
+	matchResult2.BlueCards["2"] = model.RedCard
+	assert.Nil(t, ApplyYellowCardCarryover(database, &match2, matchResult2))
+	assert.Equal(t, map[string]string{"1": model.YellowCard, "3": model.YellowCard, "5": model.RedCard},
+		matchResult2.RedCards)
+	assert.Equal(t, map[string]string{"2": model.RedCard}, matchResult2.BlueCards)
+	assert.Equal(t, map[string]bool{"5": true}, matchResult2.CarryoverRedCards)
+}
+
+func TestCalculateRankingsWithRedCard(t *testing.T) {
+	database := setupTestDb(t)
+
+	match := model.Match{Type: "qualification", DisplayName: "1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6, Status: game.RedWonMatch}
+	database.CreateMatch(&match)
+	matchResult := model.BuildTestMatchResult(match.Id, 1)
+	matchResult.RedCards = map[string]string{"2": model.RedCard}
+	database.CreateMatchResult(matchResult)
+
+	rankings, err := CalculateRankings(database, false)
+	assert.Nil(t, err)
+	rankingsByTeam := make(map[int]game.Ranking)
+	for _, ranking := range rankings {
+		rankingsByTeam[ranking.TeamId] = ranking
+	}
+	assert.Equal(t, 2, rankingsByTeam[1].RankingPoints)
+	assert.Equal(t, 45, rankingsByTeam[1].AutoPoints)
+	assert.Equal(t, 0, rankingsByTeam[2].RankingPoints)
+	assert.Equal(t, 0, rankingsByTeam[2].AutoPoints)
+	assert.Equal(t, 0, rankingsByTeam[2].Wins)
+	assert.Equal(t, 1, rankingsByTeam[2].Played)
+	assert.Equal(t, 1, rankingsByTeam[2].Disqualifications)
+	assert.Equal(t, 0, rankingsByTeam[4].RankingPoints)
+	assert.Equal(t, 1, rankingsByTeam[4].Losses)
+}
@@ -46,6 +46,15 @@ func CalculateRankings(database *model.Database, preservePreviousRank bool) (gam
 		}
 	}
 
+	// Derive the random tiebreaker from the event's seed so that the rankings are reproducible.
+	eventSettings, err := database.GetEventSettings()
+	if err != nil {
+		return nil, err
+	}
+	for teamId, ranking := range rankings {
+		ranking.Random = game.RandomTiebreakerValue(eventSettings.TiebreakerSeed, teamId)
+	}
+
 	// Retrieve old rankings so that we can display changes in rank as a result of this calculation.
 	oldRankings, err := database.GetAllRankings()
 	if err != nil {
@@ -85,10 +94,12 @@ func addMatchResultToRankings(
 		rankings[teamId] = ranking
 	}
 
+	// A team that received a red card contributes nothing to its rankings for the match.
+	disqualified := matchResult.IsDisqualified(teamId)
 	if isRed {
-		ranking.AddScoreSummary(matchResult.RedScoreSummary(), matchResult.BlueScoreSummary())
+		ranking.AddScoreSummary(matchResult.RedScoreSummary(), matchResult.BlueScoreSummary(), disqualified)
 	} else {
-		ranking.AddScoreSummary(matchResult.BlueScoreSummary(), matchResult.RedScoreSummary())
+		ranking.AddScoreSummary(matchResult.BlueScoreSummary(), matchResult.RedScoreSummary(), disqualified)
 	}
 }
 
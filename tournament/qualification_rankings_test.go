@@ -7,13 +7,14 @@ import (
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/stretchr/testify/assert"
-	"math/rand"
 	"testing"
 )
 
 func TestCalculateRankings(t *testing.T) {
-	rand.Seed(1)
 	database := setupTestDb(t)
+	eventSettings, _ := database.GetEventSettings()
+	eventSettings.TiebreakerSeed = 1
+	assert.Nil(t, database.UpdateEventSettings(eventSettings))
 
 	setupMatchResultsForRankings(database)
 	updatedRankings, err := CalculateRankings(database, false)
@@ -70,18 +71,57 @@ func TestCalculateRankings(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, updatedRankings, rankings)
 	if assert.Equal(t, 6, len(rankings)) {
-		assert.Equal(t, 4, rankings[0].TeamId)
-		assert.Equal(t, 3, rankings[0].PreviousRank)
-		assert.Equal(t, 2, rankings[1].TeamId)
-		assert.Equal(t, 1, rankings[1].PreviousRank)
+		assert.Equal(t, 2, rankings[0].TeamId)
+		assert.Equal(t, 1, rankings[0].PreviousRank)
+		assert.Equal(t, 4, rankings[1].TeamId)
+		assert.Equal(t, 3, rankings[1].PreviousRank)
 		assert.Equal(t, 3, rankings[2].TeamId)
 		assert.Equal(t, 2, rankings[2].PreviousRank)
 		assert.Equal(t, 6, rankings[3].TeamId)
 		assert.Equal(t, 5, rankings[3].PreviousRank)
-		assert.Equal(t, 5, rankings[4].TeamId)
-		assert.Equal(t, 6, rankings[4].PreviousRank)
-		assert.Equal(t, 1, rankings[5].TeamId)
-		assert.Equal(t, 4, rankings[5].PreviousRank)
+		assert.Equal(t, 1, rankings[4].TeamId)
+		assert.Equal(t, 4, rankings[4].PreviousRank)
+		assert.Equal(t, 5, rankings[5].TeamId)
+		assert.Equal(t, 6, rankings[5].PreviousRank)
+	}
+}
+
+func TestCalculateRankingsIsReproducible(t *testing.T) {
+	database := setupTestDb(t)
+	setupMatchResultsForRankings(database)
+
+	// Every team ties on all other criteria, leaving the order up to the random tiebreaker.
+	for _, match := range []model.Match{
+		{Type: "qualification", DisplayName: "5", Red1: 21, Red2: 22, Red3: 23, Blue1: 24, Blue2: 25, Blue3: 26,
+			Status: game.TieMatch},
+		{Type: "qualification", DisplayName: "6", Red1: 24, Red2: 25, Red3: 26, Blue1: 21, Blue2: 22, Blue3: 23,
+			Status: game.TieMatch},
+	} {
+		database.CreateMatch(&match)
+		matchResult := model.NewMatchResult()
+		matchResult.MatchId = match.Id
+		database.CreateMatchResult(matchResult)
+	}
+
+	rankings1, err := CalculateRankings(database, false)
+	assert.Nil(t, err)
+	rankings2, err := CalculateRankings(database, false)
+	assert.Nil(t, err)
+	if assert.Equal(t, len(rankings1), len(rankings2)) {
+		for i := range rankings1 {
+			assert.Equal(t, rankings1[i].TeamId, rankings2[i].TeamId)
+			assert.Equal(t, rankings1[i].Random, rankings2[i].Random)
+		}
+	}
+
+	// Changing the event's seed should produce a different, but equally stable, order.
+	eventSettings, _ := database.GetEventSettings()
+	eventSettings.TiebreakerSeed++
+	assert.Nil(t, database.UpdateEventSettings(eventSettings))
+	rankings3, err := CalculateRankings(database, false)
+	assert.Nil(t, err)
+	for i := range rankings3 {
+		assert.NotEqual(t, rankings1[i].Random, rankings3[i].Random)
 	}
 }
 
@@ -98,8 +138,8 @@ func setupMatchResultsForRankings(database *model.Database) {
 	database.CreateMatch(&match2)
 	matchResult2 := model.BuildTestMatchResult(match2.Id, 1)
 	matchResult2.BlueScore, matchResult2.RedScore = matchResult2.RedScore, matchResult2.BlueScore
-	matchResult2.RedScore.AutoPoints += 2
-	matchResult2.BlueScore.AutoPoints += 2
+	matchResult2.RedScore.SetCount("auto", matchResult2.RedScore.Count("auto")+2)
+	matchResult2.BlueScore.SetCount("auto", matchResult2.BlueScore.Count("auto")+2)
 	database.CreateMatchResult(matchResult2)
 
 	match3 := model.Match{Type: "qualification", DisplayName: "3", Red1: 6, Red2: 5, Red3: 4, Blue1: 3, Blue2: 2,
@@ -0,0 +1,154 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Functions for rebuilding the unplayed portion of a schedule after the team list changes mid-event.
+
+package tournament
+
+import (
+	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"math/rand"
+	"time"
+)
+
+// Rebuilds the unplayed matches of the given schedule for the given team list, leaving the completed matches as they
+// are. Match counts are rebalanced so that every team finishes with as close to the same number of counted matches as
+// the remaining time slots allow, with surrogate appearances filling out the last match. Returns the replacement
+// matches, which take over the times and names of the unplayed matches they replace; any unplayed time slots left over
+// are dropped.
+func BuildRemainingSchedule(teams []model.Team, matches []model.Match) ([]model.Match, error) {
+	if len(teams) < TeamsPerMatch {
+		return nil, fmt.Errorf("At least %d teams are required to generate a schedule", TeamsPerMatch)
+	}
+	var completedMatches, unplayedMatches []model.Match
+	for _, match := range matches {
+		if match.IsComplete() {
+			completedMatches = append(completedMatches, match)
+		} else {
+			unplayedMatches = append(unplayedMatches, match)
+		}
+	}
+	if len(unplayedMatches) == 0 {
+		return nil, fmt.Errorf("There are no unplayed matches to reschedule")
+	}
+
+	// Tally the matches each team has already played, and how recently.
+	teamIndices := make(map[int]int, len(teams))
+	for i, team := range teams {
+		teamIndices[team.Id] = i
+	}
+	playedCounts := make([]int, len(teams))
+	lastPlayed := make([]int, len(teams))
+	for i := range lastPlayed {
+		lastPlayed[i] = -1
+	}
+	for i, match := range completedMatches {
+		teamIds := []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3}
+		isSurrogate := []bool{
+			match.Red1IsSurrogate,
+			match.Red2IsSurrogate,
+			match.Red3IsSurrogate,
+			match.Blue1IsSurrogate,
+			match.Blue2IsSurrogate,
+			match.Blue3IsSurrogate,
+		}
+		for j, teamId := range teamIds {
+			if index, ok := teamIndices[teamId]; ok {
+				if !isSurrogate[j] {
+					playedCounts[index]++
+				}
+				lastPlayed[index] = i
+			}
+		}
+	}
+
+	// Keep teams out of the matches outside their availability windows or too soon after their last completed match.
+	minTurnaround := effectiveMinTurnaround(len(teams), defaultMinTurnaroundMatches)
+	matchTimes := make([]time.Time, len(unplayedMatches))
+	for j, match := range unplayedMatches {
+		matchTimes[j] = match.Time
+	}
+	unavailable := getAvailabilityWindowMatrix(teams, matchTimes)
+	if unavailable == nil {
+		unavailable = make([][]bool, len(teams))
+		for i := range unavailable {
+			unavailable[i] = make([]bool, len(unplayedMatches))
+		}
+	}
+	capacities := make([]int, len(teams))
+	for i := range teams {
+		if lastPlayed[i] >= 0 {
+			for j := 0; j < len(unplayedMatches) && len(completedMatches)-lastPlayed[i]+j < minTurnaround; j++ {
+				unavailable[i][j] = true
+			}
+		}
+		capacities[i] = countMaxAppearances(unavailable[i], minTurnaround)
+	}
+
+	// Find the highest match count that the remaining slots can bring every team up to.
+	matchCounts := make([]int, len(teams))
+	numSlots := len(unplayedMatches) * TeamsPerMatch
+	for target := 1; ; target++ {
+		counts, total := make([]int, len(teams)), 0
+		for i := range teams {
+			counts[i] = min(max(0, target-playedCounts[i]), capacities[i])
+			total += counts[i]
+		}
+		if total > numSlots {
+			break
+		}
+		matchCounts = counts
+		if target > len(unplayedMatches)+len(completedMatches) {
+			break
+		}
+	}
+	numAppearances := 0
+	for _, count := range matchCounts {
+		numAppearances += count
+	}
+	if numAppearances == 0 {
+		return nil, fmt.Errorf("The %d unplayed matches aren't enough for every team to play another match",
+			len(unplayedMatches))
+	}
+	numMatches := (numAppearances + TeamsPerMatch - 1) / TeamsPerMatch
+	surrogateSlots := numMatches*TeamsPerMatch - numAppearances
+	for i := range unavailable {
+		unavailable[i] = unavailable[i][:numMatches]
+	}
+
+	for attempt := 0; attempt < maxGenerationAttempts; attempt++ {
+		// Fill out the last match with surrogate appearances by randomly chosen teams that have room for one.
+		counts := append([]int(nil), matchCounts...)
+		surrogates := make([]bool, len(teams))
+		remainingSurrogateSlots := surrogateSlots
+		for _, i := range rand.Perm(len(teams)) {
+			if remainingSurrogateSlots > 0 && counts[i] < countMaxAppearances(unavailable[i], minTurnaround) {
+				counts[i]++
+				surrogates[i] = true
+				remainingSurrogateSlots--
+			}
+		}
+		if remainingSurrogateSlots > 0 {
+			break
+		}
+
+		anonSchedule := tryGenerateSchedule(counts, surrogates, minTurnaround, unavailable)
+		if anonSchedule == nil {
+			continue
+		}
+		teamIds := make([]int, len(teams))
+		for i, team := range teams {
+			teamIds[i] = team.Id
+		}
+		newMatches := make([]model.Match, numMatches)
+		for j, anonMatch := range anonSchedule {
+			newMatches[j].Type = unplayedMatches[j].Type
+			newMatches[j].DisplayName = unplayedMatches[j].DisplayName
+			newMatches[j].Time = unplayedMatches[j].Time
+			populateMatchTeams(&newMatches[j], anonMatch, teamIds)
+		}
+		return newMatches, nil
+	}
+	return nil, fmt.Errorf("Unable to reschedule the remaining %d matches for %d teams with at least %d matches "+
+		"between each team's matches", numMatches, len(teams), minTurnaround)
+}
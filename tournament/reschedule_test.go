@@ -0,0 +1,93 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package tournament
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestBuildRemainingSchedule(t *testing.T) {
+	rand.Seed(0)
+	teams := make([]model.Team, 18)
+	for i := range teams {
+		teams[i].Id = i + 101
+	}
+	scheduleBlocks := []model.ScheduleBlock{{StartTime: time.Unix(0, 0).UTC(), NumMatches: 30, MatchSpacingSec: 360}}
+	matches, err := BuildRandomSchedule(teams, scheduleBlocks, "qualification")
+	assert.Nil(t, err)
+	assert.Equal(t, 30, len(matches))
+	for i := 0; i < 9; i++ {
+		matches[i].Status = game.RedWonMatch
+	}
+
+	// One team withdraws and two new teams arrive.
+	withdrawnTeam := teams[4].Id
+	newTeams := append(append([]model.Team{}, teams[:4]...), teams[5:]...)
+	newTeams = append(newTeams, model.Team{Id: 201}, model.Team{Id: 202})
+	remainingMatches, err := BuildRemainingSchedule(newTeams, matches)
+	assert.Nil(t, err)
+
+	// The 126 remaining slots can bring the 17 original teams up from three matches to nine and get the new teams to
+	// nine as well, which needs 20 of the 21 unplayed matches. The new matches take over the slots of the unplayed
+	// ones.
+	if assert.Equal(t, 20, len(remainingMatches)) {
+		assert.Equal(t, "10", remainingMatches[0].DisplayName)
+		assert.Equal(t, matches[9].Time, remainingMatches[0].Time)
+		assert.Equal(t, "qualification", remainingMatches[0].Type)
+	}
+
+	counts := make(map[int]int)
+	surrogates := 0
+	for _, match := range append(append([]model.Match{}, matches[:9]...), remainingMatches...) {
+		teamIds := []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3}
+		isSurrogate := []bool{
+			match.Red1IsSurrogate,
+			match.Red2IsSurrogate,
+			match.Red3IsSurrogate,
+			match.Blue1IsSurrogate,
+			match.Blue2IsSurrogate,
+			match.Blue3IsSurrogate,
+		}
+		for j, teamId := range teamIds {
+			if isSurrogate[j] {
+				surrogates++
+			} else {
+				counts[teamId]++
+			}
+		}
+	}
+	for _, team := range teams {
+		if team.Id != withdrawnTeam {
+			assert.Equal(t, 9, counts[team.Id], "team %d", team.Id)
+		}
+	}
+	assert.Equal(t, 3, counts[withdrawnTeam])
+	assert.Equal(t, 9, counts[201])
+	assert.Equal(t, 9, counts[202])
+	assert.Equal(t, 0, surrogates)
+
+	// Nobody should have to play the first rescheduled match right after finishing the last completed one.
+	lastCompleted := matches[8]
+	for _, teamId := range []int{lastCompleted.Red1, lastCompleted.Red2, lastCompleted.Red3, lastCompleted.Blue1,
+		lastCompleted.Blue2, lastCompleted.Blue3} {
+		first := remainingMatches[0]
+		assert.NotContains(t, []int{first.Red1, first.Red2, first.Red3, first.Blue1, first.Blue2, first.Blue3}, teamId)
+	}
+}
+
+func TestBuildRemainingScheduleErrors(t *testing.T) {
+	teams := make([]model.Team, 6)
+	for i := range teams {
+		teams[i].Id = i + 101
+	}
+	matches := []model.Match{{Status: game.RedWonMatch}}
+	_, err := BuildRemainingSchedule(teams[:5], matches)
+	assert.EqualError(t, err, "At least 6 teams are required to generate a schedule")
+	_, err = BuildRemainingSchedule(teams, matches)
+	assert.EqualError(t, err, "There are no unplayed matches to reschedule")
+}
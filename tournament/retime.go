@@ -0,0 +1,75 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Functions for shifting the scheduled times of unplayed matches when the event runs early or late.
+
+package tournament
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"time"
+)
+
+// Shifts the scheduled times of the unplayed matches from the given index onward so that the match at that index is
+// scheduled for the given time and the rest of its schedule block follows at the given cycle time, or at the block's
+// own spacing if the cycle time is zero. Later blocks keep their own start times and spacing so that breaks between
+// blocks are preserved, unless the shifted block runs into them, in which case they are pushed back just far enough to
+// follow it. Matches that aren't covered by any schedule block keep their original spacing. Returns the matches whose
+// times changed.
+func RetimeMatches(
+	matches []model.Match, scheduleBlocks []model.ScheduleBlock, index int, startTime time.Time, cycleTimeSec int,
+) []model.Match {
+	if index < 0 || index >= len(matches) {
+		return nil
+	}
+	blockIndices := getMatchBlockIndices(scheduleBlocks, len(matches))
+	originalTimes := make([]time.Time, len(matches))
+	for i, match := range matches {
+		originalTimes[i] = match.Time
+	}
+
+	var retimedMatches []model.Match
+	nextTime := startTime
+	for i := index; i < len(matches); i++ {
+		blockIndex := blockIndices[i]
+		if i > index && blockIndex >= 0 && blockIndex != blockIndices[i-1] &&
+			scheduleBlocks[blockIndex].StartTime.After(nextTime) {
+			// Don't start the next block before its scheduled time.
+			nextTime = scheduleBlocks[blockIndex].StartTime
+		}
+		if matches[i].IsComplete() {
+			// Leave any matches that were played out of order where they are.
+			continue
+		}
+		if !matches[i].Time.Equal(nextTime) {
+			matches[i].Time = nextTime
+			retimedMatches = append(retimedMatches, matches[i])
+		}
+
+		spacing := time.Duration(cycleTimeSec) * time.Second
+		if blockIndex >= 0 && (cycleTimeSec == 0 || blockIndex != blockIndices[index]) {
+			spacing = time.Duration(scheduleBlocks[blockIndex].MatchSpacingSec) * time.Second
+		} else if blockIndex < 0 && cycleTimeSec == 0 && i+1 < len(matches) {
+			spacing = originalTimes[i+1].Sub(originalTimes[i])
+		}
+		nextTime = nextTime.Add(spacing)
+	}
+	return retimedMatches
+}
+
+// Returns the index of the schedule block that each match belongs to, assuming that the matches were laid out over the
+// blocks in order. Matches beyond the end of the last block are assigned to it, and all matches are assigned -1 if
+// there are no blocks.
+func getMatchBlockIndices(scheduleBlocks []model.ScheduleBlock, numMatches int) []int {
+	blockIndices := make([]int, numMatches)
+	matchIndex := 0
+	for i, block := range scheduleBlocks {
+		for j := 0; j < block.NumMatches && matchIndex < numMatches; j++ {
+			blockIndices[matchIndex] = i
+			matchIndex++
+		}
+	}
+	for ; matchIndex < numMatches; matchIndex++ {
+		blockIndices[matchIndex] = len(scheduleBlocks) - 1
+	}
+	return blockIndices
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package tournament
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRetimeMatches(t *testing.T) {
+	blockStart := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	scheduleBlocks := []model.ScheduleBlock{
+		{StartTime: blockStart, NumMatches: 10, MatchSpacingSec: 360},
+		{StartTime: blockStart.Add(4 * time.Hour), NumMatches: 10, MatchSpacingSec: 360},
+	}
+	newMatches := func() []model.Match {
+		matchTimes := getMatchTimes(scheduleBlocks, 20)
+		matches := make([]model.Match, 20)
+		for i := range matches {
+			matches[i] = model.Match{Id: i + 1, Time: matchTimes[i]}
+			if i < 3 {
+				matches[i].Status = game.RedWonMatch
+			}
+		}
+		return matches
+	}
+
+	// Running late shifts the rest of the block but leaves the next one alone.
+	matches := newMatches()
+	retimedMatches := RetimeMatches(matches, scheduleBlocks, 3, blockStart.Add(33*time.Minute), 0)
+	if assert.Equal(t, 7, len(retimedMatches)) {
+		assert.Equal(t, 4, retimedMatches[0].Id)
+		assert.Equal(t, 10, retimedMatches[6].Id)
+	}
+	assert.Equal(t, blockStart, matches[0].Time)
+	assert.Equal(t, blockStart.Add(33*time.Minute), matches[3].Time)
+	assert.Equal(t, blockStart.Add(69*time.Minute), matches[9].Time)
+	assert.Equal(t, blockStart.Add(4*time.Hour), matches[10].Time)
+
+	// A configured cycle time applies to the rest of the current block.
+	matches = newMatches()
+	retimedMatches = RetimeMatches(matches, scheduleBlocks, 3, blockStart.Add(33*time.Minute), 300)
+	assert.Equal(t, 7, len(retimedMatches))
+	assert.Equal(t, blockStart.Add(63*time.Minute), matches[9].Time)
+	assert.Equal(t, blockStart.Add(4*time.Hour), matches[10].Time)
+
+	// Running early shifts the rest of the block earlier.
+	matches = newMatches()
+	RetimeMatches(matches, scheduleBlocks, 3, blockStart.Add(14*time.Minute), 0)
+	assert.Equal(t, blockStart.Add(50*time.Minute), matches[9].Time)
+	assert.Equal(t, blockStart.Add(4*time.Hour), matches[10].Time)
+
+	// Running far enough behind to eat the break pushes the next block back too.
+	matches = newMatches()
+	retimedMatches = RetimeMatches(matches, scheduleBlocks, 3, blockStart.Add(230*time.Minute), 0)
+	assert.Equal(t, 17, len(retimedMatches))
+	assert.Equal(t, blockStart.Add(266*time.Minute), matches[9].Time)
+	assert.Equal(t, blockStart.Add(272*time.Minute), matches[10].Time)
+	assert.Equal(t, blockStart.Add(326*time.Minute), matches[19].Time)
+
+	// Catching back up lets the next block return to its scheduled start.
+	retimedMatches = RetimeMatches(matches, scheduleBlocks, 9, blockStart.Add(54*time.Minute), 0)
+	assert.Equal(t, 11, len(retimedMatches))
+	assert.Equal(t, blockStart.Add(4*time.Hour), matches[10].Time)
+	assert.Equal(t, blockStart.Add(294*time.Minute), matches[19].Time)
+
+	// Matches that were played out of order are left alone.
+	matches = newMatches()
+	matches[5].Status = game.BlueWonMatch
+	RetimeMatches(matches, scheduleBlocks, 3, blockStart.Add(33*time.Minute), 0)
+	assert.Equal(t, blockStart.Add(30*time.Minute), matches[5].Time)
+	assert.Equal(t, blockStart.Add(45*time.Minute), matches[6].Time)
+
+	// Nothing changes when the event is on schedule.
+	matches = newMatches()
+	assert.Empty(t, RetimeMatches(matches, scheduleBlocks, 3, blockStart.Add(18*time.Minute), 0))
+}
+
+func TestRetimeMatchesWithoutScheduleBlocks(t *testing.T) {
+	start := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	matches := []model.Match{
+		{Id: 1, Time: start},
+		{Id: 2, Time: start.Add(5 * time.Minute)},
+		{Id: 3, Time: start.Add(12 * time.Minute)},
+	}
+	retimedMatches := RetimeMatches(matches, nil, 1, start.Add(10*time.Minute), 0)
+	assert.Equal(t, 2, len(retimedMatches))
+	assert.Equal(t, start, matches[0].Time)
+	assert.Equal(t, start.Add(10*time.Minute), matches[1].Time)
+	assert.Equal(t, start.Add(17*time.Minute), matches[2].Time)
+
+	RetimeMatches(matches, nil, 1, start.Add(10*time.Minute), 360)
+	assert.Equal(t, start.Add(16*time.Minute), matches[2].Time)
+
+	assert.Empty(t, RetimeMatches(matches, nil, 3, start, 0))
+}
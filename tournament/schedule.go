@@ -25,7 +25,6 @@ const (
 // Creates a random schedule for the given parameters and returns it as a list of matches.
 func BuildRandomSchedule(teams []model.Team, scheduleBlocks []model.ScheduleBlock,
 	matchType string) ([]model.Match, error) {
-	// Load the anonymized, pre-randomized match schedule for the given number of teams and matches per team.
 	numTeams := len(teams)
 	numMatches := countMatches(scheduleBlocks)
 	matchesPerTeam := int(float32(numMatches*TeamsPerMatch) / float32(numTeams))
@@ -33,10 +32,153 @@ func BuildRandomSchedule(teams []model.Team, scheduleBlocks []model.ScheduleBloc
 	// Adjust the number of matches to remove any excess from non-perfect block scheduling.
 	numMatches = int(math.Ceil(float64(numTeams) * float64(matchesPerTeam) / TeamsPerMatch))
 
+	matchTimes := getMatchTimes(scheduleBlocks, numMatches)
+
+	// Teams with limited availability need a schedule generated around them; otherwise use the anonymized,
+	// pre-randomized match schedule for the given number of teams and matches per team if one exists, since it is
+	// instant, and only generate one from scratch if it doesn't.
+	unavailable, err := getTeamUnavailability(teams, matchTimes, matchesPerTeam)
+	if err != nil {
+		return nil, err
+	}
+	var anonSchedule [][12]int
+	if unavailable == nil {
+		anonSchedule, err = loadScheduleTemplate(numTeams, matchesPerTeam, numMatches)
+	}
+	if unavailable != nil || os.IsNotExist(err) {
+		anonSchedule, err = generateAnonymousSchedule(
+			numTeams, matchesPerTeam, numMatches, defaultMinTurnaroundMatches, unavailable,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate a random permutation of the team ordering to fill into the pre-randomized schedule. A schedule generated
+	// around team availability already has each team in its place.
+	teamShuffle := rand.Perm(numTeams)
+	if unavailable != nil {
+		for i := range teamShuffle {
+			teamShuffle[i] = i
+		}
+	}
+	teamIds := make([]int, numTeams)
+	for i, shuffledIndex := range teamShuffle {
+		teamIds[i] = teams[shuffledIndex].Id
+	}
+	matches := make([]model.Match, numMatches)
+	for i, anonMatch := range anonSchedule {
+		matches[i].Type = matchType
+		matches[i].DisplayName = strconv.Itoa(i + 1)
+		populateMatchTeams(&matches[i], anonMatch, teamIds)
+		matches[i].Time = matchTimes[i]
+	}
+
+	return matches, nil
+}
+
+// Fills in the teams and surrogate flags of the given match from a row of an anonymized schedule, using the given list
+// of team IDs to resolve the one-based team numbers.
+func populateMatchTeams(match *model.Match, anonMatch [12]int, teamIds []int) {
+	match.Red1 = teamIds[anonMatch[0]-1]
+	match.Red1IsSurrogate = anonMatch[1] == 1
+	match.Red2 = teamIds[anonMatch[2]-1]
+	match.Red2IsSurrogate = anonMatch[3] == 1
+	match.Red3 = teamIds[anonMatch[4]-1]
+	match.Red3IsSurrogate = anonMatch[5] == 1
+	match.Blue1 = teamIds[anonMatch[6]-1]
+	match.Blue1IsSurrogate = anonMatch[7] == 1
+	match.Blue2 = teamIds[anonMatch[8]-1]
+	match.Blue2IsSurrogate = anonMatch[9] == 1
+	match.Blue3 = teamIds[anonMatch[10]-1]
+	match.Blue3IsSurrogate = anonMatch[11] == 1
+}
+
+// Returns the start times of the given number of matches when run within the given schedule blocks.
+func getMatchTimes(scheduleBlocks []model.ScheduleBlock, numMatches int) []time.Time {
+	matchTimes := make([]time.Time, numMatches)
+	matchIndex := 0
+	for _, block := range scheduleBlocks {
+		for i := 0; i < block.NumMatches && matchIndex < numMatches; i++ {
+			matchTimes[matchIndex] = block.StartTime.Add(time.Duration(i*block.MatchSpacingSec) * time.Second)
+			matchIndex++
+		}
+	}
+	return matchTimes
+}
+
+// Returns a matrix marking which matches each team (by index) cannot play in due to its availability window, or nil if
+// all teams are available for the whole schedule. Returns an error if the windows make a schedule impossible.
+func getTeamUnavailability(teams []model.Team, matchTimes []time.Time, matchesPerTeam int) ([][]bool, error) {
+	unavailable := getAvailabilityWindowMatrix(teams, matchTimes)
+	if unavailable == nil {
+		return nil, nil
+	}
+
+	minTurnaround := effectiveMinTurnaround(len(teams), defaultMinTurnaroundMatches)
+	for i, team := range teams {
+		if maxMatches := countMaxAppearances(unavailable[i], minTurnaround); maxMatches < matchesPerTeam {
+			return nil, fmt.Errorf("Team %d is only available long enough to play %d of its %d matches", team.Id,
+				maxMatches, matchesPerTeam)
+		}
+	}
+	for j := range matchTimes {
+		count := 0
+		for i := range teams {
+			if !unavailable[i][j] {
+				count++
+			}
+		}
+		if count < TeamsPerMatch {
+			return nil, fmt.Errorf("Only %d teams are available to play in match %d", count, j+1)
+		}
+	}
+	return unavailable, nil
+}
+
+// Returns a matrix marking which of the given matches each team (by index) is outside of its availability window for,
+// or nil if no team has an availability window.
+func getAvailabilityWindowMatrix(teams []model.Team, matchTimes []time.Time) [][]bool {
+	constrained := false
+	for _, team := range teams {
+		if team.HasAvailabilityWindow() {
+			constrained = true
+		}
+	}
+	if !constrained {
+		return nil
+	}
+
+	unavailable := make([][]bool, len(teams))
+	for i, team := range teams {
+		unavailable[i] = make([]bool, len(matchTimes))
+		for j, matchTime := range matchTimes {
+			unavailable[i][j] = !team.IsAvailableAt(matchTime)
+		}
+	}
+	return unavailable
+}
+
+// Returns the most matches a team could play given the matches it is unavailable for and the minimum turnaround.
+func countMaxAppearances(unavailable []bool, minTurnaround int) int {
+	count := 0
+	lastMatch := -minTurnaround
+	for j, isUnavailable := range unavailable {
+		if !isUnavailable && j-lastMatch >= minTurnaround {
+			count++
+			lastMatch = j
+		}
+	}
+	return count
+}
+
+// Loads the pre-randomized schedule template for the given parameters from the schedules directory. Returns an error
+// satisfying os.IsNotExist if no such template exists.
+func loadScheduleTemplate(numTeams, matchesPerTeam, numMatches int) ([][12]int, error) {
 	file, err := os.Open(fmt.Sprintf("%s/%d_%d.csv", filepath.Join(model.BaseDir, schedulesDir), numTeams,
 		matchesPerTeam))
 	if err != nil {
-		return nil, fmt.Errorf("No schedule template exists for %d teams and %d matches", numTeams, matchesPerTeam)
+		return nil, err
 	}
 	defer file.Close()
 	reader := csv.NewReader(file)
@@ -58,37 +200,7 @@ func BuildRandomSchedule(teams []model.Team, scheduleBlocks []model.ScheduleBloc
 			}
 		}
 	}
-
-	// Generate a random permutation of the team ordering to fill into the pre-randomized schedule.
-	teamShuffle := rand.Perm(numTeams)
-	matches := make([]model.Match, numMatches)
-	for i, anonMatch := range anonSchedule {
-		matches[i].Type = matchType
-		matches[i].DisplayName = strconv.Itoa(i + 1)
-		matches[i].Red1 = teams[teamShuffle[anonMatch[0]-1]].Id
-		matches[i].Red1IsSurrogate = anonMatch[1] == 1
-		matches[i].Red2 = teams[teamShuffle[anonMatch[2]-1]].Id
-		matches[i].Red2IsSurrogate = anonMatch[3] == 1
-		matches[i].Red3 = teams[teamShuffle[anonMatch[4]-1]].Id
-		matches[i].Red3IsSurrogate = anonMatch[5] == 1
-		matches[i].Blue1 = teams[teamShuffle[anonMatch[6]-1]].Id
-		matches[i].Blue1IsSurrogate = anonMatch[7] == 1
-		matches[i].Blue2 = teams[teamShuffle[anonMatch[8]-1]].Id
-		matches[i].Blue2IsSurrogate = anonMatch[9] == 1
-		matches[i].Blue3 = teams[teamShuffle[anonMatch[10]-1]].Id
-		matches[i].Blue3IsSurrogate = anonMatch[11] == 1
-	}
-
-	// Fill in the match times.
-	matchIndex := 0
-	for _, block := range scheduleBlocks {
-		for i := 0; i < block.NumMatches && matchIndex < numMatches; i++ {
-			matches[matchIndex].Time = block.StartTime.Add(time.Duration(i*block.MatchSpacingSec) * time.Second)
-			matchIndex++
-		}
-	}
-
-	return matches, nil
+	return anonSchedule, nil
 }
 
 // Returns the total number of matches that can be run within the given schedule blocks.
@@ -0,0 +1,172 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Functions for measuring the quality of a match schedule so that candidate schedules can be compared.
+
+package tournament
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"sort"
+)
+
+// Statistics about a single team's matches within a schedule. Turnarounds are measured in matches, such that a team
+// playing back-to-back matches has a turnaround of one.
+type TeamScheduleStats struct {
+	TeamId            int
+	NumMatches        int
+	NumRed            int
+	NumBlue           int
+	NumSurrogate      int
+	MinTurnaround     int
+	AverageTurnaround float64
+	BackToBackMatches int
+}
+
+// Summary of the properties of a schedule that make it more or less fair to the teams playing in it.
+type ScheduleEvaluation struct {
+	NumMatches           int
+	NumTeams             int
+	MinTurnaround        int
+	AverageTurnaround    float64
+	BackToBackMatches    int
+	PartnerDuplicates    int
+	MaxPartnerRepeats    int
+	OpponentDuplicates   int
+	MaxOpponentRepeats   int
+	MaxColorImbalance    int
+	ImbalancedTeams      int
+	NumSurrogates        int
+	SurrogateTeams       int
+	MaxSurrogatesPerTeam int
+	Teams                []TeamScheduleStats
+}
+
+// Computes the quality metrics of the given schedule, whose matches are expected to be in the order they are played.
+func EvaluateSchedule(matches []model.Match) *ScheduleEvaluation {
+	evaluation := ScheduleEvaluation{NumMatches: len(matches)}
+	appearances := make(map[int][]int)
+	teamStats := make(map[int]*TeamScheduleStats)
+	partnerCounts := make(map[[2]int]int)
+	opponentCounts := make(map[[2]int]int)
+
+	for i, match := range matches {
+		red := []int{match.Red1, match.Red2, match.Red3}
+		blue := []int{match.Blue1, match.Blue2, match.Blue3}
+		redSurrogates := []bool{match.Red1IsSurrogate, match.Red2IsSurrogate, match.Red3IsSurrogate}
+		blueSurrogates := []bool{match.Blue1IsSurrogate, match.Blue2IsSurrogate, match.Blue3IsSurrogate}
+		for j, team := range append(red, blue...) {
+			if team == 0 {
+				continue
+			}
+			stats, ok := teamStats[team]
+			if !ok {
+				stats = &TeamScheduleStats{TeamId: team}
+				teamStats[team] = stats
+			}
+			stats.NumMatches++
+			appearances[team] = append(appearances[team], i)
+			if j < len(red) {
+				stats.NumRed++
+				if redSurrogates[j] {
+					stats.NumSurrogate++
+				}
+			} else {
+				stats.NumBlue++
+				if blueSurrogates[j-len(red)] {
+					stats.NumSurrogate++
+				}
+			}
+		}
+		countPartners(partnerCounts, red)
+		countPartners(partnerCounts, blue)
+		countOpponents(opponentCounts, red, blue)
+	}
+
+	evaluation.PartnerDuplicates, evaluation.MaxPartnerRepeats = summarizePairs(partnerCounts)
+	evaluation.OpponentDuplicates, evaluation.MaxOpponentRepeats = summarizePairs(opponentCounts)
+
+	totalTurnaround, numTurnarounds := 0, 0
+	for team, stats := range teamStats {
+		teamMatches := appearances[team]
+		for j := 1; j < len(teamMatches); j++ {
+			turnaround := teamMatches[j] - teamMatches[j-1]
+			if stats.MinTurnaround == 0 || turnaround < stats.MinTurnaround {
+				stats.MinTurnaround = turnaround
+			}
+			if turnaround == 1 {
+				stats.BackToBackMatches++
+			}
+			stats.AverageTurnaround += float64(turnaround)
+			totalTurnaround += turnaround
+			numTurnarounds++
+		}
+		if len(teamMatches) > 1 {
+			stats.AverageTurnaround /= float64(len(teamMatches) - 1)
+		}
+
+		if stats.MinTurnaround > 0 {
+			if evaluation.MinTurnaround == 0 || stats.MinTurnaround < evaluation.MinTurnaround {
+				evaluation.MinTurnaround = stats.MinTurnaround
+			}
+		}
+		evaluation.BackToBackMatches += stats.BackToBackMatches
+		imbalance := stats.NumRed - stats.NumBlue
+		if imbalance < 0 {
+			imbalance = -imbalance
+		}
+		evaluation.MaxColorImbalance = max(evaluation.MaxColorImbalance, imbalance)
+		if imbalance > 1 {
+			evaluation.ImbalancedTeams++
+		}
+		evaluation.NumSurrogates += stats.NumSurrogate
+		if stats.NumSurrogate > 0 {
+			evaluation.SurrogateTeams++
+		}
+		evaluation.MaxSurrogatesPerTeam = max(evaluation.MaxSurrogatesPerTeam, stats.NumSurrogate)
+		evaluation.Teams = append(evaluation.Teams, *stats)
+	}
+	if numTurnarounds > 0 {
+		evaluation.AverageTurnaround = float64(totalTurnaround) / float64(numTurnarounds)
+	}
+	evaluation.NumTeams = len(evaluation.Teams)
+	sort.Slice(evaluation.Teams, func(i, j int) bool {
+		return evaluation.Teams[i].TeamId < evaluation.Teams[j].TeamId
+	})
+
+	return &evaluation
+}
+
+// Increments the count for each pair of teams on the given alliance.
+func countPartners(counts map[[2]int]int, alliance []int) {
+	for i := 0; i < len(alliance); i++ {
+		for j := i + 1; j < len(alliance); j++ {
+			countPair(counts, alliance[i], alliance[j])
+		}
+	}
+}
+
+// Increments the count for each pairing of a team on one alliance against a team on the other.
+func countOpponents(counts map[[2]int]int, red, blue []int) {
+	for _, redTeam := range red {
+		for _, blueTeam := range blue {
+			countPair(counts, redTeam, blueTeam)
+		}
+	}
+}
+
+func countPair(counts map[[2]int]int, teamA, teamB int) {
+	if teamA != 0 && teamB != 0 && teamA != teamB {
+		counts[[2]int{min(teamA, teamB), max(teamA, teamB)}]++
+	}
+}
+
+// Returns the number of times any pair of teams was matched up beyond the first, and the most times any single pair
+// was matched up.
+func summarizePairs(counts map[[2]int]int) (int, int) {
+	duplicates, maxCount := 0, 0
+	for _, count := range counts {
+		duplicates += count - 1
+		maxCount = max(maxCount, count)
+	}
+	return duplicates, maxCount
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package tournament
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestEvaluateSchedule(t *testing.T) {
+	matches := []model.Match{
+		{Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5, Blue3: 6},
+		{Red1: 1, Red2: 2, Red3: 7, Blue1: 3, Blue2: 4, Blue3: 8},
+		{Red1: 5, Red2: 6, Red3: 8, Blue1: 1, Blue2: 7, Blue3: 2, Blue3IsSurrogate: true},
+	}
+	evaluation := EvaluateSchedule(matches)
+	assert.Equal(t, 3, evaluation.NumMatches)
+	assert.Equal(t, 8, evaluation.NumTeams)
+	assert.Equal(t, 1, evaluation.MinTurnaround)
+	assert.Equal(t, 1.2, evaluation.AverageTurnaround)
+	assert.Equal(t, 8, evaluation.BackToBackMatches)
+	assert.Equal(t, 5, evaluation.PartnerDuplicates)
+	assert.Equal(t, 3, evaluation.MaxPartnerRepeats)
+	assert.Equal(t, 9, evaluation.OpponentDuplicates)
+	assert.Equal(t, 2, evaluation.MaxOpponentRepeats)
+	assert.Equal(t, 2, evaluation.MaxColorImbalance)
+	assert.Equal(t, 1, evaluation.ImbalancedTeams)
+	assert.Equal(t, 1, evaluation.NumSurrogates)
+	assert.Equal(t, 1, evaluation.SurrogateTeams)
+	assert.Equal(t, 1, evaluation.MaxSurrogatesPerTeam)
+
+	if assert.Equal(t, 8, len(evaluation.Teams)) {
+		assert.Equal(
+			t,
+			TeamScheduleStats{
+				TeamId:            2,
+				NumMatches:        3,
+				NumRed:            2,
+				NumBlue:           1,
+				NumSurrogate:      1,
+				MinTurnaround:     1,
+				AverageTurnaround: 1,
+				BackToBackMatches: 2,
+			},
+			evaluation.Teams[1],
+		)
+		assert.Equal(
+			t,
+			TeamScheduleStats{
+				TeamId:            5,
+				NumMatches:        2,
+				NumRed:            1,
+				NumBlue:           1,
+				MinTurnaround:     2,
+				AverageTurnaround: 2,
+			},
+			evaluation.Teams[4],
+		)
+	}
+}
+
+func TestEvaluateGeneratedSchedule(t *testing.T) {
+	rand.Seed(0)
+	teams := make([]model.Team, 40)
+	for i := range teams {
+		teams[i].Id = i + 101
+	}
+	anonSchedule, err := generateAnonymousSchedule(40, 10, 67, defaultMinTurnaroundMatches, nil)
+	assert.Nil(t, err)
+	matches := make([]model.Match, len(anonSchedule))
+	for i, anonMatch := range anonSchedule {
+		matches[i] = model.Match{
+			Red1:             teams[anonMatch[0]-1].Id,
+			Red2:             teams[anonMatch[2]-1].Id,
+			Red3:             teams[anonMatch[4]-1].Id,
+			Blue1:            teams[anonMatch[6]-1].Id,
+			Blue2:            teams[anonMatch[8]-1].Id,
+			Blue3:            teams[anonMatch[10]-1].Id,
+			Red1IsSurrogate:  anonMatch[1] == 1,
+			Red2IsSurrogate:  anonMatch[3] == 1,
+			Red3IsSurrogate:  anonMatch[5] == 1,
+			Blue1IsSurrogate: anonMatch[7] == 1,
+			Blue2IsSurrogate: anonMatch[9] == 1,
+			Blue3IsSurrogate: anonMatch[11] == 1,
+		}
+	}
+
+	evaluation := EvaluateSchedule(matches)
+	assert.Equal(t, 40, evaluation.NumTeams)
+	assert.GreaterOrEqual(t, evaluation.MinTurnaround, defaultMinTurnaroundMatches)
+	assert.Equal(t, 0, evaluation.BackToBackMatches)
+	assert.Equal(t, 0, evaluation.PartnerDuplicates)
+	assert.Equal(t, 0, evaluation.ImbalancedTeams)
+	assert.Equal(t, 2, evaluation.NumSurrogates)
+	assert.Equal(t, 2, evaluation.SurrogateTeams)
+}
@@ -0,0 +1,372 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Native match schedule generator that uses simulated annealing to build a balanced schedule for any number of teams.
+
+package tournament
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// Minimum number of matches between the starts of a team's consecutive matches, if the team count allows it.
+	defaultMinTurnaroundMatches = 3
+
+	// Relative weights of the properties of a schedule that the generator tries to minimize.
+	partnerRepeatWeight  = 4
+	opponentRepeatWeight = 1
+	turnaroundWeight     = 100
+	colorImbalanceWeight = 5
+	availabilityWeight   = 1000
+
+	annealingIterationsPerSlot = 400
+	annealingStartTemperature  = 5.0
+	annealingEndTemperature    = 0.05
+	maxGenerationAttempts      = 5
+)
+
+// The state of a schedule being generated, where each match occupies TeamsPerMatch consecutive slots (red then blue)
+// holding zero-based team indices.
+type scheduleGenerator struct {
+	numTeams      int
+	minTurnaround int
+	unavailable   [][]bool
+	slots         []int
+	appearances   [][]int
+	partnerCounts [][]int
+	opponentCount [][]int
+	cost          float64
+}
+
+// Generates an anonymized schedule in the same format as the files in the schedules directory, for when no pre-baked
+// schedule exists for the given parameters. If given, unavailable[team][match] marks the matches that each team (by
+// zero-based index) cannot be scheduled into.
+func generateAnonymousSchedule(
+	numTeams, matchesPerTeam, numMatches, minTurnaround int, unavailable [][]bool,
+) ([][12]int, error) {
+	if numTeams < TeamsPerMatch {
+		return nil, fmt.Errorf("At least %d teams are required to generate a schedule", TeamsPerMatch)
+	}
+	surrogateSlots := numMatches*TeamsPerMatch - numTeams*matchesPerTeam
+	if surrogateSlots < 0 || surrogateSlots >= TeamsPerMatch {
+		return nil, fmt.Errorf("Cannot fit %d teams playing %d matches into %d matches", numTeams, matchesPerTeam,
+			numMatches)
+	}
+
+	minTurnaround = effectiveMinTurnaround(numTeams, minTurnaround)
+
+	for attempt := 0; attempt < maxGenerationAttempts; attempt++ {
+		// Randomly choose the teams that play an extra match as a surrogate to fill out the last match.
+		matchCounts := make([]int, numTeams)
+		surrogates := make([]bool, numTeams)
+		for team := range matchCounts {
+			matchCounts[team] = matchesPerTeam
+		}
+		for _, team := range rand.Perm(numTeams)[:surrogateSlots] {
+			matchCounts[team]++
+			surrogates[team] = true
+		}
+		if schedule := tryGenerateSchedule(matchCounts, surrogates, minTurnaround, unavailable); schedule != nil {
+			return schedule, nil
+		}
+	}
+	if unavailable != nil {
+		return nil, fmt.Errorf("Unable to generate a schedule for %d teams and %d matches that satisfies the team "+
+			"availability constraints with at least %d matches between each team's matches", numTeams,
+			matchesPerTeam, minTurnaround)
+	}
+	return nil, fmt.Errorf("Unable to generate a schedule for %d teams and %d matches with at least %d matches "+
+		"between each team's matches", numTeams, matchesPerTeam, minTurnaround)
+}
+
+// Makes a single attempt at generating a schedule in which each team (by index) plays the given number of matches,
+// one of which is as a surrogate for the flagged teams. The total number of appearances must fill a whole number of
+// matches. Returns nil if the attempt failed to satisfy the turnaround and availability constraints.
+func tryGenerateSchedule(matchCounts []int, surrogates []bool, minTurnaround int, unavailable [][]bool) [][12]int {
+	generator := newScheduleGenerator(matchCounts, minTurnaround, unavailable)
+	if generator == nil {
+		return nil
+	}
+	generator.anneal()
+	if !generator.isValid() {
+		return nil
+	}
+	return generator.toAnonymousSchedule(surrogates)
+}
+
+// Returns the minimum turnaround to enforce for the given team count, so as not to demand more rest than the number of
+// teams makes possible.
+func effectiveMinTurnaround(numTeams, minTurnaround int) int {
+	return max(1, min(minTurnaround, numTeams/TeamsPerMatch-1))
+}
+
+// Builds an initial schedule by spreading each team's appearances evenly but randomly across the schedule, which for
+// teams playing the same number of matches amounts to every team playing once in each round. Returns nil if the
+// appearances couldn't be arranged without a team appearing twice in the same match.
+func newScheduleGenerator(matchCounts []int, minTurnaround int, unavailable [][]bool) *scheduleGenerator {
+	type appearance struct {
+		team     int
+		position float64
+	}
+	var appearances []appearance
+	for team, count := range matchCounts {
+		for i := 0; i < count; i++ {
+			appearances = append(appearances, appearance{team, (float64(i) + rand.Float64()) / float64(count)})
+		}
+	}
+	sort.Slice(appearances, func(i, j int) bool {
+		return appearances[i].position < appearances[j].position
+	})
+	order := make([]int, len(appearances))
+	for i, appearance := range appearances {
+		order[i] = appearance.team
+	}
+
+	// Pull later teams forward wherever a team would otherwise land in the same match twice.
+	for i := range order {
+		matchStart := i - i%TeamsPerMatch
+		j := i
+		for j < len(order) && containsTeam(order[matchStart:i], order[j]) {
+			j++
+		}
+		if j == len(order) {
+			return nil
+		}
+		order[i], order[j] = order[j], order[i]
+	}
+
+	return newScheduleGeneratorFromSlots(len(matchCounts), minTurnaround, unavailable, order)
+}
+
+// Improves the schedule by repeatedly swapping random pairs of slots, accepting swaps that make it worse with a
+// probability that decreases over time so as to escape local minima.
+func (generator *scheduleGenerator) anneal() {
+	numSlots := len(generator.slots)
+	iterations := annealingIterationsPerSlot * numSlots
+	cooling := math.Pow(annealingEndTemperature/annealingStartTemperature, 1/float64(iterations))
+	temperature := annealingStartTemperature
+	bestCost := generator.cost
+	bestSlots := append([]int(nil), generator.slots...)
+
+	for i := 0; i < iterations; i++ {
+		temperature *= cooling
+		slotA, slotB := rand.Intn(numSlots), rand.Intn(numSlots)
+		if !generator.canSwap(slotA, slotB) {
+			continue
+		}
+		delta := generator.swap(slotA, slotB)
+		if delta > 0 && rand.Float64() >= math.Exp(-delta/temperature) {
+			// Reject the swap by reversing it.
+			generator.swap(slotA, slotB)
+			continue
+		}
+		if generator.cost < bestCost {
+			bestCost = generator.cost
+			copy(bestSlots, generator.slots)
+		}
+	}
+
+	if bestCost < generator.cost {
+		*generator = *newScheduleGeneratorFromSlots(
+			generator.numTeams, generator.minTurnaround, generator.unavailable, bestSlots,
+		)
+	}
+}
+
+// Builds the generator state, including the pairing counts and cost, for the given arrangement of slots.
+func newScheduleGeneratorFromSlots(numTeams, minTurnaround int, unavailable [][]bool, slots []int) *scheduleGenerator {
+	numMatches := len(slots) / TeamsPerMatch
+	generator := scheduleGenerator{
+		numTeams:      numTeams,
+		minTurnaround: minTurnaround,
+		unavailable:   unavailable,
+		slots:         slots,
+		appearances:   make([][]int, numTeams),
+		partnerCounts: make([][]int, numTeams),
+		opponentCount: make([][]int, numTeams),
+	}
+	for team := 0; team < numTeams; team++ {
+		generator.partnerCounts[team] = make([]int, numTeams)
+		generator.opponentCount[team] = make([]int, numTeams)
+	}
+	for slot, team := range slots {
+		generator.appearances[team] = append(generator.appearances[team], slot)
+	}
+	for match := 0; match < numMatches; match++ {
+		generator.cost += generator.updateMatchPairs(match, 1)
+	}
+	for team := 0; team < numTeams; team++ {
+		generator.cost += generator.teamCost(team)
+	}
+	return &generator
+}
+
+// Returns true if swapping the teams in the given slots changes the schedule without putting a team in the same
+// match twice.
+func (generator *scheduleGenerator) canSwap(slotA, slotB int) bool {
+	teamA, teamB := generator.slots[slotA], generator.slots[slotB]
+	if teamA == teamB {
+		return false
+	}
+	matchA, matchB := slotA/TeamsPerMatch, slotB/TeamsPerMatch
+	if matchA == matchB {
+		// Swapping within a match only matters if it moves the teams between alliances.
+		return isRedSlot(slotA) != isRedSlot(slotB)
+	}
+	return !containsTeam(generator.matchSlots(matchA), teamB) && !containsTeam(generator.matchSlots(matchB), teamA)
+}
+
+// Swaps the teams in the given slots and returns the resulting change in cost.
+func (generator *scheduleGenerator) swap(slotA, slotB int) float64 {
+	teamA, teamB := generator.slots[slotA], generator.slots[slotB]
+	matchA, matchB := slotA/TeamsPerMatch, slotB/TeamsPerMatch
+
+	delta := -generator.teamCost(teamA) - generator.teamCost(teamB)
+	delta += generator.updateMatchPairs(matchA, -1)
+	if matchB != matchA {
+		delta += generator.updateMatchPairs(matchB, -1)
+	}
+
+	generator.slots[slotA], generator.slots[slotB] = teamB, teamA
+	replaceAppearance(generator.appearances[teamA], slotA, slotB)
+	replaceAppearance(generator.appearances[teamB], slotB, slotA)
+
+	delta += generator.updateMatchPairs(matchA, 1)
+	if matchB != matchA {
+		delta += generator.updateMatchPairs(matchB, 1)
+	}
+	delta += generator.teamCost(teamA) + generator.teamCost(teamB)
+
+	generator.cost += delta
+	return delta
+}
+
+// Adds (or removes, if the increment is negative) the partner and opponent pairings in the given match and returns
+// the resulting change in cost.
+func (generator *scheduleGenerator) updateMatchPairs(match, increment int) float64 {
+	delta := 0.0
+	matchSlots := generator.matchSlots(match)
+	for i := 0; i < TeamsPerMatch; i++ {
+		for j := i + 1; j < TeamsPerMatch; j++ {
+			teamI, teamJ := matchSlots[i], matchSlots[j]
+			counts, weight := generator.opponentCount, opponentRepeatWeight
+			if isRedSlot(i) == isRedSlot(j) {
+				counts, weight = generator.partnerCounts, partnerRepeatWeight
+			}
+			oldCount := counts[teamI][teamJ]
+			newCount := oldCount + increment
+			counts[teamI][teamJ], counts[teamJ][teamI] = newCount, newCount
+			delta += float64(weight * (newCount*newCount - oldCount*oldCount))
+		}
+	}
+	return delta
+}
+
+// Returns the cost of the given team's insufficient rest between matches, imbalance between alliance colors and
+// matches scheduled outside of its availability window.
+func (generator *scheduleGenerator) teamCost(team int) float64 {
+	cost := 0
+	redCount := 0
+	appearances := generator.appearances[team]
+	for i, slot := range appearances {
+		if isRedSlot(slot) {
+			redCount++
+		}
+		if generator.isUnavailable(team, slot) {
+			cost += availabilityWeight
+		}
+		if i > 0 {
+			gap := slot/TeamsPerMatch - appearances[i-1]/TeamsPerMatch
+			if shortfall := generator.minTurnaround - gap; shortfall > 0 {
+				cost += turnaroundWeight * shortfall * shortfall
+			}
+		}
+	}
+	imbalance := int(math.Abs(float64(2*redCount-len(appearances)))) - 1
+	if imbalance > 0 {
+		cost += colorImbalanceWeight * imbalance * imbalance
+	}
+	return float64(cost)
+}
+
+// Returns true if every team gets at least the minimum turnaround between its matches and is only scheduled into
+// matches it is available for.
+func (generator *scheduleGenerator) isValid() bool {
+	for team, appearances := range generator.appearances {
+		for i, slot := range appearances {
+			if generator.isUnavailable(team, slot) {
+				return false
+			}
+			if i > 0 && slot/TeamsPerMatch-appearances[i-1]/TeamsPerMatch < generator.minTurnaround {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Returns true if the given team cannot play in the match containing the given slot.
+func (generator *scheduleGenerator) isUnavailable(team, slot int) bool {
+	return generator.unavailable != nil && generator.unavailable[team][slot/TeamsPerMatch]
+}
+
+// Converts the schedule into rows of one-based team numbers and surrogate flags. Teams that play an extra match to
+// fill out the schedule are marked as surrogates in their third match, per the usual FRC convention.
+func (generator *scheduleGenerator) toAnonymousSchedule(surrogates []bool) [][12]int {
+	surrogateSlots := make(map[int]bool)
+	for team, appearances := range generator.appearances {
+		if surrogates[team] {
+			surrogateSlots[appearances[min(2, len(appearances)-1)]] = true
+		}
+	}
+
+	schedule := make([][12]int, len(generator.slots)/TeamsPerMatch)
+	for slot, team := range generator.slots {
+		match, position := slot/TeamsPerMatch, slot%TeamsPerMatch
+		schedule[match][2*position] = team + 1
+		if surrogateSlots[slot] {
+			schedule[match][2*position+1] = 1
+		}
+	}
+	return schedule
+}
+
+// Returns the teams in the slots belonging to the given match.
+func (generator *scheduleGenerator) matchSlots(match int) []int {
+	return generator.slots[match*TeamsPerMatch : (match+1)*TeamsPerMatch]
+}
+
+// Returns true if the given slot belongs to the red alliance.
+func isRedSlot(slot int) bool {
+	return slot%TeamsPerMatch < TeamsPerMatch/2
+}
+
+// Replaces one slot in a team's sorted list of appearances with another, keeping the list sorted.
+func replaceAppearance(appearances []int, oldSlot, newSlot int) {
+	i := 0
+	for appearances[i] != oldSlot {
+		i++
+	}
+	appearances[i] = newSlot
+	for i > 0 && appearances[i-1] > appearances[i] {
+		appearances[i-1], appearances[i] = appearances[i], appearances[i-1]
+		i--
+	}
+	for i < len(appearances)-1 && appearances[i+1] < appearances[i] {
+		appearances[i+1], appearances[i] = appearances[i], appearances[i+1]
+		i++
+	}
+}
+
+// Returns true if the given team is among the given teams.
+func containsTeam(teams []int, team int) bool {
+	for _, t := range teams {
+		if t == team {
+			return true
+		}
+	}
+	return false
+}
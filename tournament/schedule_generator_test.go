@@ -0,0 +1,150 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package tournament
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestGenerateAnonymousSchedule(t *testing.T) {
+	rand.Seed(0)
+	for _, params := range []struct{ numTeams, matchesPerTeam int }{{6, 3}, {11, 5}, {29, 8}, {43, 12}} {
+		numMatches := (params.numTeams*params.matchesPerTeam + TeamsPerMatch - 1) / TeamsPerMatch
+		schedule, err := generateAnonymousSchedule(params.numTeams, params.matchesPerTeam, numMatches, 3, nil)
+		if !assert.Nil(t, err) {
+			continue
+		}
+		assert.Equal(t, numMatches, len(schedule))
+		minTurnaround := max(1, min(3, params.numTeams/TeamsPerMatch-1))
+
+		appearances := make(map[int][]int)
+		redCounts := make(map[int]int)
+		surrogateCounts := make(map[int]int)
+		for matchIndex, match := range schedule {
+			matchTeams := make(map[int]bool)
+			for position := 0; position < TeamsPerMatch; position++ {
+				team := match[2*position]
+				assert.False(t, matchTeams[team], "team %d appears twice in match %d", team, matchIndex+1)
+				matchTeams[team] = true
+				appearances[team] = append(appearances[team], matchIndex)
+				if position < TeamsPerMatch/2 {
+					redCounts[team]++
+				}
+				surrogateCounts[team] += match[2*position+1]
+			}
+		}
+
+		assert.Equal(t, params.numTeams, len(appearances))
+		numSurrogates := 0
+		for team, teamMatches := range appearances {
+			if len(teamMatches) == params.matchesPerTeam+1 {
+				assert.Equal(t, 1, surrogateCounts[team])
+				numSurrogates++
+			} else {
+				assert.Equal(t, params.matchesPerTeam, len(teamMatches))
+				assert.Equal(t, 0, surrogateCounts[team])
+			}
+			for i := 1; i < len(teamMatches); i++ {
+				assert.GreaterOrEqual(t, teamMatches[i]-teamMatches[i-1], minTurnaround)
+			}
+			assert.LessOrEqual(t, abs(2*redCounts[team]-len(teamMatches)), 2)
+		}
+		assert.Equal(t, numMatches*TeamsPerMatch-params.numTeams*params.matchesPerTeam, numSurrogates)
+	}
+}
+
+func TestGenerateAnonymousScheduleLimitsRepeats(t *testing.T) {
+	rand.Seed(0)
+	schedule, err := generateAnonymousSchedule(40, 10, 67, 3, nil)
+	assert.Nil(t, err)
+
+	// With 40 teams there is enough room for nobody to be paired with the same partner more than once.
+	partners := make(map[[2]int]int)
+	for _, match := range schedule {
+		for _, alliance := range [][]int{{match[0], match[2], match[4]}, {match[6], match[8], match[10]}} {
+			for i := 0; i < len(alliance); i++ {
+				for j := i + 1; j < len(alliance); j++ {
+					partners[[2]int{min(alliance[i], alliance[j]), max(alliance[i], alliance[j])}]++
+				}
+			}
+		}
+	}
+	for pair, count := range partners {
+		assert.Equal(t, 1, count, "teams %v are partnered %d times", pair, count)
+	}
+}
+
+func TestBuildRandomScheduleWithoutTemplate(t *testing.T) {
+	rand.Seed(0)
+	numTeams := 101
+	teams := make([]model.Team, numTeams)
+	for i := 0; i < numTeams; i++ {
+		teams[i].Id = i + 1001
+	}
+	scheduleBlocks := []model.ScheduleBlock{{StartTime: time.Unix(0, 0).UTC(), NumMatches: 200, MatchSpacingSec: 360}}
+	matches, err := BuildRandomSchedule(teams, scheduleBlocks, "test")
+	assert.Nil(t, err)
+	assert.Equal(t, 186, len(matches))
+	matchCounts := make(map[int]int)
+	for _, match := range matches {
+		for _, team := range []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3} {
+			matchCounts[team]++
+		}
+	}
+	assert.Equal(t, numTeams, len(matchCounts))
+	assert.Equal(t, time.Unix(185*360, 0).UTC(), matches[185].Time)
+}
+
+func TestBuildRandomScheduleWithTeamAvailability(t *testing.T) {
+	rand.Seed(0)
+	numTeams := 18
+	teams := make([]model.Team, numTeams)
+	for i := 0; i < numTeams; i++ {
+		teams[i].Id = i + 101
+	}
+	startTime := time.Unix(0, 0).UTC()
+	teams[0].AvailableFrom = startTime.Add(6 * 360 * time.Second)
+	teams[1].AvailableUntil = startTime.Add(11 * 360 * time.Second)
+	teams[2].AvailableFrom = startTime.Add(3 * 360 * time.Second)
+	teams[2].AvailableUntil = startTime.Add(14 * 360 * time.Second)
+	scheduleBlocks := []model.ScheduleBlock{{StartTime: startTime, NumMatches: 18, MatchSpacingSec: 360}}
+	matches, err := BuildRandomSchedule(teams, scheduleBlocks, "test")
+	assert.Nil(t, err)
+	assert.Equal(t, 18, len(matches))
+	matchCounts := make(map[int]int)
+	for _, match := range matches {
+		for _, teamId := range []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3} {
+			matchCounts[teamId]++
+			team := teams[teamId-101]
+			assert.True(t, team.IsAvailableAt(match.Time), "team %d is unavailable for match %s", teamId,
+				match.DisplayName)
+		}
+	}
+	for _, team := range teams {
+		assert.Equal(t, 6, matchCounts[team.Id])
+	}
+
+	// A team that isn't around long enough to play all of its matches.
+	teams[1].AvailableUntil = startTime.Add(4 * 360 * time.Second)
+	_, err = BuildRandomSchedule(teams, scheduleBlocks, "test")
+	assert.EqualError(t, err, "Team 102 is only available long enough to play 3 of its 6 matches")
+
+	// Too many teams missing from the same match.
+	teams[1].AvailableUntil = time.Time{}
+	for i := 3; i < 15; i++ {
+		teams[i].AvailableUntil = startTime.Add(16 * 360 * time.Second)
+	}
+	_, err = BuildRandomSchedule(teams, scheduleBlocks, "test")
+	assert.EqualError(t, err, "Only 5 teams are available to play in match 18")
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
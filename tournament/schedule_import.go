@@ -0,0 +1,273 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Functions for importing a match schedule that was produced by another tool.
+
+package tournament
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts accepted for imported match times, starting with the ones written by the schedule report and the FRC Events
+// API.
+var importTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02T15:04:05.999999999",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// Stations in the order in which they appear in the CSV columns and in each match's team fields.
+var importStations = []string{"Red1", "Red2", "Red3", "Blue1", "Blue2", "Blue3"}
+
+type frcEventsSchedule struct {
+	Schedule []struct {
+		StartTime       string
+		MatchNumber     int
+		TournamentLevel string
+		Teams           []struct {
+			TeamNumber int
+			Station    string
+			Surrogate  bool
+		}
+	}
+}
+
+// Parses a schedule of the given match type from either the CSV format written by the schedule report or the schedule
+// JSON returned by the FRC Events API, detecting which one it is from the content.
+func ParseSchedule(data []byte, matchType string) ([]model.Match, error) {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	var matches []model.Match
+	var err error
+	if bytes.HasPrefix(data, []byte("{")) {
+		matches, err = parseFrcEventsSchedule(data, matchType)
+	} else {
+		matches, err = parseCsvSchedule(data, matchType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("The schedule doesn't contain any matches")
+	}
+	return matches, nil
+}
+
+// Checks that every team in the given schedule is part of the event and that the surrogate flags make sense.
+func ValidateSchedule(matches []model.Match, teams []model.Team) error {
+	teamIds := make(map[int]bool, len(teams))
+	for _, team := range teams {
+		teamIds[team.Id] = true
+	}
+	matchNames := make(map[string]bool, len(matches))
+	surrogateMatches := make(map[int]string)
+	for _, match := range matches {
+		if matchNames[match.DisplayName] {
+			return fmt.Errorf("Match %s appears more than once", match.DisplayName)
+		}
+		matchNames[match.DisplayName] = true
+
+		matchTeams := make(map[int]bool)
+		for i, station := range importStations {
+			teamId, isSurrogate := getStationTeam(&match, i)
+			if teamId == 0 {
+				if isSurrogate {
+					return fmt.Errorf("Match %s has no team in %s to be a surrogate", match.DisplayName, station)
+				}
+				continue
+			}
+			if !teamIds[teamId] {
+				return fmt.Errorf("Team %d in match %s is not part of the event", teamId, match.DisplayName)
+			}
+			if matchTeams[teamId] {
+				return fmt.Errorf("Team %d appears more than once in match %s", teamId, match.DisplayName)
+			}
+			matchTeams[teamId] = true
+			if isSurrogate {
+				if previousMatch, ok := surrogateMatches[teamId]; ok {
+					return fmt.Errorf("Team %d is a surrogate in both match %s and match %s", teamId, previousMatch,
+						match.DisplayName)
+				}
+				surrogateMatches[teamId] = match.DisplayName
+			}
+		}
+	}
+	return nil
+}
+
+// Reconstructs the schedule blocks that the given matches were laid out in, starting a new block wherever the gap
+// between consecutive matches exceeds the given maximum.
+func GetScheduleBlocks(matches []model.Match, maxMatchGap time.Duration) []model.ScheduleBlock {
+	var scheduleBlocks []model.ScheduleBlock
+	blockStart := 0
+	for i := range matches {
+		if i+1 < len(matches) && matches[i+1].Time.Sub(matches[i].Time) <= maxMatchGap {
+			continue
+		}
+		block := model.ScheduleBlock{
+			MatchType:  matches[blockStart].Type,
+			StartTime:  matches[blockStart].Time,
+			NumMatches: i - blockStart + 1,
+		}
+		if block.NumMatches > 1 {
+			block.MatchSpacingSec = int(matches[i].Time.Sub(block.StartTime).Seconds()) / (block.NumMatches - 1)
+		}
+		scheduleBlocks = append(scheduleBlocks, block)
+		blockStart = i + 1
+	}
+	return scheduleBlocks
+}
+
+// Parses a schedule in the CSV format written by the schedule report, finding the columns by their headers.
+func parseCsvSchedule(data []byte, matchType string) ([]model.Match, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid CSV: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("The schedule doesn't contain any matches")
+	}
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		columns[strings.TrimSpace(header)] = i
+	}
+	for _, header := range append([]string{"Match", "Time"}, importStations...) {
+		if _, ok := columns[header]; !ok {
+			return nil, fmt.Errorf("CSV is missing the %s column", header)
+		}
+	}
+	getValue := func(record []string, header string) string {
+		if column, ok := columns[header]; ok && column < len(record) {
+			return strings.TrimSpace(record[column])
+		}
+		return ""
+	}
+
+	var matches []model.Match
+	for i, record := range records[1:] {
+		line := i + 2
+		match := model.Match{Type: matchType, DisplayName: getValue(record, "Match")}
+		if match.DisplayName == "" {
+			return nil, fmt.Errorf("Line %d has no match name", line)
+		}
+		if recordType := getValue(record, "Type"); recordType != "" && recordType != matchType {
+			return nil, fmt.Errorf("Match %s is a %s match rather than a %s match", match.DisplayName, recordType,
+				matchType)
+		}
+		if match.Time, err = parseImportTime(getValue(record, "Time")); err != nil {
+			return nil, fmt.Errorf("Match %s has an invalid time: %v", match.DisplayName, err)
+		}
+		for j, station := range importStations {
+			teamId := 0
+			if value := getValue(record, station); value != "" {
+				if teamId, err = strconv.Atoi(value); err != nil {
+					return nil, fmt.Errorf("Match %s has an invalid team in %s: %s", match.DisplayName, station, value)
+				}
+			}
+			isSurrogate := false
+			if value := getValue(record, station+"IsSurrogate"); value != "" {
+				if isSurrogate, err = strconv.ParseBool(value); err != nil {
+					return nil, fmt.Errorf("Match %s has an invalid surrogate flag in %s: %s", match.DisplayName,
+						station, value)
+				}
+			}
+			setStationTeam(&match, j, teamId, isSurrogate)
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// Parses a schedule in the JSON format returned by the FRC Events API schedule endpoint, whose times are local to the
+// event.
+func parseFrcEventsSchedule(data []byte, matchType string) ([]model.Match, error) {
+	var schedule frcEventsSchedule
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("Invalid JSON: %v", err)
+	}
+
+	var matches []model.Match
+	for _, frcMatch := range schedule.Schedule {
+		match := model.Match{Type: matchType, DisplayName: strconv.Itoa(frcMatch.MatchNumber)}
+		if frcMatch.TournamentLevel != "" && !strings.EqualFold(frcMatch.TournamentLevel, matchType) {
+			return nil, fmt.Errorf("Match %s is a %s match rather than a %s match", match.DisplayName,
+				frcMatch.TournamentLevel, matchType)
+		}
+		var err error
+		if match.Time, err = parseImportTime(frcMatch.StartTime); err != nil {
+			return nil, fmt.Errorf("Match %s has an invalid time: %v", match.DisplayName, err)
+		}
+		for _, team := range frcMatch.Teams {
+			index := -1
+			for i, station := range importStations {
+				if strings.EqualFold(team.Station, station) {
+					index = i
+				}
+			}
+			if index == -1 {
+				return nil, fmt.Errorf("Match %s has an invalid station: %s", match.DisplayName, team.Station)
+			}
+			setStationTeam(&match, index, team.TeamNumber, team.Surrogate)
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// Parses a match time in any of the accepted layouts, treating times without a zone as local.
+func parseImportTime(value string) (time.Time, error) {
+	// Drop the monotonic clock reading that time.Time.String() may append.
+	if index := strings.Index(value, " m="); index >= 0 {
+		value = value[:index]
+	}
+	for _, layout := range importTimeLayouts {
+		if matchTime, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return matchTime, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time format '%s'", value)
+}
+
+// Returns the team and surrogate flag for the station at the given index in importStations.
+func getStationTeam(match *model.Match, index int) (int, bool) {
+	switch index {
+	case 0:
+		return match.Red1, match.Red1IsSurrogate
+	case 1:
+		return match.Red2, match.Red2IsSurrogate
+	case 2:
+		return match.Red3, match.Red3IsSurrogate
+	case 3:
+		return match.Blue1, match.Blue1IsSurrogate
+	case 4:
+		return match.Blue2, match.Blue2IsSurrogate
+	default:
+		return match.Blue3, match.Blue3IsSurrogate
+	}
+}
+
+// Sets the team and surrogate flag for the station at the given index in importStations.
+func setStationTeam(match *model.Match, index int, teamId int, isSurrogate bool) {
+	switch index {
+	case 0:
+		match.Red1, match.Red1IsSurrogate = teamId, isSurrogate
+	case 1:
+		match.Red2, match.Red2IsSurrogate = teamId, isSurrogate
+	case 2:
+		match.Red3, match.Red3IsSurrogate = teamId, isSurrogate
+	case 3:
+		match.Blue1, match.Blue1IsSurrogate = teamId, isSurrogate
+	case 4:
+		match.Blue2, match.Blue2IsSurrogate = teamId, isSurrogate
+	default:
+		match.Blue3, match.Blue3IsSurrogate = teamId, isSurrogate
+	}
+}
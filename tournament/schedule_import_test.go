@@ -0,0 +1,146 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package tournament
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseCsvSchedule(t *testing.T) {
+	data := "Match,Type,Time,Red1,Red1IsSurrogate,Red2,Red2IsSurrogate,Red3,Red3IsSurrogate,Blue1,Blue1IsSurrogate," +
+		"Blue2,Blue2IsSurrogate,Blue3,Blue3IsSurrogate\n" +
+		"1,qualification,2026-03-14 09:00:00 +0000 UTC,101,false,102,false,103,false,104,false,105,false,106,true\n" +
+		"2,qualification,2026-03-14 09:07:00 +0000 UTC,107,false,108,false,109,false,110,false,111,false,112,false\n"
+	matches, err := ParseSchedule([]byte(data), "qualification")
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(matches)) {
+		assert.Equal(t, "1", matches[0].DisplayName)
+		assert.Equal(t, "qualification", matches[0].Type)
+		assert.True(t, time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC).Equal(matches[0].Time))
+		assert.Equal(t, 101, matches[0].Red1)
+		assert.Equal(t, 106, matches[0].Blue3)
+		assert.True(t, matches[0].Blue3IsSurrogate)
+		assert.False(t, matches[0].Red1IsSurrogate)
+		assert.Equal(t, 112, matches[1].Blue3)
+	}
+
+	// Columns can be in any order and the surrogate and type columns are optional.
+	data = "Blue3,Blue2,Blue1,Red3,Red2,Red1,Time,Match\n106,105,104,103,102,101,2026-03-14 09:00,Q1\n"
+	matches, err = ParseSchedule([]byte(data), "practice")
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(matches)) {
+		assert.Equal(t, "Q1", matches[0].DisplayName)
+		assert.Equal(t, "practice", matches[0].Type)
+		assert.Equal(t, 101, matches[0].Red1)
+		assert.Equal(t, 106, matches[0].Blue3)
+		assert.True(t, time.Date(2026, 3, 14, 9, 0, 0, 0, time.Local).Equal(matches[0].Time))
+	}
+}
+
+func TestParseCsvScheduleErrors(t *testing.T) {
+	header := "Match,Type,Time,Red1,Red2,Red3,Blue1,Blue2,Blue3,Blue3IsSurrogate\n"
+	_, err := ParseSchedule([]byte(header), "qualification")
+	assert.EqualError(t, err, "The schedule doesn't contain any matches")
+	_, err = ParseSchedule([]byte("Match,Time,Red1,Red2,Red3,Blue1,Blue2\n"), "qualification")
+	assert.EqualError(t, err, "CSV is missing the Blue3 column")
+	_, err = ParseSchedule([]byte(header+"1,practice,2026-03-14 09:00,1,2,3,4,5,6,false\n"), "qualification")
+	assert.EqualError(t, err, "Match 1 is a practice match rather than a qualification match")
+	_, err = ParseSchedule([]byte(header+"1,,tomorrow,1,2,3,4,5,6,false\n"), "qualification")
+	assert.EqualError(t, err, "Match 1 has an invalid time: unrecognized time format 'tomorrow'")
+	_, err = ParseSchedule([]byte(header+"1,,2026-03-14 09:00,1,2,3,4,5,x,false\n"), "qualification")
+	assert.EqualError(t, err, "Match 1 has an invalid team in Blue3: x")
+	_, err = ParseSchedule([]byte(header+"1,,2026-03-14 09:00,1,2,3,4,5,6,maybe\n"), "qualification")
+	assert.EqualError(t, err, "Match 1 has an invalid surrogate flag in Blue3: maybe")
+	_, err = ParseSchedule([]byte(header+",,2026-03-14 09:00,1,2,3,4,5,6,false\n"), "qualification")
+	assert.EqualError(t, err, "Line 2 has no match name")
+}
+
+func TestParseFrcEventsSchedule(t *testing.T) {
+	data := `{"Schedule": [
+		{"description": "Qualification 1", "startTime": "2026-03-14T09:00:00", "matchNumber": 1, "field": "Primary",
+		 "tournamentLevel": "Qualification", "teams": [
+			{"teamNumber": 101, "station": "Red1", "surrogate": false},
+			{"teamNumber": 102, "station": "Red2", "surrogate": false},
+			{"teamNumber": 103, "station": "Red3", "surrogate": true},
+			{"teamNumber": 104, "station": "Blue1", "surrogate": false},
+			{"teamNumber": 105, "station": "Blue2", "surrogate": false},
+			{"teamNumber": 106, "station": "Blue3", "surrogate": false}
+		]},
+		{"description": "Qualification 2", "startTime": "2026-03-14T09:07:00", "matchNumber": 2, "field": "Primary",
+		 "tournamentLevel": "Qualification", "teams": [
+			{"teamNumber": 107, "station": "Blue3", "surrogate": false}
+		]}
+	]}`
+	matches, err := ParseSchedule([]byte(data), "qualification")
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(matches)) {
+		assert.Equal(t, "1", matches[0].DisplayName)
+		assert.Equal(t, "qualification", matches[0].Type)
+		assert.True(t, time.Date(2026, 3, 14, 9, 0, 0, 0, time.Local).Equal(matches[0].Time))
+		assert.Equal(t, 101, matches[0].Red1)
+		assert.Equal(t, 103, matches[0].Red3)
+		assert.True(t, matches[0].Red3IsSurrogate)
+		assert.Equal(t, 106, matches[0].Blue3)
+		assert.Equal(t, "2", matches[1].DisplayName)
+		assert.Equal(t, 0, matches[1].Red1)
+		assert.Equal(t, 107, matches[1].Blue3)
+	}
+
+	_, err = ParseSchedule([]byte(data), "practice")
+	assert.EqualError(t, err, "Match 1 is a Qualification match rather than a practice match")
+	_, err = ParseSchedule([]byte(`{"Schedule": [{"matchNumber": 1, "startTime": "2026-03-14T09:00:00", `+
+		`"teams": [{"teamNumber": 101, "station": "Red4"}]}]}`), "practice")
+	assert.EqualError(t, err, "Match 1 has an invalid station: Red4")
+	_, err = ParseSchedule([]byte(`{"Schedule": []}`), "practice")
+	assert.EqualError(t, err, "The schedule doesn't contain any matches")
+	_, err = ParseSchedule([]byte(`{"Schedule": `), "practice")
+	assert.NotNil(t, err)
+}
+
+func TestValidateSchedule(t *testing.T) {
+	teams := make([]model.Team, 12)
+	for i := range teams {
+		teams[i].Id = i + 101
+	}
+	matches := []model.Match{
+		{DisplayName: "1", Red1: 101, Red2: 102, Red3: 103, Blue1: 104, Blue2: 105, Blue3: 106, Blue3IsSurrogate: true},
+		{DisplayName: "2", Red1: 107, Red2: 108, Red3: 109, Blue1: 110, Blue2: 111},
+	}
+	assert.Nil(t, ValidateSchedule(matches, teams))
+
+	matches[1].Blue3IsSurrogate = true
+	assert.EqualError(t, ValidateSchedule(matches, teams), "Match 2 has no team in Blue3 to be a surrogate")
+	matches[1].Blue3 = 254
+	assert.EqualError(t, ValidateSchedule(matches, teams), "Team 254 in match 2 is not part of the event")
+	matches[1].Blue3 = 107
+	assert.EqualError(t, ValidateSchedule(matches, teams), "Team 107 appears more than once in match 2")
+	matches[1].Blue3 = 106
+	assert.EqualError(t, ValidateSchedule(matches, teams), "Team 106 is a surrogate in both match 1 and match 2")
+	matches[1].Blue3IsSurrogate = false
+	matches[1].DisplayName = "1"
+	assert.EqualError(t, ValidateSchedule(matches, teams), "Match 1 appears more than once")
+}
+
+func TestGetScheduleBlocks(t *testing.T) {
+	start := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	var matches []model.Match
+	for i := 0; i < 5; i++ {
+		matches = append(matches, model.Match{Type: "qualification", Time: start.Add(time.Duration(i*7) * time.Minute)})
+	}
+	matches = append(matches, model.Match{Type: "qualification", Time: start.Add(4 * time.Hour)})
+	matches = append(matches, model.Match{Type: "qualification", Time: start.Add(4*time.Hour + 6*time.Minute)})
+
+	scheduleBlocks := GetScheduleBlocks(matches, 20*time.Minute)
+	assert.Equal(
+		t,
+		[]model.ScheduleBlock{
+			{MatchType: "qualification", StartTime: start, NumMatches: 5, MatchSpacingSec: 420},
+			{MatchType: "qualification", StartTime: start.Add(4 * time.Hour), NumMatches: 2, MatchSpacingSec: 360},
+		},
+		scheduleBlocks,
+	)
+	assert.Empty(t, GetScheduleBlocks(nil, 20*time.Minute))
+}
@@ -0,0 +1,282 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Web routes for alliance captains to choose which of their teams play each playoff match.
+
+package web
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/gorilla/mux"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// An alliance's lineup page is locked for a while after this many incorrect PINs in a row, to stop PINs being guessed.
+const (
+	maxLineupPinFailures     = 5
+	lineupPinLockoutDuration = 5 * time.Minute
+)
+
+// An alliance's lineup status, as shown to the scorekeeper.
+type allianceLineupStatus struct {
+	Alliance    *model.Alliance
+	NextMatch   *model.Match
+	IsSubmitted bool
+}
+
+// Tracks incorrect lineup PIN entries for each alliance.
+type lineupPinGuard struct {
+	mutex    sync.Mutex
+	failures map[int]*lineupPinFailures
+}
+
+type lineupPinFailures struct {
+	count       int
+	lockedUntil time.Time
+}
+
+func newLineupPinGuard() *lineupPinGuard {
+	return &lineupPinGuard{failures: make(map[int]*lineupPinFailures)}
+}
+
+// Returns an error if the given PIN doesn't match the alliance's, or if the alliance is locked out after too many
+// incorrect PINs.
+func (guard *lineupPinGuard) checkPin(alliance *model.Alliance, pin string) error {
+	guard.mutex.Lock()
+	defer guard.mutex.Unlock()
+
+	failures, ok := guard.failures[alliance.Id]
+	if !ok {
+		failures = &lineupPinFailures{}
+		guard.failures[alliance.Id] = failures
+	}
+	if time.Now().Before(failures.lockedUntil) {
+		return fmt.Errorf("Too many incorrect PINs. Try again after %s.", failures.lockedUntil.Format("3:04 PM"))
+	}
+	if alliance.LineupPin == "" || subtle.ConstantTimeCompare([]byte(pin), []byte(alliance.LineupPin)) != 1 {
+		failures.count++
+		if failures.count >= maxLineupPinFailures {
+			failures.count = 0
+			failures.lockedUntil = time.Now().Add(lineupPinLockoutDuration)
+		}
+		return fmt.Errorf("Incorrect PIN.")
+	}
+	delete(guard.failures, alliance.Id)
+	return nil
+}
+
+// Shows the page for an alliance captain to submit the lineup for the alliance's next playoff match.
+func (web *Web) allianceLineupGetHandler(w http.ResponseWriter, r *http.Request) {
+	allianceId, _ := strconv.Atoi(mux.Vars(r)["allianceId"])
+	web.renderAllianceLineup(w, r, allianceId, "", "")
+}
+
+// Saves the lineup submitted by an alliance captain for the alliance's next playoff match.
+func (web *Web) allianceLineupPostHandler(w http.ResponseWriter, r *http.Request) {
+	allianceId, _ := strconv.Atoi(mux.Vars(r)["allianceId"])
+	alliance, err := web.arena.Database.GetAllianceById(allianceId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if alliance == nil {
+		handleWebErr(w, fmt.Errorf("Invalid alliance ID %d.", allianceId))
+		return
+	}
+	if err = web.lineupPinGuard.checkPin(alliance, r.PostFormValue("pin")); err != nil {
+		web.renderAllianceLineup(w, r, allianceId, err.Error(), "")
+		return
+	}
+
+	match, err := web.getNextAllianceMatch(allianceId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if match == nil {
+		web.renderAllianceLineup(w, r, allianceId, "The alliance has no playoff matches left to play.", "")
+		return
+	}
+	if err = web.checkLineupSubmissionOpen(match); err != nil {
+		web.renderAllianceLineup(w, r, allianceId, err.Error(), "")
+		return
+	}
+
+	var lineup [3]int
+	for i := range lineup {
+		lineup[i], _ = strconv.Atoi(r.PostFormValue(fmt.Sprintf("station%d", i+1)))
+		if !slices.Contains(alliance.TeamIds, lineup[i]) {
+			web.renderAllianceLineup(w, r, allianceId, "Each station must be filled by a team from the alliance.", "")
+			return
+		}
+		if slices.Contains(lineup[:i], lineup[i]) {
+			web.renderAllianceLineup(w, r, allianceId, fmt.Sprintf("Team %d can't play in two stations.", lineup[i]), "")
+			return
+		}
+	}
+
+	alliance.SubmittedLineup = lineup
+	alliance.SubmittedLineupMatchId = match.Id
+	if err = web.arena.Database.UpdateAlliance(alliance); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	web.renderAllianceLineup(w, r, allianceId, "", fmt.Sprintf("Lineup submitted for match %s.", match.DisplayName))
+}
+
+// Shows the scorekeeper whether each alliance captain has submitted a lineup for their next playoff match.
+func (web *Web) allianceLineupsGetHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	alliances, err := web.arena.Database.GetAllAlliances()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	var statuses []allianceLineupStatus
+	for i := range alliances {
+		alliance := &alliances[i]
+		if len(alliance.LineupPin) != model.AllianceLineupPinLength {
+			// The alliance was created without a PIN, or with a shorter one from an older version; give it a new one
+			// so that its captain can use the lineup page.
+			if alliance.LineupPin, err = model.NewAllianceLineupPin(); err != nil {
+				handleWebErr(w, err)
+				return
+			}
+			if err = web.arena.Database.UpdateAlliance(alliance); err != nil {
+				handleWebErr(w, err)
+				return
+			}
+		}
+		status := allianceLineupStatus{Alliance: alliance}
+		if status.NextMatch, err = web.getNextAllianceMatch(alliance.Id); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		status.IsSubmitted = status.NextMatch != nil && alliance.SubmittedLineupMatchId == status.NextMatch.Id
+		statuses = append(statuses, status)
+	}
+
+	template, err := web.parseFiles("templates/alliance_lineups.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Statuses []allianceLineupStatus
+	}{web.arena.EventSettings, statuses}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+func (web *Web) renderAllianceLineup(
+	w http.ResponseWriter, r *http.Request, allianceId int, errorMessage, successMessage string,
+) {
+	alliance, err := web.arena.Database.GetAllianceById(allianceId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	if alliance == nil {
+		handleWebErr(w, fmt.Errorf("Invalid alliance ID %d.", allianceId))
+		return
+	}
+	match, err := web.getNextAllianceMatch(allianceId)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Start from the lineup already submitted for the match, or otherwise the one the alliance last played with.
+	lineup := alliance.Lineup
+	isSubmitted := match != nil && alliance.SubmittedLineupMatchId == match.Id
+	if isSubmitted {
+		lineup = alliance.SubmittedLineup
+	}
+	closedMessage := ""
+	cutoff := ""
+	if match != nil {
+		if err = web.checkLineupSubmissionOpen(match); err != nil {
+			closedMessage = err.Error()
+		} else if cutoffTime := web.getLineupCutoff(match); !cutoffTime.IsZero() {
+			cutoff = cutoffTime.Format("3:04 PM")
+		}
+	}
+
+	template, err := web.parseFiles("templates/alliance_lineup.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Alliance       *model.Alliance
+		Match          *model.Match
+		Lineup         [3]int
+		IsSubmitted    bool
+		Cutoff         string
+		ClosedMessage  string
+		ErrorMessage   string
+		SuccessMessage string
+	}{
+		web.arena.EventSettings,
+		alliance,
+		match,
+		lineup,
+		isSubmitted,
+		cutoff,
+		closedMessage,
+		errorMessage,
+		successMessage,
+	}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Returns the first playoff match that the given alliance has yet to play, or nil if there is none.
+func (web *Web) getNextAllianceMatch(allianceId int) (*model.Match, error) {
+	matches, err := web.arena.Database.GetMatchesByType("elimination")
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		if !match.IsComplete() && (match.ElimRedAlliance == allianceId || match.ElimBlueAlliance == allianceId) {
+			return &match, nil
+		}
+	}
+	return nil, nil
+}
+
+// Returns the time after which captains can no longer submit a lineup for the given match, or the zero time if there
+// is no cutoff ahead of the match being loaded.
+func (web *Web) getLineupCutoff(match *model.Match) time.Time {
+	if web.arena.EventSettings.ElimLineupCutoffSec <= 0 || match.Time.IsZero() {
+		return time.Time{}
+	}
+	return match.Time.Add(-time.Duration(web.arena.EventSettings.ElimLineupCutoffSec) * time.Second)
+}
+
+// Returns an error explaining why lineups can no longer be submitted for the given match, or nil if they still can.
+func (web *Web) checkLineupSubmissionOpen(match *model.Match) error {
+	if web.arena.CurrentMatch.Id == match.Id {
+		return fmt.Errorf("Lineups for match %s closed when the match was loaded onto the field.", match.DisplayName)
+	}
+	if cutoff := web.getLineupCutoff(match); !cutoff.IsZero() && time.Now().After(cutoff) {
+		return fmt.Errorf("Lineups for match %s closed at %s.", match.DisplayName, cutoff.Format("3:04 PM"))
+	}
+	return nil
+}
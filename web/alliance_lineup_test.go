@@ -0,0 +1,101 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package web
+
+import (
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/FRCTeam1987/crimson-arena/tournament"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAllianceLineup(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.NumElimAlliances = 2
+	tournament.CreateTestAlliances(web.arena.Database, 2)
+	startTime := time.Now().Add(time.Hour)
+	assert.Nil(t, web.arena.CreatePlayoffBracket())
+	assert.Nil(t, web.arena.UpdatePlayoffBracket(&startTime))
+
+	// Visiting the scorekeeper's page gives each alliance a PIN.
+	recorder := web.getHttpResponse("/alliances/lineups")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Not submitted")
+	alliance, _ := web.arena.Database.GetAllianceById(1)
+	assert.Len(t, alliance.LineupPin, model.AllianceLineupPinLength)
+	pin := alliance.LineupPin
+
+	recorder = web.getHttpResponse("/alliances/1/lineup")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Choose the teams to play in match F-1")
+	assert.Contains(t, recorder.Body.String(), `<option value="102" selected>`)
+
+	recorder = web.postHttpResponse("/alliances/1/lineup", "pin=wrong&station1=104&station2=101&station3=103")
+	assert.Contains(t, recorder.Body.String(), "Incorrect PIN.")
+	recorder = web.postHttpResponse("/alliances/1/lineup", "pin="+pin+"&station1=104&station2=201&station3=103")
+	assert.Contains(t, recorder.Body.String(), "Each station must be filled by a team from the alliance.")
+	recorder = web.postHttpResponse("/alliances/1/lineup", "pin="+pin+"&station1=104&station2=104&station3=103")
+	assert.Contains(t, recorder.Body.String(), "Team 104 can't play in two stations.")
+	recorder = web.postHttpResponse("/alliances/1/lineup", "pin="+pin+"&station1=104&station2=101&station3=103")
+	assert.Contains(t, recorder.Body.String(), "Lineup submitted for match F-1.")
+	recorder = web.getHttpResponse("/alliances/lineups")
+	assert.Contains(t, recorder.Body.String(), "Submitted: 104, 101, 103")
+
+	// Loading the match onto the field puts the submitted lineup into it and closes submissions.
+	matches, _ := web.arena.Database.GetMatchesByType("elimination")
+	assert.Nil(t, web.arena.LoadMatch(&matches[0]))
+	assert.Equal(t, 104, web.arena.CurrentMatch.Red1)
+	assert.Equal(t, 101, web.arena.CurrentMatch.Red2)
+	assert.Equal(t, 104, web.arena.AllianceStations["R1"].Team.Id)
+	alliance, _ = web.arena.Database.GetAllianceById(1)
+	assert.Equal(t, [3]int{104, 101, 103}, alliance.Lineup)
+	recorder = web.postHttpResponse("/alliances/1/lineup", "pin="+pin+"&station1=101&station2=102&station3=103")
+	assert.Contains(t, recorder.Body.String(), "Lineups for match F-1 closed when the match was loaded onto the field.")
+	assert.Nil(t, web.arena.LoadTestMatch())
+
+	// Submissions also close the configured time before the match is scheduled to start.
+	web.arena.EventSettings.ElimLineupCutoffSec = 7200
+	recorder = web.postHttpResponse("/alliances/1/lineup", "pin="+pin+"&station1=101&station2=102&station3=103")
+	assert.Contains(t, recorder.Body.String(), "Lineups for match F-1 closed at")
+	alliance, _ = web.arena.Database.GetAllianceById(1)
+	assert.Equal(t, [3]int{104, 101, 103}, alliance.SubmittedLineup)
+}
+
+func TestAllianceLineupPinLockout(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.NumElimAlliances = 2
+	tournament.CreateTestAlliances(web.arena.Database, 2)
+	startTime := time.Now().Add(time.Hour)
+	assert.Nil(t, web.arena.CreatePlayoffBracket())
+	assert.Nil(t, web.arena.UpdatePlayoffBracket(&startTime))
+
+	// An alliance with a PIN from an older version gets a new, longer one.
+	alliance, _ := web.arena.Database.GetAllianceById(1)
+	alliance.LineupPin = "1234"
+	assert.Nil(t, web.arena.Database.UpdateAlliance(alliance))
+	web.getHttpResponse("/alliances/lineups")
+	alliance, _ = web.arena.Database.GetAllianceById(1)
+	assert.Len(t, alliance.LineupPin, model.AllianceLineupPinLength)
+	pin := alliance.LineupPin
+
+	// Too many incorrect PINs in a row lock the alliance out, even with the right PIN.
+	for i := 0; i < maxLineupPinFailures; i++ {
+		recorder := web.postHttpResponse("/alliances/1/lineup", "pin=1234&station1=104&station2=101&station3=103")
+		assert.Contains(t, recorder.Body.String(), "Incorrect PIN.")
+	}
+	recorder := web.postHttpResponse("/alliances/1/lineup", "pin="+pin+"&station1=104&station2=101&station3=103")
+	assert.Contains(t, recorder.Body.String(), "Too many incorrect PINs.")
+
+	// Other alliances aren't affected.
+	alliance2, _ := web.arena.Database.GetAllianceById(2)
+	recorder = web.postHttpResponse(
+		"/alliances/2/lineup", "pin="+alliance2.LineupPin+"&station1=201&station2=202&station3=203",
+	)
+	assert.Contains(t, recorder.Body.String(), "Lineup submitted")
+
+	// The lockout expires after a while.
+	web.lineupPinGuard.failures[1].lockedUntil = time.Now().Add(-time.Second)
+	recorder = web.postHttpResponse("/alliances/1/lineup", "pin="+pin+"&station1=104&station2=101&station3=103")
+	assert.Contains(t, recorder.Body.String(), "Lineup submitted for match F-1.")
+}
@@ -9,20 +9,16 @@ import (
 	"fmt"
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/FRCTeam1987/crimson-arena/websocket"
+	"github.com/mitchellh/mapstructure"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
-type RankedTeam struct {
-	Rank   int
-	TeamId int
-	Picked bool
-}
-
-// Global var to hold the team rankings during the alliance selection.
-var cachedRankedTeams []*RankedTeam
-
 // Shows the alliance selection page.
 func (web *Web) allianceSelectionGetHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
@@ -32,7 +28,7 @@ func (web *Web) allianceSelectionGetHandler(w http.ResponseWriter, r *http.Reque
 	web.renderAllianceSelection(w, r, "")
 }
 
-// Updates the cache with the latest input from the client.
+// Updates the alliance selection with the latest input from the client.
 func (web *Web) allianceSelectionPostHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
 		return
@@ -43,64 +39,128 @@ func (web *Web) allianceSelectionPostHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Reset picked state for each team in preparation for reconstructing it.
-	newRankedTeams := make([]*RankedTeam, len(cachedRankedTeams))
-	for i, team := range cachedRankedTeams {
-		newRankedTeams[i] = &RankedTeam{team.Rank, team.TeamId, false}
+	// Work on a copy of the selection so that nothing changes unless all of the input is valid, and reset the picked and
+	// declined state for each team in preparation for reconstructing it.
+	snapshot := web.arena.AllianceSelection.Snapshot()
+	for i := range snapshot.RankedTeams {
+		snapshot.RankedTeams[i].Picked = false
+		snapshot.RankedTeams[i].Declined = false
+	}
+
+	// Record the teams that have declined each alliance's invitation. Per the tournament rules, a team that declines
+	// can't be invited again, so it may only appear once.
+	for i := range snapshot.Alliances {
+		declinesString := r.PostFormValue(fmt.Sprintf("declines%d", i))
+		declinedTeamIds := []int{}
+		for _, teamString := range strings.FieldsFunc(declinesString, func(c rune) bool { return c == ',' || c == ' ' }) {
+			teamId, err := strconv.Atoi(teamString)
+			if err != nil {
+				web.renderAllianceSelection(w, r, fmt.Sprintf("Invalid team number value '%s'.", teamString))
+				return
+			}
+			if err = declineRankedTeam(snapshot.RankedTeams, teamId); err != nil {
+				web.renderAllianceSelection(w, r, err.Error())
+				return
+			}
+			declinedTeamIds = append(declinedTeamIds, teamId)
+		}
+		snapshot.Alliances[i].DeclinedTeamIds = declinedTeamIds
 	}
 
 	// Iterate through all selections and update the alliances.
-	for i, alliance := range web.arena.AllianceSelectionAlliances {
+	for i, alliance := range snapshot.Alliances {
 		for j := range alliance.TeamIds {
 			teamString := r.PostFormValue(fmt.Sprintf("selection%d_%d", i, j))
 			if teamString == "" {
-				web.arena.AllianceSelectionAlliances[i].TeamIds[j] = 0
+				snapshot.Alliances[i].TeamIds[j] = 0
 			} else {
 				teamId, err := strconv.Atoi(teamString)
 				if err != nil {
 					web.renderAllianceSelection(w, r, fmt.Sprintf("Invalid team number value '%s'.", teamString))
 					return
 				}
-				found := false
-				for _, team := range newRankedTeams {
-					if team.TeamId == teamId {
-						if team.Picked {
-							web.renderAllianceSelection(w, r,
-								fmt.Sprintf("Team %d is already part of an alliance.", teamId))
-							return
-						}
-						found = true
-						team.Picked = true
-						web.arena.AllianceSelectionAlliances[i].TeamIds[j] = teamId
-						break
-					}
-				}
-				if !found {
-					web.renderAllianceSelection(
-						w,
-						r,
-						fmt.Sprintf(
-							"Team %d has not played any matches at this event and is ineligible for selection.", teamId,
-						),
-					)
+				if err = pickRankedTeam(snapshot.RankedTeams, teamId, j == 0); err != nil {
+					web.renderAllianceSelection(w, r, err.Error())
 					return
 				}
+				snapshot.Alliances[i].TeamIds[j] = teamId
 			}
 		}
 	}
-	cachedRankedTeams = newRankedTeams
 
-	web.arena.AllianceSelectionNotifier.Notify()
+	if err := web.updateAllianceSelection(snapshot, false); err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	http.Redirect(w, r, "/alliance_selection", 303)
 }
 
+// The websocket endpoint for the alliance selection client to make picks and control the pick countdown.
+func (web *Web) allianceSelectionWebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	ws, err := websocket.NewWebsocket(w, r)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	defer ws.Close()
+
+	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
+	go ws.HandleNotifiers(web.arena.AllianceSelectionNotifier)
+
+	// Loop, waiting for commands and responding to them, until the client closes the connection.
+	for {
+		messageType, data, err := ws.Read()
+		if err != nil {
+			if err == io.EOF {
+				// Client has closed the connection; nothing to do here.
+				return
+			}
+			log.Println(err)
+			return
+		}
+
+		switch messageType {
+		case "pick", "decline":
+			args := struct {
+				TeamId int
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			if messageType == "pick" {
+				err = web.pickAllianceSelectionTeam(args.TeamId)
+			} else {
+				err = web.declineAllianceSelectionTeam(args.TeamId)
+			}
+		case "undo":
+			err = web.undoAllianceSelection()
+		case "startTimer":
+			err = web.setAllianceSelectionTimer(true)
+		case "stopTimer":
+			err = web.setAllianceSelectionTimer(false)
+		default:
+			ws.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
+			continue
+		}
+		if err != nil {
+			ws.WriteError(err.Error())
+		}
+	}
+}
+
 // Sets up the empty alliances and populates the ranked team list.
 func (web *Web) allianceSelectionStartHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
 		return
 	}
 
-	if len(web.arena.AllianceSelectionAlliances) != 0 {
+	if len(web.arena.AllianceSelection.Alliances) != 0 {
 		web.renderAllianceSelection(w, r, "Can't start alliance selection when it is already in progress.")
 		return
 	}
@@ -110,14 +170,15 @@ func (web *Web) allianceSelectionStartHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	// Create a blank alliance set matching the event configuration.
-	web.arena.AllianceSelectionAlliances = make([]model.Alliance, web.arena.EventSettings.NumElimAlliances)
+	allianceSelection := web.arena.AllianceSelection
+	allianceSelection.Alliances = make([]model.Alliance, web.arena.EventSettings.NumElimAlliances)
 	teamsPerAlliance := 3
 	if web.arena.EventSettings.SelectionRound3Order != "" {
 		teamsPerAlliance = 4
 	}
 	for i := 0; i < web.arena.EventSettings.NumElimAlliances; i++ {
-		web.arena.AllianceSelectionAlliances[i].Id = i + 1
-		web.arena.AllianceSelectionAlliances[i].TeamIds = make([]int, teamsPerAlliance)
+		allianceSelection.Alliances[i].Id = i + 1
+		allianceSelection.Alliances[i].TeamIds = make([]int, teamsPerAlliance)
 	}
 
 	// Populate the ranked list of teams.
@@ -126,11 +187,17 @@ func (web *Web) allianceSelectionStartHandler(w http.ResponseWriter, r *http.Req
 		handleWebErr(w, err)
 		return
 	}
-	cachedRankedTeams = make([]*RankedTeam, len(rankings))
+	allianceSelection.RankedTeams = make([]model.RankedTeam, len(rankings))
 	for i, ranking := range rankings {
-		cachedRankedTeams[i] = &RankedTeam{i + 1, ranking.TeamId, false}
+		allianceSelection.RankedTeams[i] = model.RankedTeam{Rank: i + 1, TeamId: ranking.TeamId}
 	}
+	allianceSelection.UndoHistory = nil
+	allianceSelection.PickDeadline = time.Time{}
 
+	if err = web.arena.Database.UpdateAllianceSelection(allianceSelection); err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	web.arena.AllianceSelectionNotifier.Notify()
 	http.Redirect(w, r, "/alliance_selection", 303)
 }
@@ -171,8 +238,11 @@ func (web *Web) allianceSelectionResetHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
+	// Discard the in-progress selection along with its undo history.
+	if web.arena.AllianceSelection, err = web.arena.Database.ResetAllianceSelection(); err != nil {
+		handleWebErr(w, err)
+		return
+	}
 	web.arena.AllianceSelectionNotifier.Notify()
 	http.Redirect(w, r, "/alliance_selection", 303)
 }
@@ -196,7 +266,7 @@ func (web *Web) allianceSelectionFinalizeHandler(w http.ResponseWriter, r *http.
 	}
 
 	// Check that all spots are filled.
-	for _, alliance := range web.arena.AllianceSelectionAlliances {
+	for _, alliance := range web.arena.AllianceSelection.Alliances {
 		for _, allianceTeamId := range alliance.TeamIds {
 			if allianceTeamId <= 0 {
 				web.renderAllianceSelection(w, r, "Can't finalize alliance selection until all spots have been filled.")
@@ -206,20 +276,33 @@ func (web *Web) allianceSelectionFinalizeHandler(w http.ResponseWriter, r *http.
 	}
 
 	// Save alliances to the database.
-	for _, alliance := range web.arena.AllianceSelectionAlliances {
+	for _, alliance := range web.arena.AllianceSelection.Alliances {
 		// Populate the initial lineup according to the tournament rules (alliance captain in the middle, first pick on
 		// the left, second pick on the right).
 		alliance.Lineup[0] = alliance.TeamIds[1]
 		alliance.Lineup[1] = alliance.TeamIds[0]
 		alliance.Lineup[2] = alliance.TeamIds[2]
+		alliance.LineupPin, err = model.NewAllianceLineupPin()
+		if err != nil {
+			handleWebErr(w, err)
+			return
+		}
 
-		err := web.arena.Database.CreateAlliance(&alliance)
+		err = web.arena.Database.CreateAlliance(&alliance)
 		if err != nil {
 			handleWebErr(w, err)
 			return
 		}
 	}
 
+	// The pick countdown is no longer needed once the alliances are set.
+	web.arena.AllianceSelection.PickDeadline = time.Time{}
+	if err = web.arena.Database.UpdateAllianceSelection(web.arena.AllianceSelection); err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	web.arena.AllianceSelectionNotifier.Notify()
+
 	// Generate the first round of elimination matches.
 	if err = web.arena.CreatePlayoffBracket(); err != nil {
 		handleWebErr(w, err)
@@ -278,15 +361,15 @@ func (web *Web) allianceSelectionPublishHandler(w http.ResponseWriter, r *http.R
 }
 
 func (web *Web) renderAllianceSelection(w http.ResponseWriter, r *http.Request, errorMessage string) {
-	if len(web.arena.AllianceSelectionAlliances) == 0 {
-		// The application may have been restarted since the alliance selection was conducted; try reloading the
+	if len(web.arena.AllianceSelection.Alliances) == 0 {
+		// The alliance selection may have been conducted before its progress was saved; try reloading the finalized
 		// alliances from the DB.
-		var err error
-		web.arena.AllianceSelectionAlliances, err = web.arena.Database.GetAllAlliances()
+		alliances, err := web.arena.Database.GetAllAlliances()
 		if err != nil {
 			handleWebErr(w, err)
 			return
 		}
+		web.arena.AllianceSelection.Alliances = model.WithoutLineupPins(alliances)
 	}
 
 	template, err := web.parseFiles("templates/alliance_selection.html", "templates/base.html")
@@ -298,11 +381,20 @@ func (web *Web) renderAllianceSelection(w http.ResponseWriter, r *http.Request,
 	data := struct {
 		*model.EventSettings
 		Alliances    []model.Alliance
-		RankedTeams  []*RankedTeam
+		RankedTeams  []model.RankedTeam
 		NextRow      int
 		NextCol      int
+		CanUndo      bool
 		ErrorMessage string
-	}{web.arena.EventSettings, web.arena.AllianceSelectionAlliances, cachedRankedTeams, nextRow, nextCol, errorMessage}
+	}{
+		web.arena.EventSettings,
+		web.arena.AllianceSelection.Alliances,
+		web.arena.AllianceSelection.RankedTeams,
+		nextRow,
+		nextCol,
+		len(web.arena.AllianceSelection.UndoHistory) > 0,
+		errorMessage,
+	}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -333,10 +425,160 @@ func (web *Web) canResetAllianceSelection() bool {
 	return true
 }
 
+// Places the given team in the next open spot of the alliance selection.
+func (web *Web) pickAllianceSelectionTeam(teamId int) error {
+	if err := web.checkAllianceSelectionInProgress(); err != nil {
+		return err
+	}
+	row, col := web.determineNextCell()
+	if row == -1 {
+		return fmt.Errorf("All alliance selection spots have already been filled.")
+	}
+
+	snapshot := web.arena.AllianceSelection.Snapshot()
+	if err := pickRankedTeam(snapshot.RankedTeams, teamId, col == 0); err != nil {
+		return err
+	}
+	snapshot.Alliances[row].TeamIds[col] = teamId
+	return web.updateAllianceSelection(snapshot, true)
+}
+
+// Records that the given team declined the invitation of the alliance that is currently picking.
+func (web *Web) declineAllianceSelectionTeam(teamId int) error {
+	if err := web.checkAllianceSelectionInProgress(); err != nil {
+		return err
+	}
+	row, col := web.determineNextCell()
+	if row == -1 || col == 0 {
+		return fmt.Errorf("No alliance is currently making a pick.")
+	}
+
+	snapshot := web.arena.AllianceSelection.Snapshot()
+	if err := declineRankedTeam(snapshot.RankedTeams, teamId); err != nil {
+		return err
+	}
+	// Once part of an alliance, a team may only be invited by a higher-seeded alliance while it is a captain.
+	captainRow := -1
+	for i, alliance := range snapshot.Alliances {
+		if alliance.TeamIds[0] == teamId {
+			captainRow = i
+		}
+	}
+	if findRankedTeam(snapshot.RankedTeams, teamId).Picked && (captainRow == -1 || captainRow <= row) {
+		return fmt.Errorf("Team %d is already part of an alliance.", teamId)
+	}
+	snapshot.Alliances[row].DeclinedTeamIds = append(snapshot.Alliances[row].DeclinedTeamIds, teamId)
+	return web.updateAllianceSelection(snapshot, true)
+}
+
+// Reverts the most recent change to the alliance selection.
+func (web *Web) undoAllianceSelection() error {
+	if err := web.checkAllianceSelectionInProgress(); err != nil {
+		return err
+	}
+	if !web.arena.AllianceSelection.Undo() {
+		return fmt.Errorf("There is nothing to undo.")
+	}
+	web.arena.AllianceSelection.PickDeadline = time.Time{}
+	return web.saveAllianceSelection()
+}
+
+// Starts the pick countdown over from the beginning, or stops it.
+func (web *Web) setAllianceSelectionTimer(running bool) error {
+	if err := web.checkAllianceSelectionInProgress(); err != nil {
+		return err
+	}
+	if running && web.arena.EventSettings.SelectionPickTimeSec == 0 {
+		return fmt.Errorf("The alliance selection pick time must be set to use the countdown.")
+	}
+	web.arena.AllianceSelection.PickDeadline = time.Time{}
+	if running {
+		web.arena.AllianceSelection.PickDeadline =
+			time.Now().Add(time.Duration(web.arena.EventSettings.SelectionPickTimeSec) * time.Second)
+	}
+	return web.saveAllianceSelection()
+}
+
+// Applies the given changes to the alliance selection, keeping the previous state in the undo history. Restarts the
+// pick countdown for the next alliance to pick if requested and there is one.
+func (web *Web) updateAllianceSelection(snapshot model.AllianceSelectionSnapshot, restartTimer bool) error {
+	web.arena.AllianceSelection.Apply(snapshot)
+	if restartTimer {
+		web.arena.AllianceSelection.PickDeadline = time.Time{}
+		if row, _ := web.determineNextCell(); row != -1 && web.arena.EventSettings.SelectionPickTimeSec > 0 {
+			web.arena.AllianceSelection.PickDeadline =
+				time.Now().Add(time.Duration(web.arena.EventSettings.SelectionPickTimeSec) * time.Second)
+		}
+	}
+	return web.saveAllianceSelection()
+}
+
+// Persists the alliance selection so that it survives a restart and pushes it out to the displays.
+func (web *Web) saveAllianceSelection() error {
+	if err := web.arena.Database.UpdateAllianceSelection(web.arena.AllianceSelection); err != nil {
+		return err
+	}
+	web.arena.AllianceSelectionNotifier.Notify()
+	return nil
+}
+
+// Returns an error if the alliance selection isn't currently underway.
+func (web *Web) checkAllianceSelectionInProgress() error {
+	if !web.canModifyAllianceSelection() {
+		return fmt.Errorf("Alliance selection has already been finalized.")
+	}
+	if len(web.arena.AllianceSelection.Alliances) == 0 {
+		return fmt.Errorf("Alliance selection has not been started.")
+	}
+	return nil
+}
+
+// Marks the given team as picked in the given ranked team list, or returns an error if the tournament rules don't
+// allow it to fill the given kind of spot.
+func pickRankedTeam(rankedTeams []model.RankedTeam, teamId int, isCaptain bool) error {
+	team := findRankedTeam(rankedTeams, teamId)
+	if team == nil {
+		return fmt.Errorf("Team %d has not played any matches at this event and is ineligible for selection.", teamId)
+	}
+	if team.Picked {
+		return fmt.Errorf("Team %d is already part of an alliance.", teamId)
+	}
+	if team.Declined && !isCaptain {
+		// A team that has declined an invitation may still become an alliance captain, but can't be picked.
+		return fmt.Errorf("Team %d declined an invitation and can't be picked by another alliance.", teamId)
+	}
+	team.Picked = true
+	return nil
+}
+
+// Marks the given team as having declined an invitation in the given ranked team list, or returns an error if the
+// tournament rules don't allow it to be invited.
+func declineRankedTeam(rankedTeams []model.RankedTeam, teamId int) error {
+	team := findRankedTeam(rankedTeams, teamId)
+	if team == nil {
+		return fmt.Errorf("Team %d has not played any matches at this event and can't be invited.", teamId)
+	}
+	if team.Declined {
+		return fmt.Errorf("Team %d has already declined an invitation.", teamId)
+	}
+	team.Declined = true
+	return nil
+}
+
+// Returns the entry for the given team in the given ranked team list, or nil if the team isn't in it.
+func findRankedTeam(rankedTeams []model.RankedTeam, teamId int) *model.RankedTeam {
+	for i := range rankedTeams {
+		if rankedTeams[i].TeamId == teamId {
+			return &rankedTeams[i]
+		}
+	}
+	return nil
+}
+
 // Returns the row and column of the next alliance selection spot that should have keyboard autofocus.
 func (web *Web) determineNextCell() (int, int) {
 	// Check the first two columns.
-	for i, alliance := range web.arena.AllianceSelectionAlliances {
+	for i, alliance := range web.arena.AllianceSelection.Alliances {
 		if alliance.TeamIds[0] == 0 {
 			return i, 0
 		}
@@ -347,14 +589,14 @@ func (web *Web) determineNextCell() (int, int) {
 
 	// Check the third column.
 	if web.arena.EventSettings.SelectionRound2Order == "F" {
-		for i, alliance := range web.arena.AllianceSelectionAlliances {
+		for i, alliance := range web.arena.AllianceSelection.Alliances {
 			if alliance.TeamIds[2] == 0 {
 				return i, 2
 			}
 		}
 	} else {
-		for i := len(web.arena.AllianceSelectionAlliances) - 1; i >= 0; i-- {
-			if web.arena.AllianceSelectionAlliances[i].TeamIds[2] == 0 {
+		for i := len(web.arena.AllianceSelection.Alliances) - 1; i >= 0; i-- {
+			if web.arena.AllianceSelection.Alliances[i].TeamIds[2] == 0 {
 				return i, 2
 			}
 		}
@@ -362,14 +604,14 @@ func (web *Web) determineNextCell() (int, int) {
 
 	// Check the fourth column.
 	if web.arena.EventSettings.SelectionRound3Order == "F" {
-		for i, alliance := range web.arena.AllianceSelectionAlliances {
+		for i, alliance := range web.arena.AllianceSelection.Alliances {
 			if alliance.TeamIds[3] == 0 {
 				return i, 3
 			}
 		}
 	} else if web.arena.EventSettings.SelectionRound3Order == "L" {
-		for i := len(web.arena.AllianceSelectionAlliances) - 1; i >= 0; i-- {
-			if web.arena.AllianceSelectionAlliances[i].TeamIds[3] == 0 {
+		for i := len(web.arena.AllianceSelection.Alliances) - 1; i >= 0; i-- {
+			if web.arena.AllianceSelection.Alliances[i].TeamIds[3] == 0 {
 				return i, 3
 			}
 		}
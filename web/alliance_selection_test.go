@@ -6,6 +6,8 @@ package web
 import (
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/FRCTeam1987/crimson-arena/websocket"
+	gorillawebsocket "github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -13,8 +15,6 @@ import (
 func TestAllianceSelection(t *testing.T) {
 	web := setupTestWeb(t)
 
-	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
 	web.arena.EventSettings.NumElimAlliances = 15
 	web.arena.EventSettings.SelectionRound3Order = "L"
 	for i := 1; i <= 10; i++ {
@@ -30,8 +30,8 @@ func TestAllianceSelection(t *testing.T) {
 	// Start the alliance selection.
 	recorder = web.postHttpResponse("/alliance_selection/start", "")
 	assert.Equal(t, 303, recorder.Code)
-	if assert.Equal(t, 15, len(web.arena.AllianceSelectionAlliances)) {
-		assert.Equal(t, 4, len(web.arena.AllianceSelectionAlliances[0].TeamIds))
+	if assert.Equal(t, 15, len(web.arena.AllianceSelection.Alliances)) {
+		assert.Equal(t, 4, len(web.arena.AllianceSelection.Alliances[0].TeamIds))
 	}
 	recorder = web.getHttpResponse("/alliance_selection")
 	assert.Contains(t, recorder.Body.String(), "Captain")
@@ -46,14 +46,14 @@ func TestAllianceSelection(t *testing.T) {
 	web.arena.EventSettings.SelectionRound3Order = ""
 	recorder = web.postHttpResponse("/alliance_selection/start", "")
 	assert.Equal(t, 303, recorder.Code)
-	if assert.Equal(t, 3, len(web.arena.AllianceSelectionAlliances)) {
-		assert.Equal(t, 3, len(web.arena.AllianceSelectionAlliances[0].TeamIds))
+	if assert.Equal(t, 3, len(web.arena.AllianceSelection.Alliances)) {
+		assert.Equal(t, 3, len(web.arena.AllianceSelection.Alliances[0].TeamIds))
 	}
 
 	// Update one team at a time.
 	recorder = web.postHttpResponse("/alliance_selection", "selection0_0=110")
 	assert.Equal(t, 303, recorder.Code)
-	assert.Equal(t, 110, web.arena.AllianceSelectionAlliances[0].TeamIds[0])
+	assert.Equal(t, 110, web.arena.AllianceSelection.Alliances[0].TeamIds[0])
 	recorder = web.getHttpResponse("/alliance_selection")
 	assert.Contains(t, recorder.Body.String(), "\"110\"")
 	assert.NotContains(t, recorder.Body.String(), ">110<")
@@ -61,9 +61,9 @@ func TestAllianceSelection(t *testing.T) {
 	// Update multiple teams at a time.
 	recorder = web.postHttpResponse("/alliance_selection", "selection0_0=101&selection0_1=102&selection1_0=103")
 	assert.Equal(t, 303, recorder.Code)
-	assert.Equal(t, 101, web.arena.AllianceSelectionAlliances[0].TeamIds[0])
-	assert.Equal(t, 102, web.arena.AllianceSelectionAlliances[0].TeamIds[1])
-	assert.Equal(t, 103, web.arena.AllianceSelectionAlliances[1].TeamIds[0])
+	assert.Equal(t, 101, web.arena.AllianceSelection.Alliances[0].TeamIds[0])
+	assert.Equal(t, 102, web.arena.AllianceSelection.Alliances[0].TeamIds[1])
+	assert.Equal(t, 103, web.arena.AllianceSelection.Alliances[1].TeamIds[0])
 	recorder = web.getHttpResponse("/alliance_selection")
 	assert.Contains(t, recorder.Body.String(), ">110<")
 
@@ -98,8 +98,6 @@ func TestAllianceSelection(t *testing.T) {
 func TestAllianceSelectionErrors(t *testing.T) {
 	web := setupTestWeb(t)
 
-	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
 	web.arena.EventSettings.NumElimAlliances = 2
 	for i := 1; i <= 6; i++ {
 		web.arena.Database.CreateRanking(&game.Ranking{TeamId: 100 + i, Rank: i})
@@ -149,18 +147,152 @@ func TestAllianceSelectionErrors(t *testing.T) {
 	recorder = web.postHttpResponse("/alliance_selection", "selection0_0=asdf")
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "already been finalized")
-	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
+	web.arena.AllianceSelection.Alliances = nil
 	recorder = web.postHttpResponse("/alliance_selection/start", "")
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "already been finalized")
 }
 
+func TestAllianceSelectionDeclines(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.EventSettings.NumElimAlliances = 2
+	for i := 1; i <= 8; i++ {
+		web.arena.Database.CreateRanking(&game.Ranking{TeamId: 100 + i, Rank: i})
+	}
+	recorder := web.postHttpResponse("/alliance_selection/start", "")
+	assert.Equal(t, 303, recorder.Code)
+
+	// Record a decline and check that it shows up.
+	recorder = web.postHttpResponse("/alliance_selection", "selection0_0=101&declines0=102")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, []int{102}, web.arena.AllianceSelection.Alliances[0].DeclinedTeamIds)
+	recorder = web.getHttpResponse("/alliance_selection")
+	assert.Contains(t, recorder.Body.String(), "102 (declined)")
+	assert.Contains(t, recorder.Body.String(), `value="102"`)
+
+	// Check the rules around teams that have declined.
+	recorder = web.postHttpResponse("/alliance_selection", "selection0_0=101&declines0=102&selection0_1=102")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 102 declined an invitation and can't be picked by another alliance.")
+	recorder = web.postHttpResponse("/alliance_selection", "selection0_0=101&declines0=102&declines1=102")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 102 has already declined an invitation.")
+	recorder = web.postHttpResponse("/alliance_selection", "selection0_0=101&declines0=254")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 254 has not played any matches at this event and can't be invited.")
+	recorder = web.postHttpResponse("/alliance_selection", "selection0_0=101&declines0=102,asdf")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid team number value 'asdf'.")
+
+	// A team that declined can still become an alliance captain.
+	recorder = web.postHttpResponse("/alliance_selection", "selection0_0=101&selection0_1=103&selection0_2=104&"+
+		"selection1_0=102&selection1_1=105&selection1_2=106&declines0=102, 107&declines1=108")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, []int{102, 107}, web.arena.AllianceSelection.Alliances[0].DeclinedTeamIds)
+	assert.Equal(t, []int{108}, web.arena.AllianceSelection.Alliances[1].DeclinedTeamIds)
+
+	// Check that the declines are saved along with the alliances.
+	recorder = web.postHttpResponse("/alliance_selection/finalize", "startTime=2014-01-01 01:00:00 PM")
+	assert.Equal(t, 303, recorder.Code)
+	alliances, _ := web.arena.Database.GetAllAlliances()
+	if assert.Equal(t, 2, len(alliances)) {
+		assert.Equal(t, []int{102, 107}, alliances[0].DeclinedTeamIds)
+		assert.Equal(t, []int{108}, alliances[1].DeclinedTeamIds)
+	}
+	recorder = web.getHttpResponse("/reports/pdf/alliances")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/pdf", recorder.Header()["Content-Type"][0])
+}
+
+func TestAllianceSelectionWebsocket(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.EventSettings.NumElimAlliances = 2
+	web.arena.EventSettings.SelectionPickTimeSec = 120
+	assert.Nil(t, web.arena.Database.UpdateEventSettings(web.arena.EventSettings))
+	for i := 1; i <= 8; i++ {
+		web.arena.Database.CreateRanking(&game.Ranking{TeamId: 100 + i, Rank: i})
+	}
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/alliance_selection/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	readWebsocketType(t, ws, "allianceSelection")
+
+	ws.Write("pick", map[string]any{"TeamId": 101})
+	assert.Contains(t, readWebsocketError(t, ws), "Alliance selection has not been started.")
+	recorder := web.postHttpResponse("/alliance_selection/start", "")
+	assert.Equal(t, 303, recorder.Code)
+	readWebsocketType(t, ws, "allianceSelection")
+
+	// Seat a captain, have a team decline, and then make a pick.
+	ws.Write("pick", map[string]any{"TeamId": 101})
+	message := readWebsocketType(t, ws, "allianceSelection").(map[string]any)
+	assert.Equal(t, true, message["ShowTimer"])
+	assert.Equal(t, 120.0, message["TimeRemainingSec"])
+	ws.Write("decline", map[string]any{"TeamId": 102})
+	readWebsocketType(t, ws, "allianceSelection")
+	ws.Write("pick", map[string]any{"TeamId": 102})
+	assert.Contains(t, readWebsocketError(t, ws), "Team 102 declined an invitation")
+	ws.Write("pick", map[string]any{"TeamId": 103})
+	readWebsocketType(t, ws, "allianceSelection")
+	assert.Equal(t, []int{101, 103, 0}, web.arena.AllianceSelection.Alliances[0].TeamIds)
+	assert.Equal(t, []int{102}, web.arena.AllianceSelection.Alliances[0].DeclinedTeamIds)
+
+	// A team that declined can still be seated as a captain, but a captain can't then be invited by a lower alliance.
+	ws.Write("decline", map[string]any{"TeamId": 104})
+	assert.Contains(t, readWebsocketError(t, ws), "No alliance is currently making a pick.")
+	ws.Write("pick", map[string]any{"TeamId": 102})
+	readWebsocketType(t, ws, "allianceSelection")
+	ws.Write("decline", map[string]any{"TeamId": 101})
+	assert.Contains(t, readWebsocketError(t, ws), "Team 101 is already part of an alliance.")
+
+	// Undo the last pick and check that the state is saved across a restart.
+	ws.Write("undo", nil)
+	message = readWebsocketType(t, ws, "allianceSelection").(map[string]any)
+	assert.Equal(t, false, message["ShowTimer"])
+	assert.Equal(t, []int{0, 0, 0}, web.arena.AllianceSelection.Alliances[1].TeamIds)
+	ws.Write("pick", map[string]any{"TeamId": 104})
+	readWebsocketType(t, ws, "allianceSelection")
+	assert.Nil(t, web.arena.LoadSettings())
+	readWebsocketType(t, ws, "allianceSelection")
+	assert.Equal(t, []int{104, 0, 0}, web.arena.AllianceSelection.Alliances[1].TeamIds)
+	assert.Equal(t, 4, len(web.arena.AllianceSelection.UndoHistory))
+	recorder = web.getHttpResponse("/alliance_selection")
+	assert.Contains(t, recorder.Body.String(), `value="104"`)
+	assert.Contains(t, recorder.Body.String(), "102 (declined)")
+
+	// Control the pick countdown manually.
+	ws.Write("stopTimer", nil)
+	message = readWebsocketType(t, ws, "allianceSelection").(map[string]any)
+	assert.Equal(t, false, message["ShowTimer"])
+	assert.True(t, web.arena.AllianceSelection.PickDeadline.IsZero())
+	ws.Write("startTimer", nil)
+	message = readWebsocketType(t, ws, "allianceSelection").(map[string]any)
+	assert.Equal(t, true, message["ShowTimer"])
+	web.arena.EventSettings.SelectionPickTimeSec = 0
+	ws.Write("startTimer", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "pick time must be set")
+
+	// Undo everything back to the start of the selection.
+	for i := 0; i < 4; i++ {
+		ws.Write("undo", nil)
+		readWebsocketType(t, ws, "allianceSelection")
+	}
+	ws.Write("undo", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "There is nothing to undo.")
+	assert.Equal(t, []int{0, 0, 0}, web.arena.AllianceSelection.Alliances[0].TeamIds)
+	ws.Write("invalid", nil)
+	assert.Contains(t, readWebsocketError(t, ws), "Invalid message type")
+}
+
 func TestAllianceSelectionReset(t *testing.T) {
 	web := setupTestWeb(t)
 
-	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
 	web.arena.EventSettings.NumElimAlliances = 2
 	for i := 1; i <= 6; i++ {
 		web.arena.Database.CreateRanking(&game.Ranking{TeamId: 100 + i, Rank: i})
@@ -215,8 +347,6 @@ func TestAllianceSelectionReset(t *testing.T) {
 func TestAllianceSelectionAutofocus(t *testing.T) {
 	web := setupTestWeb(t)
 
-	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
 	web.arena.EventSettings.NumElimAlliances = 2
 
 	// Straight draft.
@@ -227,35 +357,35 @@ func TestAllianceSelectionAutofocus(t *testing.T) {
 	i, j := web.determineNextCell()
 	assert.Equal(t, 0, i)
 	assert.Equal(t, 0, j)
-	web.arena.AllianceSelectionAlliances[0].TeamIds[0] = 1
+	web.arena.AllianceSelection.Alliances[0].TeamIds[0] = 1
 	i, j = web.determineNextCell()
 	assert.Equal(t, 0, i)
 	assert.Equal(t, 1, j)
-	web.arena.AllianceSelectionAlliances[0].TeamIds[1] = 2
+	web.arena.AllianceSelection.Alliances[0].TeamIds[1] = 2
 	i, j = web.determineNextCell()
 	assert.Equal(t, 1, i)
 	assert.Equal(t, 0, j)
-	web.arena.AllianceSelectionAlliances[1].TeamIds[0] = 3
+	web.arena.AllianceSelection.Alliances[1].TeamIds[0] = 3
 	i, j = web.determineNextCell()
 	assert.Equal(t, 1, i)
 	assert.Equal(t, 1, j)
-	web.arena.AllianceSelectionAlliances[1].TeamIds[1] = 4
+	web.arena.AllianceSelection.Alliances[1].TeamIds[1] = 4
 	i, j = web.determineNextCell()
 	assert.Equal(t, 0, i)
 	assert.Equal(t, 2, j)
-	web.arena.AllianceSelectionAlliances[0].TeamIds[2] = 5
+	web.arena.AllianceSelection.Alliances[0].TeamIds[2] = 5
 	i, j = web.determineNextCell()
 	assert.Equal(t, 1, i)
 	assert.Equal(t, 2, j)
-	web.arena.AllianceSelectionAlliances[1].TeamIds[2] = 6
+	web.arena.AllianceSelection.Alliances[1].TeamIds[2] = 6
 	i, j = web.determineNextCell()
 	assert.Equal(t, 0, i)
 	assert.Equal(t, 3, j)
-	web.arena.AllianceSelectionAlliances[0].TeamIds[3] = 7
+	web.arena.AllianceSelection.Alliances[0].TeamIds[3] = 7
 	i, j = web.determineNextCell()
 	assert.Equal(t, 1, i)
 	assert.Equal(t, 3, j)
-	web.arena.AllianceSelectionAlliances[1].TeamIds[3] = 8
+	web.arena.AllianceSelection.Alliances[1].TeamIds[3] = 8
 	i, j = web.determineNextCell()
 	assert.Equal(t, -1, i)
 	assert.Equal(t, -1, j)
@@ -270,35 +400,35 @@ func TestAllianceSelectionAutofocus(t *testing.T) {
 	i, j = web.determineNextCell()
 	assert.Equal(t, 0, i)
 	assert.Equal(t, 0, j)
-	web.arena.AllianceSelectionAlliances[0].TeamIds[0] = 1
+	web.arena.AllianceSelection.Alliances[0].TeamIds[0] = 1
 	i, j = web.determineNextCell()
 	assert.Equal(t, 0, i)
 	assert.Equal(t, 1, j)
-	web.arena.AllianceSelectionAlliances[0].TeamIds[1] = 2
+	web.arena.AllianceSelection.Alliances[0].TeamIds[1] = 2
 	i, j = web.determineNextCell()
 	assert.Equal(t, 1, i)
 	assert.Equal(t, 0, j)
-	web.arena.AllianceSelectionAlliances[1].TeamIds[0] = 3
+	web.arena.AllianceSelection.Alliances[1].TeamIds[0] = 3
 	i, j = web.determineNextCell()
 	assert.Equal(t, 1, i)
 	assert.Equal(t, 1, j)
-	web.arena.AllianceSelectionAlliances[1].TeamIds[1] = 4
+	web.arena.AllianceSelection.Alliances[1].TeamIds[1] = 4
 	i, j = web.determineNextCell()
 	assert.Equal(t, 1, i)
 	assert.Equal(t, 2, j)
-	web.arena.AllianceSelectionAlliances[1].TeamIds[2] = 5
+	web.arena.AllianceSelection.Alliances[1].TeamIds[2] = 5
 	i, j = web.determineNextCell()
 	assert.Equal(t, 0, i)
 	assert.Equal(t, 2, j)
-	web.arena.AllianceSelectionAlliances[0].TeamIds[2] = 6
+	web.arena.AllianceSelection.Alliances[0].TeamIds[2] = 6
 	i, j = web.determineNextCell()
 	assert.Equal(t, 1, i)
 	assert.Equal(t, 3, j)
-	web.arena.AllianceSelectionAlliances[1].TeamIds[3] = 7
+	web.arena.AllianceSelection.Alliances[1].TeamIds[3] = 7
 	i, j = web.determineNextCell()
 	assert.Equal(t, 0, i)
 	assert.Equal(t, 3, j)
-	web.arena.AllianceSelectionAlliances[0].TeamIds[3] = 8
+	web.arena.AllianceSelection.Alliances[0].TeamIds[3] = 8
 	i, j = web.determineNextCell()
 	assert.Equal(t, -1, i)
 	assert.Equal(t, -1, j)
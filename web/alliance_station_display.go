@@ -52,5 +52,5 @@ func (web *Web) allianceStationDisplayWebsocketHandler(w http.ResponseWriter, r
 	// Subscribe the websocket to the notifiers whose messages will be passed on to the client.
 	ws.HandleNotifiers(display.Notifier, web.arena.MatchTimingNotifier, web.arena.AllianceStationDisplayModeNotifier,
 		web.arena.ArenaStatusNotifier, web.arena.MatchLoadNotifier, web.arena.MatchTimeNotifier,
-		web.arena.RealtimeScoreNotifier, web.arena.ReloadDisplaysNotifier)
+		web.arena.RealtimeScoreNotifier, web.arena.AllianceRestNotifier, web.arena.ReloadDisplaysNotifier)
 }
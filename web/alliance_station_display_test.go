@@ -43,6 +43,7 @@ func TestAllianceStationDisplayWebsocket(t *testing.T) {
 	readWebsocketType(t, ws, "matchLoad")
 	readWebsocketType(t, ws, "matchTime")
 	readWebsocketType(t, ws, "realtimeScore")
+	readWebsocketType(t, ws, "allianceRest")
 
 	// Change to a different screen.
 	web.arena.AllianceStationDisplayMode = "logo"
@@ -60,8 +61,10 @@ func TestAllianceStationDisplayWebsocket(t *testing.T) {
 	web.arena.AllianceStations["B3"].Bypass = true
 	web.arena.StartMatch()
 	web.arena.Update()
-	messages := readWebsocketMultiple(t, ws, 3)
-	_, ok := messages["matchTime"]
+	messages := readWebsocketMultiple(t, ws, 4)
+	_, ok := messages["allianceRest"]
+	assert.True(t, ok)
+	_, ok = messages["matchTime"]
 	assert.True(t, ok)
 	web.arena.MatchStartTime = time.Now().Add(-time.Duration(game.MatchTiming.WarmupDurationSec) * time.Second)
 	web.arena.Update()
@@ -52,5 +52,6 @@ func (web *Web) announcerDisplayWebsocketHandler(w http.ResponseWriter, r *http.
 	// Subscribe the websocket to the notifiers whose messages will be passed on to the client.
 	ws.HandleNotifiers(display.Notifier, web.arena.MatchTimingNotifier, web.arena.MatchLoadNotifier,
 		web.arena.MatchTimeNotifier, web.arena.RealtimeScoreNotifier, web.arena.ScorePostedNotifier,
-		web.arena.AudienceDisplayModeNotifier, web.arena.ReloadDisplaysNotifier)
+		web.arena.AudienceDisplayModeNotifier, web.arena.EventStatusNotifier, web.arena.ScheduleUpdateNotifier,
+		web.arena.AllianceRestNotifier, web.arena.ReloadDisplaysNotifier)
 }
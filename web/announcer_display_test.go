@@ -36,6 +36,8 @@ func TestAnnouncerDisplayWebsocket(t *testing.T) {
 	readWebsocketType(t, ws, "realtimeScore")
 	readWebsocketType(t, ws, "scorePosted")
 	readWebsocketType(t, ws, "audienceDisplayMode")
+	readWebsocketType(t, ws, "eventStatus")
+	readWebsocketType(t, ws, "allianceRest")
 
 	web.arena.MatchLoadNotifier.Notify()
 	readWebsocketType(t, ws, "matchLoad")
@@ -47,11 +49,15 @@ func TestAnnouncerDisplayWebsocket(t *testing.T) {
 	web.arena.AllianceStations["B3"].Bypass = true
 	web.arena.StartMatch()
 	web.arena.Update()
-	messages := readWebsocketMultiple(t, ws, 2)
-	_, ok := messages["audienceDisplayMode"]
+	messages := readWebsocketMultiple(t, ws, 4)
+	_, ok := messages["allianceRest"]
+	assert.True(t, ok)
+	_, ok = messages["audienceDisplayMode"]
 	assert.True(t, ok)
 	_, ok = messages["matchTime"]
 	assert.True(t, ok)
+	_, ok = messages["eventStatus"]
+	assert.True(t, ok)
 	web.arena.RealtimeScoreNotifier.Notify()
 	readWebsocketType(t, ws, "realtimeScore")
 	web.arena.ScorePostedNotifier.Notify()
@@ -8,12 +8,14 @@ package web
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/bracket"
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/FRCTeam1987/crimson-arena/partner"
 	"github.com/FRCTeam1987/crimson-arena/websocket"
 	"github.com/gorilla/mux"
 	"io"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
@@ -48,8 +50,50 @@ type allianceMatchup struct {
 	SeriesLeader       string
 	SeriesStatus       string
 	IsComplete         bool
+	Transform          string
 }
 
+// Positions of the elements of a bracket that is laid out automatically because its format has no hand-drawn layout.
+type genericBracketLayout struct {
+	Transform          string
+	Connectors         []genericBracketConnector
+	Labels             []genericBracketLabel
+	StandingsTransform string
+	Standings          []genericBracketStanding
+}
+
+type genericBracketConnector struct {
+	Points  string
+	IsLoser bool
+}
+
+type genericBracketLabel struct {
+	X    float64
+	Text string
+}
+
+type genericBracketStanding struct {
+	Y          int
+	Rank       int
+	AllianceId int
+	Value      string
+}
+
+// Dimensions in pixels used when laying out a bracket automatically.
+const (
+	genericBracketColumnWidth = 300
+	genericBracketRowHeight   = 190
+	genericBracketBlockWidth  = 205
+	genericBracketMaxWidth    = 1700
+	genericBracketMaxHeight   = 780
+	genericBracketTop         = 130
+	// Rounds with more matchups than this, such as a round robin, wrap onto additional columns.
+	genericBracketMaxRows = 4
+	// Vertical position of the first row of the round robin standings and the spacing between rows.
+	genericBracketStandingsTop       = 55
+	genericBracketStandingsRowHeight = 36
+)
+
 // Generates a JSON dump of the matches and results.
 func (web *Web) matchesApiHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -221,7 +265,7 @@ func (web *Web) alliancesApiHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonData, err := json.MarshalIndent(alliances, "", "  ")
+	jsonData, err := json.MarshalIndent(model.WithoutLineupPins(alliances), "", "  ")
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -324,9 +368,13 @@ func (web *Web) generateBracketSvg(w io.Writer, activeMatch *model.Match, showTe
 		}
 	}
 
-	bracketType := "double"
-	numAlliances := web.arena.EventSettings.NumElimAlliances
-	if web.arena.EventSettings.ElimType == "single" {
+	bracketType := ""
+	var layout *genericBracketLayout
+	if web.arena.PlayoffBracket != nil {
+		bracketType = web.arena.PlayoffBracket.Layout
+	}
+	if bracketType == "single" {
+		numAlliances := web.arena.EventSettings.NumElimAlliances
 		if numAlliances > 8 {
 			bracketType = "16"
 		} else if numAlliances > 4 {
@@ -336,16 +384,154 @@ func (web *Web) generateBracketSvg(w io.Writer, activeMatch *model.Match, showTe
 		} else {
 			bracketType = "2"
 		}
+	} else if bracketType == "" || bracketType == "double" && web.arena.EventSettings.NumElimAlliances < 8 {
+		// The hand-drawn double-elimination layout has a place for every matchup, so smaller brackets with byes are
+		// laid out automatically instead.
+		bracketType = "generic"
+		layout = layOutGenericBracket(web.arena.PlayoffBracket, matchups)
 	}
 
 	template, err := web.parseFiles("templates/bracket.svg")
 	if err != nil {
 		return err
 	}
+	finalsBestOf := 0
+	if web.arena.PlayoffBracket != nil && web.arena.PlayoffBracket.FinalsMatchup != nil {
+		finalsBestOf = web.arena.PlayoffBracket.FinalsMatchup.BestOf()
+	}
 	data := struct {
 		BracketType             string
 		Matchups                map[string]*allianceMatchup
 		ShowTemporaryConnectors bool
-	}{bracketType, matchups, showTemporaryConnectors}
+		Layout                  *genericBracketLayout
+		FinalsBestOf            int
+	}{bracketType, matchups, showTemporaryConnectors, layout, finalsBestOf}
 	return template.ExecuteTemplate(w, "bracket", data)
 }
+
+// Arranges the matchups of a bracket that has no hand-drawn layout into one column per round (wrapping rounds that
+// are too tall onto additional columns), scaled to fit, and returns the connectors, labels and any round robin
+// standings to draw alongside them. Sets the position of each matchup in the given map.
+func layOutGenericBracket(playoffBracket *bracket.Bracket, matchups map[string]*allianceMatchup) *genericBracketLayout {
+	if playoffBracket == nil {
+		return &genericBracketLayout{}
+	}
+	var rounds [][]*bracket.Matchup
+	for _, matchup := range playoffBracket.GetAllMatchups() {
+		if len(rounds) == 0 || rounds[len(rounds)-1][0].Round != matchup.Round {
+			rounds = append(rounds, nil)
+		}
+		rounds[len(rounds)-1] = append(rounds[len(rounds)-1], matchup)
+	}
+	if len(rounds) == 0 {
+		return &genericBracketLayout{}
+	}
+
+	// Split each round into columns and center each column's matchups vertically within the tallest one.
+	type roundColumns struct {
+		firstColumn int
+		columns     [][]*bracket.Matchup
+	}
+	var roundsColumns []roundColumns
+	numColumns := 0
+	maxRows := 0
+	for _, round := range rounds {
+		layoutRound := roundColumns{firstColumn: numColumns}
+		for i := 0; i < len(round); i += genericBracketMaxRows {
+			column := round[i:min(i+genericBracketMaxRows, len(round))]
+			layoutRound.columns = append(layoutRound.columns, column)
+			maxRows = max(maxRows, len(column))
+		}
+		roundsColumns = append(roundsColumns, layoutRound)
+		numColumns += len(layoutRound.columns)
+	}
+	type position struct{ x, y float64 }
+	positions := make(map[*bracket.Matchup]position)
+	for _, round := range roundsColumns {
+		for i, column := range round.columns {
+			for j, matchup := range column {
+				matchupPosition := position{
+					x: float64((round.firstColumn + i) * genericBracketColumnWidth),
+					y: float64((maxRows-len(column))*genericBracketRowHeight)/2 + float64(j*genericBracketRowHeight),
+				}
+				positions[matchup] = matchupPosition
+				matchups[fmt.Sprintf("%d_%d", matchup.Round, matchup.Group)].Transform =
+					fmt.Sprintf("translate(%.1f %.1f)", matchupPosition.x, matchupPosition.y)
+			}
+		}
+	}
+
+	var standings []genericBracketStanding
+	if roundRobin := playoffBracket.RoundRobin; roundRobin != nil {
+		// Show the round robin standings in an extra column to the right of the bracket.
+		for i, ranking := range roundRobin.Rankings {
+			value := fmt.Sprintf("%.1f", ranking.AverageScore())
+			if roundRobin.RankBy == bracket.RoundRobinRankByRankingPoints {
+				value = fmt.Sprintf("%.2f RP", ranking.AverageRankingPoints())
+			}
+			standings = append(standings, genericBracketStanding{
+				Y:          genericBracketStandingsTop + i*genericBracketStandingsRowHeight,
+				Rank:       ranking.Rank,
+				AllianceId: ranking.AllianceId,
+				Value:      value,
+			})
+		}
+		numColumns++
+	}
+
+	width := float64((numColumns-1)*genericBracketColumnWidth + genericBracketBlockWidth)
+	height := float64(max(maxRows*genericBracketRowHeight,
+		genericBracketStandingsTop+len(standings)*genericBracketStandingsRowHeight))
+	scale := math.Min(1, math.Min(genericBracketMaxWidth/width, genericBracketMaxHeight/height))
+	offsetX := 960 - width*scale/2
+	offsetY := genericBracketTop + (genericBracketMaxHeight-height*scale)/2
+	layout := genericBracketLayout{Transform: fmt.Sprintf("translate(%.1f %.1f) scale(%.3f)", offsetX, offsetY, scale)}
+	if standings != nil {
+		layout.StandingsTransform = fmt.Sprintf("translate(%d 0)", (numColumns-1)*genericBracketColumnWidth)
+		layout.Standings = standings
+	}
+
+	// Connect the middle of each source matchup to the alliance slot that it feeds.
+	for _, round := range rounds {
+		for _, matchup := range round {
+			redSource, redUseWinner := matchup.RedAllianceSourceMatchup()
+			blueSource, blueUseWinner := matchup.BlueAllianceSourceMatchup()
+			for _, source := range []struct {
+				matchup   *bracket.Matchup
+				useWinner bool
+				offsetY   float64
+			}{{redSource, redUseWinner, 56}, {blueSource, blueUseWinner, 121}} {
+				if source.matchup == nil {
+					continue
+				}
+				start := positions[source.matchup]
+				end := positions[matchup]
+				startX := start.x + genericBracketBlockWidth
+				startY := start.y + 88
+				endY := end.y + source.offsetY
+				elbowX := end.x - (genericBracketColumnWidth-genericBracketBlockWidth)/2
+				layout.Connectors = append(layout.Connectors, genericBracketConnector{
+					Points: fmt.Sprintf(
+						"%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f",
+						startX, startY, elbowX, startY, elbowX, endY, end.x, endY,
+					),
+					IsLoser: !source.useWinner,
+				})
+			}
+		}
+	}
+
+	// Label each round at the center of its columns.
+	for i, round := range roundsColumns {
+		text := fmt.Sprintf("Round %d", i+1)
+		if round.columns[0][0].IsRoundRobin() {
+			text = "Round Robin"
+		} else if i == len(roundsColumns)-1 && len(rounds[i]) == 1 && rounds[i][0] == playoffBracket.FinalsMatchup {
+			text = "Finals"
+		}
+		centerX := float64(round.firstColumn*genericBracketColumnWidth) +
+			float64((len(round.columns)-1)*genericBracketColumnWidth+genericBracketBlockWidth)/2
+		layout.Labels = append(layout.Labels, genericBracketLabel{X: math.Round(offsetX + centerX*scale), Text: text})
+	}
+	return &layout
+}
@@ -5,12 +5,14 @@ package web
 
 import (
 	"encoding/json"
+	"github.com/FRCTeam1987/crimson-arena/bracket"
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/FRCTeam1987/crimson-arena/tournament"
 	"github.com/FRCTeam1987/crimson-arena/websocket"
 	gorillawebsocket "github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 	"time"
 )
@@ -106,10 +108,14 @@ func TestAlliancesApi(t *testing.T) {
 	web := setupTestWeb(t)
 
 	model.BuildTestAlliances(web.arena.Database)
+	alliance, _ := web.arena.Database.GetAllianceById(1)
+	alliance.LineupPin = "12345678"
+	web.arena.Database.UpdateAlliance(alliance)
 
 	recorder := web.getHttpResponse("/api/alliances")
 	assert.Equal(t, 200, recorder.Code)
 	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	assert.NotContains(t, recorder.Body.String(), "12345678")
 	var alliances []model.Alliance
 	err := json.Unmarshal([]byte(recorder.Body.String()), &alliances)
 	assert.Nil(t, err)
@@ -155,4 +161,91 @@ func TestBracketSvgApiDoubleElimination(t *testing.T) {
 	assert.Equal(t, 200, recorder.Code)
 	assert.Equal(t, "image/svg+xml", recorder.Header()["Content-Type"][0])
 	assert.Contains(t, recorder.Body.String(), "Best-of-3")
+
+	// The finals subtitle follows the configured length of the series.
+	web.arena.EventSettings.ElimRoundBestOf = map[int]int{6: 5}
+	assert.Nil(t, web.arena.CreatePlayoffBracket())
+	recorder = web.getHttpResponse("/api/bracket/svg")
+	assert.Contains(t, recorder.Body.String(), "Best-of-5")
+	web.arena.EventSettings.ElimRoundBestOf = nil
+
+	// Brackets with byes use the automatic layout since some of the drawn matchups aren't played.
+	web.arena.EventSettings.NumElimAlliances = 6
+	tournament.CreateTestAlliances(web.arena.Database, 6)
+	assert.Nil(t, web.arena.CreatePlayoffBracket())
+	recorder = web.getHttpResponse("/api/bracket/svg")
+	assert.Equal(t, 200, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, `class="bracket_generic"`)
+	assert.Contains(t, body, `id="match_2_3"`)
+	assert.NotContains(t, body, `id="match_1_1"`)
+	assert.NotContains(t, body, `id="match_2_1"`)
+	assert.Contains(t, body, ">Round 5</text>")
+	assert.Contains(t, body, ">Finals</text>")
+}
+
+func TestBracketSvgApiGenericLayout(t *testing.T) {
+	web := setupTestWeb(t)
+	definition, err := bracket.ParseBracketDefinition("mini", []byte(`{
+		"name": "Mini Double-Elimination", "minAlliances": 4, "maxAlliances": 4, "matchups": [
+			{"round": 1, "group": 1, "displayName": "1", "numWinsToAdvance": 1,
+			 "redAllianceSource": {"allianceId": 1}, "blueAllianceSource": {"allianceId": 4}},
+			{"round": 1, "group": 2, "displayName": "2", "numWinsToAdvance": 1,
+			 "redAllianceSource": {"allianceId": 2}, "blueAllianceSource": {"allianceId": 3}},
+			{"round": 2, "group": 1, "displayName": "3", "numWinsToAdvance": 1,
+			 "redAllianceSource": {"winnerOf": {"round": 1, "group": 1}},
+			 "blueAllianceSource": {"winnerOf": {"round": 1, "group": 2}}},
+			{"round": 2, "group": 2, "displayName": "4", "numWinsToAdvance": 1,
+			 "redAllianceSource": {"loserOf": {"round": 1, "group": 1}},
+			 "blueAllianceSource": {"loserOf": {"round": 1, "group": 2}}},
+			{"round": 3, "group": 1, "displayName": "5", "numWinsToAdvance": 1,
+			 "redAllianceSource": {"loserOf": {"round": 2, "group": 1}},
+			 "blueAllianceSource": {"winnerOf": {"round": 2, "group": 2}}},
+			{"round": 4, "group": 1, "displayName": "F", "numWinsToAdvance": 2,
+			 "redAllianceSource": {"winnerOf": {"round": 2, "group": 1}},
+			 "blueAllianceSource": {"winnerOf": {"round": 3, "group": 1}}}
+		]
+	}`))
+	assert.Nil(t, err)
+	web.arena.PlayoffBracket, err = definition.NewBracket(4)
+	assert.Nil(t, err)
+	tournament.CreateTestAlliances(web.arena.Database, 4)
+
+	recorder := web.getHttpResponse("/api/bracket/svg")
+	assert.Equal(t, 200, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, `class="bracket_generic"`)
+	assert.Contains(t, body, `id="match_1_1" transform="translate(0.0 0.0)"`)
+	assert.Contains(t, body, `id="match_2_2" transform="translate(300.0 190.0)"`)
+	assert.Contains(t, body, `id="match_4_1" transform="translate(900.0 95.0)"`)
+	assert.Equal(t, 8, strings.Count(body, "<polyline"))
+	assert.Equal(t, 3, strings.Count(body, `<polyline class="loser"`))
+	assert.Contains(t, body, ">Round 3</text>")
+	assert.Contains(t, body, ">Finals</text>")
+	assert.NotContains(t, body, ">Round 4</text>")
+}
+
+func TestBracketSvgApiRoundRobin(t *testing.T) {
+	web := setupTestWeb(t)
+	var err error
+	web.arena.PlayoffBracket, err = bracket.NewBracketFromDefinition("round_robin", 8)
+	assert.Nil(t, err)
+	tournament.CreateTestAlliances(web.arena.Database, 8)
+	assert.Nil(t, web.arena.PlayoffBracket.Update(web.arena.Database, nil))
+
+	// The twelve round-robin matches wrap onto three columns, followed by the finals and the standings.
+	recorder := web.getHttpResponse("/api/bracket/svg")
+	assert.Equal(t, 200, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, `class="bracket_generic"`)
+	assert.Contains(t, body, `id="match_1_4" transform="translate(0.0 570.0)"`)
+	assert.Contains(t, body, `id="match_1_5" transform="translate(300.0 0.0)"`)
+	assert.Contains(t, body, `id="match_2_1" transform="translate(900.0 285.0)"`)
+	assert.Contains(t, body, `<g id="standings" transform="translate(1200 0)">`)
+	assert.Contains(t, body, ">1. Alliance 1</text>")
+	assert.Contains(t, body, ">8. Alliance 8</text>")
+	assert.Contains(t, body, ">RR #2</text>")
+	assert.Contains(t, body, ">Round Robin</text>")
+	assert.Contains(t, body, ">Finals</text>")
+	assert.NotContains(t, body, "<polyline")
 }
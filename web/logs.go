@@ -0,0 +1,162 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+//
+// Web routes for browsing and charting the driver station logs recorded during each match.
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/FRCTeam1987/crimson-arena/field"
+	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Logs for the same match that were started within this long of each other are grouped together.
+const logGroupingWindow = 10 * time.Second
+
+type LogListMatch struct {
+	StartedAt time.Time
+	MatchType string
+	MatchName string
+	Teams     []LogListTeam
+}
+
+type LogListTeam struct {
+	TeamId int
+	Path   string
+}
+
+// Match period boundaries, in seconds of match time as recorded in the logs.
+type logPeriods struct {
+	AutoStartSec   float64
+	AutoEndSec     float64
+	TeleopStartSec float64
+	TeleopEndSec   float64
+}
+
+// Shows the list of driver station logs, grouped by match.
+func (web *Web) logsGetHandler(w http.ResponseWriter, r *http.Request) {
+	logs, err := field.ListTeamMatchLogs()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teamId, _ := strconv.Atoi(r.URL.Query().Get("teamId"))
+
+	var matches []LogListMatch
+	for _, info := range logs {
+		if teamId > 0 && info.TeamId != teamId {
+			continue
+		}
+		team := LogListTeam{info.TeamId, url.PathEscape(info.Filename)}
+		if match := findLogListMatch(matches, info); match != nil {
+			match.Teams = append(match.Teams, team)
+			continue
+		}
+		matches = append(matches, LogListMatch{
+			StartedAt: info.StartedAt,
+			MatchType: info.MatchType,
+			MatchName: info.MatchName,
+			Teams:     []LogListTeam{team},
+		})
+	}
+
+	template, err := web.parseFiles("templates/logs.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Matches []LogListMatch
+		TeamId  int
+	}{web.arena.EventSettings, matches, teamId}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Returns the already-listed match that the given log belongs to, or nil if there isn't one.
+func findLogListMatch(matches []LogListMatch, info field.TeamMatchLogInfo) *LogListMatch {
+	for i := len(matches) - 1; i >= 0; i-- {
+		if matches[i].StartedAt.Sub(info.StartedAt) > logGroupingWindow {
+			break
+		}
+		if matches[i].MatchType == info.MatchType && matches[i].MatchName == info.MatchName {
+			return &matches[i]
+		}
+	}
+	return nil
+}
+
+// Shows the charts for a single driver station log.
+func (web *Web) logGetHandler(w http.ResponseWriter, r *http.Request) {
+	info, err := field.GetTeamMatchLogInfo(mux.Vars(r)["filename"])
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	template, err := web.parseFiles("templates/log.html", "templates/base.html")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	data := struct {
+		*model.EventSettings
+		Log  *field.TeamMatchLogInfo
+		Path string
+	}{web.arena.EventSettings, info, url.PathEscape(info.Filename)}
+	err = template.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
+
+// Generates a JSON dump of the given driver station log and the match period boundaries for charting it.
+func (web *Web) logApiHandler(w http.ResponseWriter, r *http.Request) {
+	info, err := field.GetTeamMatchLogInfo(mux.Vars(r)["filename"])
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	rows, err := field.ReadTeamMatchLog(info.Filename)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	data := struct {
+		*field.TeamMatchLogInfo
+		Periods logPeriods
+		Rows    []field.TeamMatchLogRow
+	}{
+		info,
+		logPeriods{
+			AutoStartSec:   float64(game.MatchTiming.WarmupDurationSec),
+			AutoEndSec:     game.GetDurationToAutoEnd().Seconds(),
+			TeleopStartSec: game.GetDurationToTeleopStart().Seconds(),
+			TeleopEndSec:   game.GetDurationToTeleopEnd().Seconds(),
+		},
+		rows,
+	}
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(jsonData)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+}
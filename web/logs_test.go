@@ -0,0 +1,107 @@
+// Copyright 2026 Team 1987. All Rights Reserved.
+
+package web
+
+import (
+	"encoding/json"
+	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogs(t *testing.T) {
+	web := setupTestWeb(t)
+
+	logsPath := filepath.Join(model.BaseDir, "static/logs")
+	assert.Nil(t, os.MkdirAll(logsPath, 0755))
+	header := "matchTimeSec,packetType,teamId,allianceStation,dsLinked,radioLinked,rioLinked,robotLinked,auto," +
+		"enabled,emergencyStop,batteryVoltage,missedPacketCount,dsRobotTripTimeMs,rxRate,txRate,signalNoiseRatio," +
+		"brownout,canUtilization,cpuUsage,ramUsage,diskUsage,pdpTotalCurrent,radioSignalDb,radioBandwidthMbps,message\n"
+	logs := map[string]string{
+		"20200102030405_Qualification_Match_Q7_9881.csv": header +
+			"4.000000,22,9881,R1,true,true,true,true,true,true,false,12.500000,0,6,1.000000,2.000000,40,false," +
+			"10,20,30,40,5.000000,-50,3.000000,\n" +
+			"5.000000,23,9881,R1,true,true,true,false,true,true,false,12.400000,2,6,1.000000,2.000000,40,false," +
+			"10,20,30,40,5.000000,-50,3.000000,\"Lost robot, comms\"\n",
+		"20200102030406_Qualification_Match_Q7_9882.csv":  header,
+		"20200102030406__Match_Test Match_9883.csv":       header,
+		"20200102030406_Qualification_Match_Q7_notes.csv": header,
+	}
+	for filename, contents := range logs {
+		path := filepath.Join(logsPath, filename)
+		assert.Nil(t, os.WriteFile(path, []byte(contents), 0644))
+		defer os.Remove(path)
+	}
+
+	// Check that the logs for the same match are grouped together and that other files are ignored.
+	recorder := web.getHttpResponse("/logs")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Match Logs")
+	assert.Equal(t, 1, strings.Count(recorder.Body.String(), "<td>Q7</td>"))
+	assert.Contains(t, recorder.Body.String(), "/logs/20200102030405_Qualification_Match_Q7_9881.csv")
+	assert.Contains(t, recorder.Body.String(), "/logs/20200102030406_Qualification_Match_Q7_9882.csv")
+	assert.Contains(t, recorder.Body.String(), "/logs/20200102030406__Match_Test%20Match_9883.csv")
+	assert.NotContains(t, recorder.Body.String(), "notes")
+
+	recorder = web.getHttpResponse("/logs?teamId=9882")
+	assert.Equal(t, 200, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "9881.csv")
+	assert.Contains(t, recorder.Body.String(), "9882.csv")
+
+	recorder = web.getHttpResponse("/logs/20200102030405_Qualification_Match_Q7_9881.csv")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 9881 Log - Qualification Q7")
+	recorder = web.getHttpResponse("/logs/20200102030406__Match_Test%20Match_9883.csv")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 9883 Log - Test Test Match")
+	recorder = web.getHttpResponse("/logs/bogus.csv")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "invalid log filename")
+}
+
+func TestLogApi(t *testing.T) {
+	web := setupTestWeb(t)
+
+	logsPath := filepath.Join(model.BaseDir, "static/logs")
+	assert.Nil(t, os.MkdirAll(logsPath, 0755))
+	filename := "20200102030405_Playoff_Match_F1_9884.csv"
+	path := filepath.Join(logsPath, filename)
+	contents := "matchTimeSec,packetType,teamId,allianceStation,dsLinked,radioLinked,rioLinked,robotLinked,auto," +
+		"enabled,emergencyStop,batteryVoltage,missedPacketCount,dsRobotTripTimeMs,rxRate,txRate,signalNoiseRatio\n" +
+		"4.000000,22,9884,B3,true,true,true,false,true,true,false,12.500000,3,6,1.000000,2.000000,40\n"
+	assert.Nil(t, os.WriteFile(path, []byte(contents), 0644))
+	defer os.Remove(path)
+
+	recorder := web.getHttpResponse("/api/logs/" + filename)
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header()["Content-Type"][0])
+	var data struct {
+		TeamId    int
+		MatchType string
+		MatchName string
+		Periods   logPeriods
+		Rows      []struct {
+			MatchTimeSec      float64
+			RobotLinked       bool
+			BatteryVoltage    float64
+			MissedPacketCount int
+		}
+	}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &data))
+	assert.Equal(t, 9884, data.TeamId)
+	assert.Equal(t, "Playoff", data.MatchType)
+	assert.Equal(t, "F1", data.MatchName)
+	assert.Equal(t, logPeriods{AutoStartSec: 3, AutoEndSec: 18, TeleopStartSec: 20, TeleopEndSec: 155}, data.Periods)
+	if assert.Equal(t, 1, len(data.Rows)) {
+		assert.Equal(t, 4.0, data.Rows[0].MatchTimeSec)
+		assert.False(t, data.Rows[0].RobotLinked)
+		assert.Equal(t, 12.5, data.Rows[0].BatteryVoltage)
+		assert.Equal(t, 3, data.Rows[0].MissedPacketCount)
+	}
+
+	recorder = web.getHttpResponse("/api/logs/20200102030405_Playoff_Match_F2_9884.csv")
+	assert.Equal(t, 500, recorder.Code)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/mitchellh/mapstructure"
 	"io"
 	"log"
+	"maps"
 	"net/http"
 	"sort"
 	"strconv"
@@ -77,21 +78,35 @@ func (web *Web) matchPlayHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	isReplay := matchResult != nil
+	var redAlliance, blueAlliance *model.Alliance
+	if web.arena.CurrentMatch.Type == "elimination" {
+		if redAlliance, err = web.arena.Database.GetAllianceById(web.arena.CurrentMatch.ElimRedAlliance); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		if blueAlliance, err = web.arena.Database.GetAllianceById(web.arena.CurrentMatch.ElimBlueAlliance); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
 	data := struct {
 		*model.EventSettings
-		PlcIsEnabled          bool
-		MatchesByType         map[string]MatchPlayList
-		CurrentMatchType      string
-		Match                 *model.Match
-		RedOffFieldTeams      []int
-		BlueOffFieldTeams     []int
-		RedScore              *game.Score
-		BlueScore             *game.Score
-		AllowSubstitution     bool
-		IsReplay              bool
-		SavedMatchType        string
-		SavedMatch            *model.Match
-		PlcArmorBlockStatuses map[string]bool
+		PlcIsEnabled              bool
+		MatchesByType             map[string]MatchPlayList
+		CurrentMatchType          string
+		Match                     *model.Match
+		RedOffFieldTeams          []int
+		BlueOffFieldTeams         []int
+		RedAlliance               *model.Alliance
+		BlueAlliance              *model.Alliance
+		RedScore                  *game.Score
+		BlueScore                 *game.Score
+		AllowSubstitution         bool
+		IsReplay                  bool
+		SavedMatchType            string
+		SavedMatch                *model.Match
+		PlcArmorBlockStatuses     map[string]bool
+		MaxGameSpecificDataLength int
 	}{
 		web.arena.EventSettings,
 		web.arena.Plc.IsEnabled(),
@@ -100,6 +115,8 @@ func (web *Web) matchPlayHandler(w http.ResponseWriter, r *http.Request) {
 		web.arena.CurrentMatch,
 		redOffFieldTeams,
 		blueOffFieldTeams,
+		redAlliance,
+		blueAlliance,
 		web.arena.RedScore,
 		web.arena.BlueScore,
 		web.arena.CurrentMatch.ShouldAllowSubstitution(),
@@ -107,6 +124,7 @@ func (web *Web) matchPlayHandler(w http.ResponseWriter, r *http.Request) {
 		web.arena.SavedMatch.CapitalizedType(),
 		web.arena.SavedMatch,
 		web.arena.Plc.GetArmorBlockStatuses(),
+		model.MaxGameSpecificDataLength,
 	}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
@@ -219,7 +237,8 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
 	go ws.HandleNotifiers(web.arena.MatchTimingNotifier, web.arena.ArenaStatusNotifier, web.arena.MatchTimeNotifier,
 		web.arena.RealtimeScoreNotifier, web.arena.AudienceDisplayModeNotifier,
-		web.arena.AllianceStationDisplayModeNotifier, web.arena.EventStatusNotifier, web.arena.FieldLightsNotifier)
+		web.arena.AllianceStationDisplayModeNotifier, web.arena.EventStatusNotifier, web.arena.FieldLightsNotifier,
+		web.arena.GameDataNotifier)
 
 	// Loop, waiting for commands and responding to them, until the client closes the connection.
 	for {
@@ -379,6 +398,71 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 				ws.WriteError(err.Error())
 				continue
 			}
+		case "startAllianceTimeout":
+			args := struct {
+				AllianceId  int
+				DurationSec int
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			err = web.arena.StartAllianceTimeout(args.AllianceId, args.DurationSec)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			alliance, err := web.arena.Database.GetAllianceById(args.AllianceId)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			err = ws.Write("allianceTimeouts", map[string]int{
+				"AllianceId": alliance.Id, "TimeoutsRemaining": alliance.TimeoutsRemaining(),
+			})
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		case "invokeBackup":
+			args := struct {
+				AllianceId int
+				TeamId     int
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			backupTeamId, err := web.arena.InvokeBackup(args.AllianceId, args.TeamId)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			if web.arena.EventSettings.TbaPublishingEnabled {
+				if err = web.arena.TbaClient.PublishAlliances(web.arena.Database); err != nil {
+					ws.WriteError(fmt.Sprintf("Failed to publish alliances: %s", err.Error()))
+				}
+			}
+			err = ws.Write("backupInvoked", map[string]int{"AllianceId": args.AllianceId, "TeamId": backupTeamId})
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		case "retimeSchedule":
+			retimedMatches, err := web.arena.RetimeRemainingMatches()
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			if len(retimedMatches) > 0 && web.arena.EventSettings.TbaPublishingEnabled &&
+				web.arena.CurrentMatch.Type != "practice" {
+				if err = web.arena.TbaClient.PublishMatches(web.arena.Database); err != nil {
+					ws.WriteError(fmt.Sprintf("Failed to publish matches: %s", err.Error()))
+					continue
+				}
+			}
 		case "setTestMatchName":
 			if web.arena.CurrentMatch.Type != "test" {
 				// Don't allow changing the name of a non-test match.
@@ -392,14 +476,49 @@ func (web *Web) matchPlayWebsocketHandler(w http.ResponseWriter, r *http.Request
 			web.arena.CurrentMatch.DisplayName = name
 			web.arena.MatchLoadNotifier.Notify()
 			continue
+		case "setGameData":
+			args := struct {
+				Red  string
+				Blue string
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			err = web.arena.SetGameData(args.Red, args.Blue)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
 		case "updateRealtimeScore":
-			args := data.(map[string]interface{})
-			web.arena.BlueScore.AutoPoints = int(args["blueAuto"].(float64))
-			web.arena.RedScore.AutoPoints = int(args["redAuto"].(float64))
-			web.arena.BlueScore.TeleopPoints = int(args["blueTeleop"].(float64))
-			web.arena.RedScore.TeleopPoints = int(args["redTeleop"].(float64))
-			web.arena.BlueScore.EndgamePoints = int(args["blueEndgame"].(float64))
-			web.arena.RedScore.EndgamePoints = int(args["redEndgame"].(float64))
+			args := struct {
+				Red  game.Score
+				Blue game.Score
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			redScore, err := applyScoreCounts(new(game.Score), args.Red.ElementCounts)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			blueScore, err := applyScoreCounts(new(game.Score), args.Blue.ElementCounts)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			if args.Red.Fouls < 0 || args.Red.TechFouls < 0 || args.Blue.Fouls < 0 || args.Blue.TechFouls < 0 {
+				ws.WriteError("Foul counts cannot be negative.")
+				continue
+			}
+			redScore.Fouls, redScore.TechFouls = args.Red.Fouls, args.Red.TechFouls
+			blueScore.Fouls, blueScore.TechFouls = args.Blue.Fouls, args.Blue.TechFouls
+			*web.arena.RedScore = *redScore
+			*web.arena.BlueScore = *blueScore
 			web.arena.RealtimeScoreNotifier.Notify()
 		default:
 			ws.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
@@ -420,6 +539,16 @@ func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchRes
 	var updatedRankings game.Rankings
 
 	if match.Type != "test" {
+		if match.ShouldUpdateCards() {
+			// A second yellow card within the same phase of the tournament becomes a red card.
+			if err := tournament.ApplyYellowCardCarryover(web.arena.Database, match, matchResult); err != nil {
+				return err
+			}
+		}
+		if match.ShouldUpdateEliminationMatches() {
+			matchResult.CorrectPlayoffScore()
+		}
+
 		if matchResult.PlayNumber == 0 {
 			// Determine the play number for this new match result.
 			prevMatchResult, err := web.arena.Database.GetMatchResultForMatch(match.Id)
@@ -447,14 +576,19 @@ func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchRes
 
 		// Update and save the match record to the database.
 		match.ScoreCommittedAt = time.Now()
-		redScoreSummary := matchResult.RedScoreSummary()
-		blueScoreSummary := matchResult.BlueScoreSummary()
-		match.Status = game.DetermineMatchStatus(redScoreSummary, blueScoreSummary)
+		web.updateMatchStatus(match, matchResult)
 		err := web.arena.Database.UpdateMatch(match)
 		if err != nil {
 			return err
 		}
 
+		if match.ShouldUpdateCards() {
+			// A changed card may affect whether a yellow card in a later match should have become a red card.
+			if err = web.updateLaterYellowCardCarryover(match); err != nil {
+				return err
+			}
+		}
+
 		if match.ShouldUpdateRankings() {
 			// Recalculate all the rankings.
 			rankings, err := tournament.CalculateRankings(web.arena.Database, isMatchReviewEdit)
@@ -527,9 +661,84 @@ func (web *Web) commitMatchScore(match *model.Match, matchResult *model.MatchRes
 	return nil
 }
 
+// Sets the status of the given match from its result, breaking a playoff tie if necessary.
+func (web *Web) updateMatchStatus(match *model.Match, matchResult *model.MatchResult) {
+	redScoreSummary := matchResult.RedScoreSummary()
+	blueScoreSummary := matchResult.BlueScoreSummary()
+	match.Status = game.DetermineMatchStatus(redScoreSummary, blueScoreSummary)
+	match.TiebreakReason = ""
+	if match.Status == game.TieMatch && web.shouldBreakPlayoffTie(match) {
+		var tiebreaker game.PlayoffTiebreaker
+		match.Status, tiebreaker = game.BreakPlayoffTie(
+			redScoreSummary, blueScoreSummary, web.arena.EventSettings.PlayoffTiebreakers,
+		)
+		if tiebreaker != "" {
+			match.TiebreakReason = tiebreaker.Description(web.arena.EventSettings.GameDefinition)
+		}
+	}
+}
+
+// Re-applies the yellow card carryover to the completed matches of the same type that follow the given one, saving
+// any whose cards changed as a result.
+func (web *Web) updateLaterYellowCardCarryover(match *model.Match) error {
+	matches, err := web.arena.Database.GetMatchesByType(match.Type)
+	if err != nil {
+		return err
+	}
+	isLater := false
+	for i := range matches {
+		laterMatch := &matches[i]
+		if laterMatch.Id == match.Id {
+			isLater = true
+			continue
+		}
+		if !isLater || !laterMatch.IsComplete() {
+			continue
+		}
+		matchResult, err := web.arena.Database.GetMatchResultForMatch(laterMatch.Id)
+		if err != nil {
+			return err
+		}
+		if matchResult == nil {
+			continue
+		}
+
+		redCards := maps.Clone(matchResult.RedCards)
+		blueCards := maps.Clone(matchResult.BlueCards)
+		if err = tournament.ApplyYellowCardCarryover(web.arena.Database, laterMatch, matchResult); err != nil {
+			return err
+		}
+		if maps.Equal(redCards, matchResult.RedCards) && maps.Equal(blueCards, matchResult.BlueCards) {
+			continue
+		}
+		if laterMatch.ShouldUpdateEliminationMatches() {
+			matchResult.CorrectPlayoffScore()
+		}
+		if err = web.arena.Database.UpdateMatchResult(matchResult); err != nil {
+			return err
+		}
+		web.updateMatchStatus(laterMatch, matchResult)
+		if err = web.arena.Database.UpdateMatch(laterMatch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns true if a tie in the given match should be decided by the playoff tiebreakers rather than standing or being
+// replayed, which is the case for every playoff match outside of a round robin.
+func (web *Web) shouldBreakPlayoffTie(match *model.Match) bool {
+	if match.Type != "elimination" {
+		return false
+	}
+	matchup, err := web.arena.PlayoffBracket.GetMatchup(match.ElimRound, match.ElimGroup)
+	return err != nil || !matchup.IsRoundRobin()
+}
+
 func (web *Web) getCurrentMatchResult() *model.MatchResult {
 	return &model.MatchResult{MatchId: web.arena.CurrentMatch.Id, MatchType: web.arena.CurrentMatch.Type,
-		RedScore: web.arena.RedScore, BlueScore: web.arena.BlueScore}
+		RedScore: web.arena.RedScore, BlueScore: web.arena.BlueScore, RedCards: web.arena.RedCards,
+		BlueCards: web.arena.BlueCards}
 }
 
 // Saves the realtime result as the final score for the match currently loaded into the arena.
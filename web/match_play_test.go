@@ -139,7 +139,7 @@ func TestCommitMatch(t *testing.T) {
 	assert.Nil(t, web.arena.Database.CreateMatch(match))
 	matchResult = model.NewMatchResult()
 	matchResult.MatchId = match.Id
-	matchResult.BlueScore = &game.Score{AutoPoints: 10}
+	matchResult.BlueScore = &game.Score{ElementCounts: map[string]int{"auto": 10}}
 	err = web.commitMatchScore(match, matchResult, true)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, matchResult.PlayNumber)
@@ -148,7 +148,7 @@ func TestCommitMatch(t *testing.T) {
 
 	matchResult = model.NewMatchResult()
 	matchResult.MatchId = match.Id
-	matchResult.RedScore = &game.Score{AutoPoints: 20}
+	matchResult.RedScore = &game.Score{ElementCounts: map[string]int{"auto": 20}}
 	err = web.commitMatchScore(match, matchResult, true)
 	assert.Nil(t, err)
 	assert.Equal(t, 2, matchResult.PlayNumber)
@@ -191,6 +191,7 @@ func TestCommitEliminationTie(t *testing.T) {
 	assert.Equal(t, game.TieMatch, match.Status)
 
 	tournament.CreateTestAlliances(web.arena.Database, 2)
+	web.arena.EventSettings.NumElimAlliances = 2
 	web.arena.CreatePlayoffBracket()
 	match.Type = "elimination"
 	match.ElimRedAlliance = 1
@@ -199,6 +200,63 @@ func TestCommitEliminationTie(t *testing.T) {
 	web.commitMatchScore(match, matchResult, true)
 	match, _ = web.arena.Database.GetMatchById(1)
 	assert.Equal(t, game.TieMatch, match.Status)
+	assert.Equal(t, "", match.TiebreakReason)
+
+	// A tie with equal foul points is decided by the auto points.
+	matchResult.RedScore.ElementCounts = map[string]int{"auto": 3, "teleop": 2}
+	matchResult.BlueScore.ElementCounts = map[string]int{"auto": 1, "teleop": 4}
+	assert.Nil(t, web.commitMatchScore(match, matchResult, true))
+	match, _ = web.arena.Database.GetMatchById(1)
+	assert.Equal(t, game.RedWonMatch, match.Status)
+	assert.Equal(t, "More auto points", match.TiebreakReason)
+
+	// Without any playoff tiebreakers the tie stands and the match is replayed.
+	web.arena.EventSettings.PlayoffTiebreakers = []game.PlayoffTiebreaker{}
+	assert.Nil(t, web.commitMatchScore(match, matchResult, true))
+	match, _ = web.arena.Database.GetMatchById(1)
+	assert.Equal(t, game.TieMatch, match.Status)
+	assert.Equal(t, "", match.TiebreakReason)
+}
+
+func TestCommitCards(t *testing.T) {
+	web := setupTestWeb(t)
+
+	// A second yellow card within qualifications should be upgraded to a red card.
+	match1 := &model.Match{Type: "qualification", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5, Blue3: 6}
+	web.arena.Database.CreateMatch(match1)
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match1.Id
+	matchResult.RedCards["1"] = model.YellowCard
+	assert.Nil(t, web.commitMatchScore(match1, matchResult, true))
+	assert.Equal(t, model.YellowCard, matchResult.RedCards["1"])
+
+	match2 := &model.Match{Type: "qualification", Red1: 4, Red2: 5, Red3: 6, Blue1: 1, Blue2: 2, Blue3: 3}
+	web.arena.Database.CreateMatch(match2)
+	matchResult = model.NewMatchResult()
+	matchResult.MatchId = match2.Id
+	matchResult.BlueCards["1"] = model.YellowCard
+	matchResult.BlueCards["2"] = model.YellowCard
+	assert.Nil(t, web.commitMatchScore(match2, matchResult, true))
+	matchResult, _ = web.arena.Database.GetMatchResultForMatch(match2.Id)
+	assert.Equal(t, model.RedCard, matchResult.BlueCards["1"])
+	assert.Equal(t, model.YellowCard, matchResult.BlueCards["2"])
+
+	// A red card in the playoffs should disqualify the whole alliance.
+	web.arena.EventSettings.NumElimAlliances = 2
+	tournament.CreateTestAlliances(web.arena.Database, 2)
+	assert.Nil(t, web.arena.CreatePlayoffBracket())
+	match3 := &model.Match{Type: "elimination", Red1: 102, Red2: 101, Red3: 103, Blue1: 202, Blue2: 201, Blue3: 203,
+		ElimRedAlliance: 1, ElimBlueAlliance: 2}
+	web.arena.Database.CreateMatch(match3)
+	matchResult = model.NewMatchResult()
+	matchResult.MatchId = match3.Id
+	matchResult.RedScore = &game.Score{ElementCounts: map[string]int{"auto": 20}}
+	matchResult.RedCards["101"] = model.RedCard
+	assert.Nil(t, web.commitMatchScore(match3, matchResult, true))
+	assert.True(t, matchResult.RedScore.PlayoffDq)
+	assert.False(t, matchResult.BlueScore.PlayoffDq)
+	match3, _ = web.arena.Database.GetMatchById(match3.Id)
+	assert.Equal(t, game.BlueWonMatch, match3.Status)
 }
 
 func TestMatchPlayWebsocketCommands(t *testing.T) {
@@ -271,21 +329,18 @@ func TestMatchPlayWebsocketCommands(t *testing.T) {
 	readWebsocketType(t, ws, "allianceStationDisplayMode")
 	assert.Equal(t, field.PostMatch, web.arena.MatchState)
 	ws.Write("updateRealtimeScore", map[string]any{
-		"blueAuto":    10,
-		"redAuto":     20,
-		"blueTeleop":  30,
-		"redTeleop":   40,
-		"blueEndgame": 50,
-		"redEndgame":  60,
+		"red":  map[string]any{"ElementCounts": map[string]int{"auto": 20, "teleop": 40, "endgame": 60}, "Fouls": 1},
+		"blue": map[string]any{"ElementCounts": map[string]int{"auto": 10, "teleop": 30, "endgame": 50}},
 	})
 	readWebsocketType(t, ws, "arenaStatus")
 	readWebsocketType(t, ws, "realtimeScore")
-	assert.Equal(t, 20, web.arena.SavedMatchResult.RedScore.AutoPoints)
-	assert.Equal(t, 40, web.arena.SavedMatchResult.RedScore.TeleopPoints)
-	assert.Equal(t, 60, web.arena.SavedMatchResult.RedScore.EndgamePoints)
-	assert.Equal(t, 10, web.arena.SavedMatchResult.BlueScore.AutoPoints)
-	assert.Equal(t, 30, web.arena.SavedMatchResult.BlueScore.TeleopPoints)
-	assert.Equal(t, 50, web.arena.SavedMatchResult.BlueScore.EndgamePoints)
+	assert.Equal(t, 20, web.arena.SavedMatchResult.RedScore.Count("auto"))
+	assert.Equal(t, 40, web.arena.SavedMatchResult.RedScore.Count("teleop"))
+	assert.Equal(t, 60, web.arena.SavedMatchResult.RedScore.Count("endgame"))
+	assert.Equal(t, 10, web.arena.SavedMatchResult.BlueScore.Count("auto"))
+	assert.Equal(t, 30, web.arena.SavedMatchResult.BlueScore.Count("teleop"))
+	assert.Equal(t, 50, web.arena.SavedMatchResult.BlueScore.Count("endgame"))
+	assert.Equal(t, 1, web.arena.SavedMatchResult.RedScore.Fouls)
 	ws.Write("commitResults", nil)
 	readWebsocketMultiple(t, ws, 3) // reload, realtimeScore, setAllianceStationDisplay
 	assert.Equal(t, field.PreMatch, web.arena.MatchState)
@@ -374,6 +429,114 @@ func TestMatchPlayWebsocketNotifications(t *testing.T) {
 	assert.Equal(t, game.MatchTiming.WarmupDurationSec+game.MatchTiming.AutoDurationSec, matchTime.MatchTimeSec)
 }
 
+func TestMatchPlayWebsocketRetimeSchedule(t *testing.T) {
+	web := setupTestWeb(t)
+
+	blockStart := time.Now().UTC().Add(-30 * time.Minute).Truncate(time.Second)
+	web.arena.Database.CreateScheduleBlock(
+		&model.ScheduleBlock{MatchType: "qualification", StartTime: blockStart, NumMatches: 3, MatchSpacingSec: 360},
+	)
+	for i := 0; i < 3; i++ {
+		web.arena.Database.CreateMatch(&model.Match{
+			Type: "qualification", DisplayName: fmt.Sprint(i + 1), Time: blockStart.Add(time.Duration(i*6) * time.Minute),
+		})
+	}
+	matches, _ := web.arena.Database.GetMatchesByType("qualification")
+	assert.Nil(t, web.arena.LoadMatch(&matches[0]))
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/match_play/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	queueingConn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/displays/queueing/websocket?displayId=1", nil)
+	assert.Nil(t, err)
+	defer queueingConn.Close()
+	queueingWs := websocket.NewTestWebsocket(queueingConn)
+	readWebsocketType(t, queueingWs, "displayConfiguration")
+	readWebsocketType(t, queueingWs, "matchTiming")
+	readWebsocketType(t, queueingWs, "matchLoad")
+	readWebsocketType(t, queueingWs, "matchTime")
+	readWebsocketType(t, queueingWs, "eventStatus")
+	readWebsocketType(t, queueingWs, "allianceRest")
+
+	// The queueing display should be told to refresh once the remaining matches have been shifted to start now.
+	ws.Write("retimeSchedule", nil)
+	readWebsocketType(t, queueingWs, "scheduleUpdate")
+	matches, _ = web.arena.Database.GetMatchesByType("qualification")
+	assert.WithinDuration(t, time.Now(), matches[0].Time, 2*time.Second)
+	assert.Equal(t, matches[0].Time.Add(6*time.Minute), matches[1].Time)
+	assert.Equal(t, matches[0].Time.Add(12*time.Minute), matches[2].Time)
+}
+
+func TestMatchPlayWebsocketAllianceTimeout(t *testing.T) {
+	web := setupTestWeb(t)
+	tournament.CreateTestAlliances(web.arena.Database, 2)
+	match := model.Match{Type: "elimination", DisplayName: "F-1", ElimRedAlliance: 1, ElimBlueAlliance: 2}
+	web.arena.Database.CreateMatch(&match)
+	assert.Nil(t, web.arena.LoadMatch(&match))
+
+	recorder := web.getHttpResponse("/match_play")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `<span id="allianceTimeouts2">1</span>`)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/match_play/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	readWebsocketMultiple(t, ws, 9)
+
+	ws.Write("startAllianceTimeout", map[string]any{"allianceId": 2, "durationSec": 90})
+	messages := readWebsocketMultiple(t, ws, 4)
+	if assert.Contains(t, messages, "allianceTimeouts") {
+		assert.Equal(t, map[string]any{"AllianceId": 2.0, "TimeoutsRemaining": 0.0}, messages["allianceTimeouts"])
+	}
+	assert.Equal(t, field.TimeoutActive, web.arena.MatchState)
+	assert.Equal(t, 90, game.MatchTiming.TimeoutDurationSec)
+
+	web.arena.MatchState = field.PreMatch
+	ws.Write("startAllianceTimeout", map[string]any{"allianceId": 2, "durationSec": 90})
+	assert.Contains(t, readWebsocketError(t, ws), "alliance 2 has no timeouts remaining")
+	recorder = web.getHttpResponse("/match_play")
+	assert.Contains(t, recorder.Body.String(), `<span id="allianceTimeouts2">0</span>`)
+}
+
+func TestMatchPlayWebsocketInvokeBackup(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.NumElimAlliances = 2
+	tournament.CreateTestAlliances(web.arena.Database, 2)
+	web.arena.Database.CreateRanking(&game.Ranking{TeamId: 254, Rank: 9})
+	assert.Nil(t, web.arena.CreatePlayoffBracket())
+	assert.Nil(t, web.arena.UpdatePlayoffBracket(nil))
+	matches, _ := web.arena.Database.GetMatchesByType("elimination")
+	assert.Nil(t, web.arena.LoadMatch(&matches[0]))
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/match_play/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	readWebsocketMultiple(t, ws, 9)
+
+	ws.Write("invokeBackup", map[string]any{"allianceId": 1, "teamId": 104})
+	assert.Contains(t, readWebsocketError(t, ws), "team 104 is not in the lineup of alliance 1")
+	ws.Write("invokeBackup", map[string]any{"allianceId": 1, "teamId": 103})
+	messages := readWebsocketMultiple(t, ws, 2)
+	if assert.Contains(t, messages, "backupInvoked") {
+		assert.Equal(t, map[string]any{"AllianceId": 1.0, "TeamId": 254.0}, messages["backupInvoked"])
+	}
+	assert.Equal(t, 254, web.arena.CurrentMatch.Red3)
+
+	// The backup is shown as called on the backup teams report.
+	recorder := web.getHttpResponse("/reports/csv/backups")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "9,true,254,0")
+}
+
 // Handles the status and matchTime messages arriving in either order.
 func readWebsocketStatusMatchTime(t *testing.T, ws *websocket.Websocket) (bool, field.MatchTimeMessage) {
 	return getStatusMatchTime(t, readWebsocketMultiple(t, ws, 2))
@@ -389,3 +552,50 @@ func getStatusMatchTime(t *testing.T, messages map[string]any) (bool, field.Matc
 	}
 	return statusReceived, matchTime
 }
+
+func TestMatchPlayWebsocketGameData(t *testing.T) {
+	web := setupTestWeb(t)
+	web.arena.EventSettings.GameSpecificDataOptions = "LRL"
+	match := model.Match{Type: "qualification", DisplayName: "1"}
+	web.arena.Database.CreateMatch(&match)
+	assert.Nil(t, web.arena.LoadMatch(&match))
+
+	recorder := web.getHttpResponse("/match_play")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `value="LRL"`)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/match_play/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	readWebsocketMultiple(t, ws, 9)
+
+	queueingConn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/displays/queueing/websocket?displayId=1", nil)
+	assert.Nil(t, err)
+	defer queueingConn.Close()
+	queueingWs := websocket.NewTestWebsocket(queueingConn)
+	readWebsocketMultiple(t, queueingWs, 6)
+
+	// Messages set by hand should be saved with the match straight away and pushed out to the other clients.
+	ws.Write("setGameData", map[string]any{"Red": "RRL", "Blue": "LLR"})
+	messages := readWebsocketMultiple(t, ws, 2)
+	assert.Contains(t, messages, "arenaStatus")
+	assert.Contains(t, messages, "gameData")
+	readWebsocketType(t, queueingWs, "matchLoad")
+	assert.Equal(t, "RRL", web.arena.CurrentMatch.RedGameData)
+	assert.Equal(t, "LLR", web.arena.CurrentMatch.BlueGameData)
+	savedMatch, _ := web.arena.Database.GetMatchById(match.Id)
+	assert.Equal(t, "RRL", savedMatch.RedGameData)
+	assert.Equal(t, "LLR", savedMatch.BlueGameData)
+
+	web.arena.CurrentMatch.StartedAt = time.Now()
+	web.arena.Database.UpdateMatch(web.arena.CurrentMatch)
+	recorder = web.getHttpResponse("/match_review")
+	assert.Contains(t, recorder.Body.String(), `<span class="red-text">RRL</span>`)
+
+	web.arena.MatchState = field.AutoPeriod
+	ws.Write("setGameData", map[string]any{"Red": "RRR", "Blue": "RRR"})
+	assert.Contains(t, readWebsocketError(t, ws), "once the match has started")
+}
@@ -16,15 +16,17 @@ import (
 )
 
 type MatchReviewListItem struct {
-	Id          int
-	DisplayName string
-	Time        string
-	RedTeams    []int
-	BlueTeams   []int
-	RedScore    int
-	BlueScore   int
-	ColorClass  string
-	IsComplete  bool
+	Id           int
+	DisplayName  string
+	Time         string
+	RedTeams     []int
+	BlueTeams    []int
+	RedScore     int
+	BlueScore    int
+	RedGameData  string
+	BlueGameData string
+	ColorClass   string
+	IsComplete   bool
 }
 
 // Shows the match review interface.
@@ -128,6 +130,12 @@ func (web *Web) matchReviewEditPostHandler(w http.ResponseWriter, r *http.Reques
 		// If editing the current match, just save it back to memory.
 		*web.arena.RedScore = *matchResult.RedScore
 		*web.arena.BlueScore = *matchResult.BlueScore
+		if matchResult.RedCards != nil {
+			web.arena.RedCards = matchResult.RedCards
+		}
+		if matchResult.BlueCards != nil {
+			web.arena.BlueCards = matchResult.BlueCards
+		}
 
 		http.Redirect(w, r, "/match_play", 303)
 	} else {
@@ -186,6 +194,8 @@ func (web *Web) buildMatchReviewList(matchType string) ([]MatchReviewListItem, e
 		matchReviewList[i].Time = match.Time.Local().Format("Mon 1/02 03:04 PM")
 		matchReviewList[i].RedTeams = []int{match.Red1, match.Red2, match.Red3}
 		matchReviewList[i].BlueTeams = []int{match.Blue1, match.Blue2, match.Blue3}
+		matchReviewList[i].RedGameData = match.RedGameData
+		matchReviewList[i].BlueGameData = match.BlueGameData
 		matchResult, err := web.arena.Database.GetMatchResultForMatch(match.Id)
 		if err != nil {
 			return []MatchReviewListItem{}, err
@@ -66,8 +66,8 @@ func TestMatchReviewEditExistingResult(t *testing.T) {
 
 	// Update the score to something else.
 	postBody := fmt.Sprintf(
-		"matchResultJson={\"MatchId\":%d,\"RedScore\":{\"AutoPoints\":45,\"TeleopPoints\":80,\"EndgamePoints\":10},"+
-			"\"BlueScore\":{\"AutoPoints\":15,\"TeleopPoints\":60,\"EndgamePoints\":50}}",
+		"matchResultJson={\"MatchId\":%d,\"RedScore\":{\"ElementCounts\":{\"auto\":45,\"teleop\":80,\"endgame\":10}},"+
+			"\"BlueScore\":{\"ElementCounts\":{\"auto\":15,\"teleop\":60,\"endgame\":50}}}",
 		match.Id,
 	)
 	recorder = web.postHttpResponse(fmt.Sprintf("/match_review/%d/edit", match.Id), postBody)
@@ -103,8 +103,8 @@ func TestMatchReviewCreateNewResult(t *testing.T) {
 
 	// Update the score to something else.
 	postBody := fmt.Sprintf(
-		"matchResultJson={\"MatchId\":%d,\"RedScore\":{\"AutoPoints\":10,\"TeleopPoints\":20,\"EndgamePoints\":30},"+
-			"\"BlueScore\":{\"AutoPoints\":40,\"TeleopPoints\":50,\"EndgamePoints\":60}}",
+		"matchResultJson={\"MatchId\":%d,\"RedScore\":{\"ElementCounts\":{\"auto\":10,\"teleop\":20,\"endgame\":30}},"+
+			"\"BlueScore\":{\"ElementCounts\":{\"auto\":40,\"teleop\":50,\"endgame\":60}}}",
 		match.Id,
 	)
 	recorder = web.postHttpResponse(fmt.Sprintf("/match_review/%d/edit", match.Id), postBody)
@@ -140,8 +140,8 @@ func TestMatchReviewEditCurrentMatch(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), " 352 ")
 
 	postBody := fmt.Sprintf(
-		"matchResultJson={\"MatchId\":%d,\"RedScore\":{\"AutoPoints\":10,\"TeleopPoints\":20,\"EndgamePoints\":30},"+
-			"\"BlueScore\":{\"AutoPoints\":40,\"TeleopPoints\":50,\"EndgamePoints\":60}}",
+		"matchResultJson={\"MatchId\":%d,\"RedScore\":{\"ElementCounts\":{\"auto\":10,\"teleop\":20,\"endgame\":30}},"+
+			"\"BlueScore\":{\"ElementCounts\":{\"auto\":40,\"teleop\":50,\"endgame\":60}}}",
 		match.Id,
 	)
 	recorder = web.postHttpResponse("/match_review/current/edit", postBody)
@@ -151,10 +151,54 @@ func TestMatchReviewEditCurrentMatch(t *testing.T) {
 	// Check that the persisted match is still unedited and that the realtime scores have been updated instead.
 	match2, _ := web.arena.Database.GetMatchById(match.Id)
 	assert.Equal(t, game.MatchNotPlayed, match2.Status)
-	assert.Equal(t, 10, web.arena.RedScore.AutoPoints)
-	assert.Equal(t, 20, web.arena.RedScore.TeleopPoints)
-	assert.Equal(t, 30, web.arena.RedScore.EndgamePoints)
-	assert.Equal(t, 40, web.arena.BlueScore.AutoPoints)
-	assert.Equal(t, 50, web.arena.BlueScore.TeleopPoints)
-	assert.Equal(t, 60, web.arena.BlueScore.EndgamePoints)
+	assert.Equal(t, 10, web.arena.RedScore.Count("auto"))
+	assert.Equal(t, 20, web.arena.RedScore.Count("teleop"))
+	assert.Equal(t, 30, web.arena.RedScore.Count("endgame"))
+	assert.Equal(t, 40, web.arena.BlueScore.Count("auto"))
+	assert.Equal(t, 50, web.arena.BlueScore.Count("teleop"))
+	assert.Equal(t, 60, web.arena.BlueScore.Count("endgame"))
+}
+
+func TestMatchReviewEditCardCarryover(t *testing.T) {
+	web := setupTestWeb(t)
+
+	match1 := model.Match{Type: "qualification", DisplayName: "1", Red1: 1, Red2: 2, Red3: 3, Blue1: 4, Blue2: 5,
+		Blue3: 6}
+	web.arena.Database.CreateMatch(&match1)
+	matchResult := model.NewMatchResult()
+	matchResult.MatchId = match1.Id
+	matchResult.RedCards["1"] = model.YellowCard
+	assert.Nil(t, web.commitMatchScore(&match1, matchResult, false))
+	match2 := model.Match{Type: "qualification", DisplayName: "2", Red1: 4, Red2: 5, Red3: 6, Blue1: 1, Blue2: 2,
+		Blue3: 3}
+	web.arena.Database.CreateMatch(&match2)
+	matchResult = model.NewMatchResult()
+	matchResult.MatchId = match2.Id
+	matchResult.BlueCards["1"] = model.YellowCard
+	matchResult.BlueCards["2"] = model.RedCard
+	assert.Nil(t, web.commitMatchScore(&match2, matchResult, false))
+	matchResult, _ = web.arena.Database.GetMatchResultForMatch(match2.Id)
+	assert.Equal(t, map[string]string{"1": model.RedCard, "2": model.RedCard}, matchResult.BlueCards)
+
+	// Removing the first yellow card should undo the upgrade in the later match but leave the direct red card.
+	postBody := fmt.Sprintf("matchResultJson={\"MatchId\":%d,\"RedScore\":{},\"BlueScore\":{},\"RedCards\":{}}",
+		match1.Id)
+	recorder := web.postHttpResponse(fmt.Sprintf("/match_review/%d/edit", match1.Id), postBody)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	matchResult, _ = web.arena.Database.GetMatchResultForMatch(match2.Id)
+	assert.Equal(t, map[string]string{"1": model.YellowCard, "2": model.RedCard}, matchResult.BlueCards)
+	assert.Equal(t, map[string]bool{}, matchResult.CarryoverRedCards)
+	ranking, _ := web.arena.Database.GetRankingForTeam(1)
+	if assert.NotNil(t, ranking) {
+		assert.Equal(t, 0, ranking.Disqualifications)
+	}
+
+	// Adding it back should upgrade the later yellow card again.
+	postBody = fmt.Sprintf("matchResultJson={\"MatchId\":%d,\"RedScore\":{},\"BlueScore\":{},"+
+		"\"RedCards\":{\"1\":\"yellow\"}}", match1.Id)
+	recorder = web.postHttpResponse(fmt.Sprintf("/match_review/%d/edit", match1.Id), postBody)
+	assert.Equal(t, 303, recorder.Code, recorder.Body.String())
+	matchResult, _ = web.arena.Database.GetMatchResultForMatch(match2.Id)
+	assert.Equal(t, map[string]string{"1": model.RedCard, "2": model.RedCard}, matchResult.BlueCards)
+	assert.Equal(t, map[string]bool{"1": true}, matchResult.CarryoverRedCards)
 }
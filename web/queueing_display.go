@@ -108,5 +108,6 @@ func (web *Web) queueingDisplayWebsocketHandler(w http.ResponseWriter, r *http.R
 
 	// Subscribe the websocket to the notifiers whose messages will be passed on to the client.
 	ws.HandleNotifiers(display.Notifier, web.arena.MatchTimingNotifier, web.arena.MatchLoadNotifier,
-		web.arena.MatchTimeNotifier, web.arena.EventStatusNotifier, web.arena.ReloadDisplaysNotifier)
+		web.arena.MatchTimeNotifier, web.arena.EventStatusNotifier, web.arena.ScheduleUpdateNotifier,
+		web.arena.AllianceRestNotifier, web.arena.ReloadDisplaysNotifier)
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/jung-kurt/gofpdf"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -35,7 +36,11 @@ func (web *Web) rankingsCsvReportHandler(w http.ResponseWriter, r *http.Request)
 		handleWebErr(w, err)
 		return
 	}
-	err = template.ExecuteTemplate(w, "rankings.csv", rankings)
+	data := struct {
+		Rankings    game.Rankings
+		Tiebreakers []game.Tiebreaker
+	}{rankings, web.arena.EventSettings.RankingRules.DisplayTiebreakers()}
+	err = template.ExecuteTemplate(w, "rankings.csv", data)
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -51,8 +56,8 @@ func (web *Web) rankingsPdfReportHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	// The widths of the table columns in mm, stored here so that they can be referenced for each row.
-	colWidths := map[string]float64{"Rank": 13, "Team": 22, "RP": 23, "Auto": 23, "Endgame": 23, "Teleop": 23,
-		"W-L-T": 23, "DQ": 23, "Played": 23}
+	tiebreakers := web.arena.EventSettings.RankingRules.DisplayTiebreakers()
+	colWidths := map[string]float64{"Rank": 13, "Team": 22, "Tiebreaker": 23, "W-L-T": 23, "DQ": 23, "Played": 23}
 	rowHeight := 6.5
 
 	pdf := gofpdf.New("P", "mm", "Letter", "font")
@@ -64,10 +69,9 @@ func (web *Web) rankingsPdfReportHandler(w http.ResponseWriter, r *http.Request)
 	pdf.CellFormat(195, rowHeight, "Team Standings - "+web.arena.EventSettings.Name, "", 1, "C", false, 0, "")
 	pdf.CellFormat(colWidths["Rank"], rowHeight, "Rank", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["Team"], rowHeight, "Team", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(colWidths["RP"], rowHeight, "RP", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(colWidths["Auto"], rowHeight, "Auto", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(colWidths["Endgame"], rowHeight, "Endgame", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(colWidths["Teleop"], rowHeight, "Teleop", "1", 0, "C", true, 0, "")
+	for _, tiebreaker := range tiebreakers {
+		pdf.CellFormat(colWidths["Tiebreaker"], rowHeight, tiebreaker.Name(), "1", 0, "C", true, 0, "")
+	}
 	pdf.CellFormat(colWidths["W-L-T"], rowHeight, "W-L-T", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["Played"], rowHeight, "Played", "1", 1, "C", true, 0, "")
 	for _, ranking := range rankings {
@@ -76,10 +80,10 @@ func (web *Web) rankingsPdfReportHandler(w http.ResponseWriter, r *http.Request)
 		pdf.CellFormat(colWidths["Rank"], rowHeight, strconv.Itoa(ranking.Rank), "1", 0, "C", false, 0, "")
 		pdf.SetFont("Arial", "", 10)
 		pdf.CellFormat(colWidths["Team"], rowHeight, strconv.Itoa(ranking.TeamId), "1", 0, "C", false, 0, "")
-		pdf.CellFormat(colWidths["RP"], rowHeight, strconv.Itoa(ranking.RankingPoints), "1", 0, "C", false, 0, "")
-		pdf.CellFormat(colWidths["Auto"], rowHeight, strconv.Itoa(ranking.AutoPoints), "1", 0, "C", false, 0, "")
-		pdf.CellFormat(colWidths["Endgame"], rowHeight, strconv.Itoa(ranking.EndgamePoints), "1", 0, "C", false, 0, "")
-		pdf.CellFormat(colWidths["Teleop"], rowHeight, strconv.Itoa(ranking.TeleopPoints), "1", 0, "C", false, 0, "")
+		for _, tiebreaker := range tiebreakers {
+			value := strconv.Itoa(ranking.TiebreakerValue(tiebreaker))
+			pdf.CellFormat(colWidths["Tiebreaker"], rowHeight, value, "1", 0, "C", false, 0, "")
+		}
 		record := fmt.Sprintf("%d-%d-%d", ranking.Wins, ranking.Losses, ranking.Ties)
 		pdf.CellFormat(colWidths["W-L-T"], rowHeight, record, "1", 0, "C", false, 0, "")
 		pdf.CellFormat(colWidths["Played"], rowHeight, strconv.Itoa(ranking.Played), "1", 1, "C", false, 0, "")
@@ -119,13 +123,22 @@ func (web *Web) findBackupTeams(rankings game.Rankings) (game.Rankings, map[int]
 	pickedBackups := make(map[int]bool)
 
 	for _, alliance := range alliances {
+		// Teams that declined an invitation during alliance selection aren't eligible to be backups.
+		for _, declinedTeamId := range alliance.DeclinedTeamIds {
+			pickedTeams[declinedTeamId] = true
+		}
+		for _, backupTeamId := range alliance.BackupTeamIds {
+			pickedBackups[backupTeamId] = true
+		}
 		for i, allianceTeamId := range alliance.TeamIds {
 			// Teams in third in an alliance are backups at events that use 3 team alliances.
 			if i == 3 {
 				pickedBackups[allianceTeamId] = true
 				continue
 			}
-			pickedTeams[allianceTeamId] = true
+			if !pickedBackups[allianceTeamId] {
+				pickedTeams[allianceTeamId] = true
+			}
 		}
 	}
 
@@ -630,6 +643,18 @@ func (web *Web) alliancesPdfReportHandler(w http.ResponseWriter, r *http.Request
 			}
 		}
 	})
+	if roundRobin := web.arena.PlayoffBracket.RoundRobin; roundRobin != nil {
+		// Alliances that haven't made it into the rest of the bracket are still in, or were eliminated in, the round robin.
+		for _, ranking := range roundRobin.Rankings {
+			if _, ok := allianceStatuses[ranking.AllianceId]; !ok {
+				if roundRobin.IsComplete() {
+					allianceStatuses[ranking.AllianceId] = "Eliminated in\nRound Robin"
+				} else {
+					allianceStatuses[ranking.AllianceId] = "Playing in\nRound Robin"
+				}
+			}
+		}
+	}
 
 	teams, err := web.arena.Database.GetAllTeams()
 	if err != nil {
@@ -642,7 +667,9 @@ func (web *Web) alliancesPdfReportHandler(w http.ResponseWriter, r *http.Request
 	}
 
 	// The widths of the table columns in mm, stored here so that they can be referenced for each row.
-	colWidths := map[string]float64{"Alliance": 23, "Id": 12, "Name": 80, "Location": 80}
+	colWidths := map[string]float64{
+		"Alliance": 23, "Id": 12, "Name": 58, "Location": 54, "Timeouts": 24, "Backups": 24,
+	}
 	rowHeight := 6.5
 
 	pdf := gofpdf.New("P", "mm", "Letter", "font")
@@ -655,7 +682,9 @@ func (web *Web) alliancesPdfReportHandler(w http.ResponseWriter, r *http.Request
 	pdf.CellFormat(colWidths["Alliance"], rowHeight, "Alliance", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["Id"], rowHeight, "Team", "1", 0, "C", true, 0, "")
 	pdf.CellFormat(colWidths["Name"], rowHeight, "Name", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(colWidths["Location"], rowHeight, "Location", "1", 1, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Location"], rowHeight, "Location", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Timeouts"], rowHeight, "Timeouts Used", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(colWidths["Backups"], rowHeight, "Backups Used", "1", 1, "C", true, 0, "")
 	pdf.SetFont("Arial", "", 10)
 	xStart := pdf.GetX()
 	for _, alliance := range alliances {
@@ -677,6 +706,26 @@ func (web *Web) alliancesPdfReportHandler(w http.ResponseWriter, r *http.Request
 			location := fmt.Sprintf("%s, %s, %s", team.City, team.StateProv, team.Country)
 			pdf.CellFormat(colWidths["Location"], rowHeight, location, "1", 1, "L", false, 0, "")
 		}
+
+		// Render the alliance's timeout and backup usage alongside all of its teams.
+		yEnd := pdf.GetY()
+		pdf.SetXY(xStart+colWidths["Alliance"]+colWidths["Id"]+colWidths["Name"]+colWidths["Location"], yStart)
+		timeoutsUsed := fmt.Sprintf("%d of %d", alliance.TimeoutsUsed, model.AllianceTimeoutAllowance)
+		pdf.CellFormat(colWidths["Timeouts"], yEnd-yStart, timeoutsUsed, "1", 0, "C", false, 0, "")
+		backupsUsed := fmt.Sprintf("%d of %d", alliance.BackupsUsed(), model.AllianceBackupAllowance)
+		pdf.CellFormat(colWidths["Backups"], yEnd-yStart, backupsUsed, "1", 1, "C", false, 0, "")
+		pdf.SetY(yEnd)
+
+		if len(alliance.DeclinedTeamIds) > 0 {
+			declinedTeams := make([]string, len(alliance.DeclinedTeamIds))
+			for i, teamId := range alliance.DeclinedTeamIds {
+				declinedTeams[i] = strconv.Itoa(teamId)
+			}
+			pdf.SetFont("Arial", "I", 9)
+			declinedText := fmt.Sprintf("Invitation declined by: %s", strings.Join(declinedTeams, ", "))
+			pdf.CellFormat(195, rowHeight, declinedText, "1", 1, "L", false, 0, "")
+			pdf.SetFont("Arial", "", 10)
+		}
 	}
 
 	addTimeGeneratedFooter(pdf)
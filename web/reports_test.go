@@ -148,4 +148,22 @@ func TestBracketPdfReport(t *testing.T) {
 	assert.Equal(t, 200, recorder.Code)
 	assert.Equal(t, "text/html; charset=utf-8", recorder.Header()["Content-Type"][0])
 	assert.Contains(t, recorder.Body.String(), "Finals")
+
+	web.arena.EventSettings.ElimType = "double"
+	web.arena.EventSettings.NumElimAlliances = 4
+	tournament.CreateTestAlliances(web.arena.Database, 4)
+	assert.Nil(t, web.arena.CreatePlayoffBracket())
+	recorder = web.getHttpResponse("/reports/pdf/bracket")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `id="match_4_2"`)
+	assert.Contains(t, recorder.Body.String(), "L 8")
+	assert.NotContains(t, recorder.Body.String(), ">TO ")
+
+	// Alliances that have used their timeout or backup are marked as such.
+	alliance, _ := web.arena.Database.GetAllianceById(3)
+	alliance.TimeoutsUsed = 1
+	alliance.BackupTeamIds = []int{1503}
+	assert.Nil(t, web.arena.Database.UpdateAlliance(alliance))
+	recorder = web.getHttpResponse("/reports/pdf/bracket")
+	assert.Contains(t, recorder.Body.String(), ">TO BU<")
 }
@@ -10,10 +10,15 @@ API Docs
 JSON Schema:
 
 {
-   “red”: {“auto”: 99, “teleop”: 99, “endgame": 99},
-   “blue”: {“auto”: 99, “teleop”: 99, “endgame": 99}
+   "red": {"<element ID>": 99, ...},
+   "blue": {"<element ID>": 99, ...}
 }
 
+Each key is the ID of a scoring element in the event's game definition and each value is the number of times the
+alliance has been credited with that element. Using the default game definition, the elements are "auto", "teleop"
+and "endgame", each worth one point. The reserved keys "fouls" and "techFouls" hold the number of fouls and tech fouls
+committed by the alliance, the points for which are awarded to the opposing alliance.
+
 GET http://10.0.100.5/api/scores
 
 Returns current score.
@@ -26,7 +31,7 @@ parts are optional. Anything missing is set to zero.
 Example:
 
 {
-   “red”: {“auto”: 10}
+   "red": {"auto": 10}
 }
 
 Red teleop and endgame are set to zero as well as all blue scores.
@@ -40,30 +45,36 @@ request body are left untouched.
 Example:
 
 {
-   “red”: {“auto”: 10},
+   "red": {"auto": 10},
    "blue": {"teleop": -5}
 }
 
 10 is added to red auto. Red teleop and endgame are left untouched.
 5 is subtracted from blue teleop. Blue auto and endgame are left untouched.
 
+Requests referencing an element that is not in the game definition, or that would leave a count negative or above
+the element's maximum, are rejected with a 400 status.
+
 */
 
 package web
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/FRCTeam1987/crimson-arena/field"
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"io/ioutil"
 	"net/http"
 )
 
-type jsonAllianceScore struct {
-	Auto    int `json:"auto"`
-	Teleop  int `json:"teleop"`
-	Endgame int `json:"endgame"`
-}
+// Keys in the alliance score object that hold foul counts rather than scoring element counts.
+const (
+	foulsKey     = "fouls"
+	techFoulsKey = "techFouls"
+)
+
+type jsonAllianceScore map[string]int
 
 type jsonScore struct {
 	Red  jsonAllianceScore `json:"red"`
@@ -71,18 +82,14 @@ type jsonScore struct {
 }
 
 func (web *Web) getScoresHandler(w http.ResponseWriter, r *http.Request) {
-	json.NewEncoder(w).Encode(jsonScore{
-		Red: jsonAllianceScore{
-			Auto:    web.arena.RedScore.AutoPoints,
-			Teleop:  web.arena.RedScore.TeleopPoints,
-			Endgame: web.arena.RedScore.EndgamePoints,
-		},
-		Blue: jsonAllianceScore{
-			Auto:    web.arena.BlueScore.AutoPoints,
-			Teleop:  web.arena.BlueScore.TeleopPoints,
-			Endgame: web.arena.BlueScore.EndgamePoints,
-		},
-	})
+	scores := jsonScore{Red: jsonAllianceScore{}, Blue: jsonAllianceScore{}}
+	for _, element := range game.CurrentGame.ScoringElements {
+		scores.Red[element.Id] = web.arena.RedScore.Count(element.Id)
+		scores.Blue[element.Id] = web.arena.BlueScore.Count(element.Id)
+	}
+	scores.Red[foulsKey], scores.Red[techFoulsKey] = web.arena.RedScore.Fouls, web.arena.RedScore.TechFouls
+	scores.Blue[foulsKey], scores.Blue[techFoulsKey] = web.arena.BlueScore.Fouls, web.arena.BlueScore.TechFouls
+	json.NewEncoder(w).Encode(scores)
 }
 
 func (web *Web) setScoresHandler(w http.ResponseWriter, r *http.Request) {
@@ -100,16 +107,57 @@ func (web *Web) setScoresHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	json.Unmarshal(reqBody, &scores)
 
+	redScore, blueScore := web.arena.RedScore, web.arena.BlueScore
 	if r.Method == "PUT" {
-		web.arena.RedScore = new(game.Score)
-		web.arena.BlueScore = new(game.Score)
+		redScore = new(game.Score)
+		blueScore = new(game.Score)
+	}
+	if redScore, err = applyScoreCounts(redScore, scores.Red); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if blueScore, err = applyScoreCounts(blueScore, scores.Blue); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	web.arena.RedScore.AutoPoints += scores.Red.Auto
-	web.arena.RedScore.TeleopPoints += scores.Red.Teleop
-	web.arena.RedScore.EndgamePoints += scores.Red.Endgame
-	web.arena.BlueScore.AutoPoints += scores.Blue.Auto
-	web.arena.BlueScore.TeleopPoints += scores.Blue.Teleop
-	web.arena.BlueScore.EndgamePoints += scores.Blue.Endgame
+	web.arena.RedScore = redScore
+	web.arena.BlueScore = blueScore
 	web.arena.RealtimeScoreNotifier.Notify()
 }
+
+// Returns a copy of the given score with the given element and foul counts added to it, or an error if any of the
+// elements aren't part of the current game definition or the resulting count would be out of range.
+func applyScoreCounts(score *game.Score, counts map[string]int) (*game.Score, error) {
+	updatedScore := &game.Score{Fouls: score.Fouls, TechFouls: score.TechFouls, PlayoffDq: score.PlayoffDq}
+	for elementId, count := range score.ElementCounts {
+		updatedScore.SetCount(elementId, count)
+	}
+	for elementId, delta := range counts {
+		switch elementId {
+		case foulsKey:
+			if updatedScore.Fouls += delta; updatedScore.Fouls < 0 {
+				return nil, fmt.Errorf("Foul count cannot be negative.")
+			}
+			continue
+		case techFoulsKey:
+			if updatedScore.TechFouls += delta; updatedScore.TechFouls < 0 {
+				return nil, fmt.Errorf("Tech foul count cannot be negative.")
+			}
+			continue
+		}
+		element := game.CurrentGame.GetElement(elementId)
+		if element == nil {
+			return nil, fmt.Errorf("Invalid scoring element '%s'.", elementId)
+		}
+		count := updatedScore.Count(elementId) + delta
+		if count < 0 {
+			return nil, fmt.Errorf("Count for scoring element '%s' cannot be negative.", elementId)
+		}
+		if element.MaxCount > 0 && count > element.MaxCount {
+			return nil, fmt.Errorf("Count for scoring element '%s' cannot exceed %d.", elementId, element.MaxCount)
+		}
+		updatedScore.SetCount(elementId, count)
+	}
+	return updatedScore, nil
+}
@@ -15,26 +15,18 @@ import (
 func TestGetScores(t *testing.T) {
 	web := setupTestWeb(t)
 
-	score1 := game.TestScore1()
-	score2 := game.TestScore2()
-	web.arena.RedScore.AutoPoints = score1.AutoPoints
-	web.arena.RedScore.TeleopPoints = score1.TeleopPoints
-	web.arena.RedScore.EndgamePoints = score1.EndgamePoints
-	web.arena.BlueScore.AutoPoints = score2.AutoPoints
-	web.arena.BlueScore.TeleopPoints = score2.TeleopPoints
-	web.arena.BlueScore.EndgamePoints = score2.EndgamePoints
+	web.arena.RedScore = game.TestScore1()
+	web.arena.BlueScore = game.TestScore2()
 
 	recorder := web.getHttpResponse("/api/scores")
 	assert.Equal(t, 200, recorder.Code)
 
 	var reqScores jsonScore
 	json.Unmarshal(recorder.Body.Bytes(), &reqScores)
-	assert.Equal(t, score1.AutoPoints, reqScores.Red.Auto)
-	assert.Equal(t, score1.TeleopPoints, reqScores.Red.Teleop)
-	assert.Equal(t, score1.EndgamePoints, reqScores.Red.Endgame)
-	assert.Equal(t, score2.AutoPoints, reqScores.Blue.Auto)
-	assert.Equal(t, score2.TeleopPoints, reqScores.Blue.Teleop)
-	assert.Equal(t, score2.EndgamePoints, reqScores.Blue.Endgame)
+	assert.Equal(t, jsonAllianceScore{"auto": 45, "teleop": 80, "endgame": 30, "fouls": 0, "techFouls": 0},
+		reqScores.Red)
+	assert.Equal(t, jsonAllianceScore{"auto": 15, "teleop": 40, "endgame": 25, "fouls": 0, "techFouls": 0},
+		reqScores.Blue)
 }
 
 func TestPatchScores(t *testing.T) {
@@ -48,35 +40,35 @@ func TestPatchScores(t *testing.T) {
 
 	score1 := game.TestScore1()
 	score2 := game.TestScore2()
-	web.arena.RedScore.AutoPoints = score1.AutoPoints
-	web.arena.RedScore.TeleopPoints = score1.TeleopPoints
-	web.arena.RedScore.EndgamePoints = score1.EndgamePoints
-	web.arena.BlueScore.AutoPoints = score2.AutoPoints
-	web.arena.BlueScore.TeleopPoints = score2.TeleopPoints
-	web.arena.BlueScore.EndgamePoints = score2.EndgamePoints
+	web.arena.RedScore = game.TestScore1()
+	web.arena.BlueScore = game.TestScore2()
 
 	web.arena.MatchState = field.PostMatch
 	recorder = web.patchHttpResponse("/api/scores",
 		"{\"red\":{\"auto\":5,\"teleop\":10,\"endgame\":15}}")
 	assert.Equal(t, 200, recorder.Code)
 
-	assert.Equal(t, score1.AutoPoints+5, web.arena.RedScore.AutoPoints)
-	assert.Equal(t, score1.TeleopPoints+10, web.arena.RedScore.TeleopPoints)
-	assert.Equal(t, score1.EndgamePoints+15, web.arena.RedScore.EndgamePoints)
-	assert.Equal(t, score2.AutoPoints, web.arena.BlueScore.AutoPoints)
-	assert.Equal(t, score2.TeleopPoints, web.arena.BlueScore.TeleopPoints)
-	assert.Equal(t, score2.EndgamePoints, web.arena.BlueScore.EndgamePoints)
+	assert.Equal(t, score1.Count("auto")+5, web.arena.RedScore.Count("auto"))
+	assert.Equal(t, score1.Count("teleop")+10, web.arena.RedScore.Count("teleop"))
+	assert.Equal(t, score1.Count("endgame")+15, web.arena.RedScore.Count("endgame"))
+	assert.True(t, score2.Equals(web.arena.BlueScore))
 
 	recorder = web.patchHttpResponse("/api/scores",
 		"{\"blue\":{\"auto\":-5,\"teleop\":-10,\"endgame\":-15}}")
 	assert.Equal(t, 200, recorder.Code)
 
-	assert.Equal(t, score1.AutoPoints+5, web.arena.RedScore.AutoPoints)
-	assert.Equal(t, score1.TeleopPoints+10, web.arena.RedScore.TeleopPoints)
-	assert.Equal(t, score1.EndgamePoints+15, web.arena.RedScore.EndgamePoints)
-	assert.Equal(t, score2.AutoPoints-5, web.arena.BlueScore.AutoPoints)
-	assert.Equal(t, score2.TeleopPoints-10, web.arena.BlueScore.TeleopPoints)
-	assert.Equal(t, score2.EndgamePoints-15, web.arena.BlueScore.EndgamePoints)
+	assert.Equal(t, score1.Count("auto")+5, web.arena.RedScore.Count("auto"))
+	assert.Equal(t, score1.Count("teleop")+10, web.arena.RedScore.Count("teleop"))
+	assert.Equal(t, score1.Count("endgame")+15, web.arena.RedScore.Count("endgame"))
+	assert.Equal(t, score2.Count("auto")-5, web.arena.BlueScore.Count("auto"))
+	assert.Equal(t, score2.Count("teleop")-10, web.arena.BlueScore.Count("teleop"))
+	assert.Equal(t, score2.Count("endgame")-15, web.arena.BlueScore.Count("endgame"))
+
+	// Check that the counts can't be made negative.
+	recorder = web.patchHttpResponse("/api/scores", "{\"blue\":{\"auto\":-100}}")
+	assert.Equal(t, 400, recorder.Code)
+	assert.Equal(t, "Count for scoring element 'auto' cannot be negative.\n", recorder.Body.String())
+	assert.Equal(t, score2.Count("auto")-5, web.arena.BlueScore.Count("auto"))
 }
 
 func TestPutScores(t *testing.T) {
@@ -88,35 +80,83 @@ func TestPutScores(t *testing.T) {
 	assert.Equal(t, 400, recorder.Code)
 	assert.Equal(t, "Score cannot be updated in this match state\n", recorder.Body.String())
 
-	score1 := game.TestScore1()
-	score2 := game.TestScore2()
-	web.arena.RedScore.AutoPoints = score1.AutoPoints
-	web.arena.RedScore.TeleopPoints = score1.TeleopPoints
-	web.arena.RedScore.EndgamePoints = score1.EndgamePoints
-	web.arena.BlueScore.AutoPoints = score2.AutoPoints
-	web.arena.BlueScore.TeleopPoints = score2.TeleopPoints
-	web.arena.BlueScore.EndgamePoints = score2.EndgamePoints
+	web.arena.RedScore = game.TestScore1()
+	web.arena.BlueScore = game.TestScore2()
 
 	web.arena.MatchState = field.PostMatch
 	recorder = web.putHttpResponse("/api/scores",
 		"{\"red\":{\"auto\":5,\"teleop\":10,\"endgame\":15}}")
 	assert.Equal(t, 200, recorder.Code)
 
-	assert.Equal(t, 5, web.arena.RedScore.AutoPoints)
-	assert.Equal(t, 10, web.arena.RedScore.TeleopPoints)
-	assert.Equal(t, 15, web.arena.RedScore.EndgamePoints)
-	assert.Equal(t, 0, web.arena.BlueScore.AutoPoints)
-	assert.Equal(t, 0, web.arena.BlueScore.TeleopPoints)
-	assert.Equal(t, 0, web.arena.BlueScore.EndgamePoints)
+	assert.Equal(t, 5, web.arena.RedScore.Count("auto"))
+	assert.Equal(t, 10, web.arena.RedScore.Count("teleop"))
+	assert.Equal(t, 15, web.arena.RedScore.Count("endgame"))
+	assert.Equal(t, 0, web.arena.BlueScore.Count("auto"))
+	assert.Equal(t, 0, web.arena.BlueScore.Count("teleop"))
+	assert.Equal(t, 0, web.arena.BlueScore.Count("endgame"))
 
 	recorder = web.putHttpResponse("/api/scores",
 		"{\"blue\":{\"auto\":5,\"teleop\":10,\"endgame\":15}}")
 	assert.Equal(t, 200, recorder.Code)
 
-	assert.Equal(t, 0, web.arena.RedScore.AutoPoints)
-	assert.Equal(t, 0, web.arena.RedScore.TeleopPoints)
-	assert.Equal(t, 0, web.arena.RedScore.EndgamePoints)
-	assert.Equal(t, 5, web.arena.BlueScore.AutoPoints)
-	assert.Equal(t, 10, web.arena.BlueScore.TeleopPoints)
-	assert.Equal(t, 15, web.arena.BlueScore.EndgamePoints)
+	assert.Equal(t, 0, web.arena.RedScore.Count("auto"))
+	assert.Equal(t, 0, web.arena.RedScore.Count("teleop"))
+	assert.Equal(t, 0, web.arena.RedScore.Count("endgame"))
+	assert.Equal(t, 5, web.arena.BlueScore.Count("auto"))
+	assert.Equal(t, 10, web.arena.BlueScore.Count("teleop"))
+	assert.Equal(t, 15, web.arena.BlueScore.Count("endgame"))
+}
+
+func TestSetScoresCustomGame(t *testing.T) {
+	web := setupTestWeb(t)
+	game.CurrentGame = &game.GameDefinition{
+		Name: "Test Game",
+		ScoringElements: []game.ScoringElement{
+			{Id: "leave", Name: "Leave", Period: game.AutoPeriod, Points: 2, MaxCount: 3},
+			{Id: "note", Name: "Note", Period: game.TeleopPeriod, Points: 5},
+		},
+	}
+	defer func() { game.CurrentGame = game.DefaultGameDefinition() }()
+
+	web.arena.MatchState = field.TeleopPeriod
+	recorder := web.putHttpResponse("/api/scores", "{\"red\":{\"leave\":2,\"note\":7}}")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, 39, web.arena.RedScore.Summarize(web.arena.BlueScore).Score)
+
+	recorder = web.patchHttpResponse("/api/scores", "{\"red\":{\"leave\":2}}")
+	assert.Equal(t, 400, recorder.Code)
+	assert.Equal(t, "Count for scoring element 'leave' cannot exceed 3.\n", recorder.Body.String())
+
+	recorder = web.patchHttpResponse("/api/scores", "{\"blue\":{\"auto\":2}}")
+	assert.Equal(t, 400, recorder.Code)
+	assert.Equal(t, "Invalid scoring element 'auto'.\n", recorder.Body.String())
+	assert.Equal(t, 39, web.arena.RedScore.Summarize(web.arena.BlueScore).Score)
+
+	recorder = web.getHttpResponse("/api/scores")
+	var reqScores jsonScore
+	json.Unmarshal(recorder.Body.Bytes(), &reqScores)
+	assert.Equal(t, jsonAllianceScore{"leave": 2, "note": 7, "fouls": 0, "techFouls": 0}, reqScores.Red)
+	assert.Equal(t, jsonAllianceScore{"leave": 0, "note": 0, "fouls": 0, "techFouls": 0}, reqScores.Blue)
+}
+
+func TestSetScoresFouls(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.MatchState = field.TeleopPeriod
+	recorder := web.putHttpResponse("/api/scores", "{\"red\":{\"teleop\":10,\"fouls\":2},\"blue\":{\"techFouls\":1}}")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, 2, web.arena.RedScore.Fouls)
+	assert.Equal(t, 1, web.arena.BlueScore.TechFouls)
+	assert.Equal(t, 22, web.arena.RedScore.Summarize(web.arena.BlueScore).Score)
+	assert.Equal(t, 10, web.arena.BlueScore.Summarize(web.arena.RedScore).Score)
+
+	recorder = web.patchHttpResponse("/api/scores", "{\"red\":{\"fouls\":-1}}")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, 1, web.arena.RedScore.Fouls)
+	assert.Equal(t, 10, web.arena.RedScore.Count("teleop"))
+
+	recorder = web.patchHttpResponse("/api/scores", "{\"blue\":{\"techFouls\":-2}}")
+	assert.Equal(t, 400, recorder.Code)
+	assert.Equal(t, "Tech foul count cannot be negative.\n", recorder.Body.String())
+	assert.Equal(t, 1, web.arena.BlueScore.TechFouls)
 }
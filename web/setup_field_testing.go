@@ -7,9 +7,11 @@ package web
 
 import (
 	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/field"
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/FRCTeam1987/crimson-arena/websocket"
+	"github.com/mitchellh/mapstructure"
 	"io"
 	"log"
 	"net/http"
@@ -33,7 +35,15 @@ func (web *Web) fieldTestingGetHandler(w http.ResponseWriter, r *http.Request) {
 		InputNames    []string
 		RegisterNames []string
 		CoilNames     []string
-	}{web.arena.EventSettings, game.MatchSounds, plc.GetInputNames(), plc.GetRegisterNames(), plc.GetCoilNames()}
+		Stations      []string
+	}{
+		web.arena.EventSettings,
+		game.MatchSounds,
+		plc.GetInputNames(),
+		plc.GetRegisterNames(),
+		plc.GetCoilNames(),
+		[]string{"R1", "R2", "R3", "B1", "B2", "B3"},
+	}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -55,7 +65,8 @@ func (web *Web) fieldTestingWebsocketHandler(w http.ResponseWriter, r *http.Requ
 	defer ws.Close()
 
 	// Subscribe the websocket to the notifiers whose messages will be passed on to the client, in a separate goroutine.
-	go ws.HandleNotifiers(web.arena.Plc.IoChangeNotifier)
+	go ws.HandleNotifiers(web.arena.Plc.IoChangeNotifier, web.arena.DriverStationSimulatorNotifier,
+		web.arena.ArenaStatusNotifier)
 
 	// Loop, waiting for commands and responding to them, until the client closes the connection.
 	for {
@@ -77,6 +88,32 @@ func (web *Web) fieldTestingWebsocketHandler(w http.ResponseWriter, r *http.Requ
 				continue
 			}
 			web.arena.PlaySoundNotifier.NotifyWithMessage(sound)
+		case "setDriverStationSimulatorEnabled":
+			enabled, ok := data.(bool)
+			if !ok {
+				ws.WriteError(fmt.Sprintf("Failed to parse '%s' message.", messageType))
+				continue
+			}
+			web.arena.SetDriverStationSimulatorEnabled(enabled)
+		case "setSimulatedRobot":
+			args := struct {
+				Station        string
+				RobotLinked    bool
+				BatteryVoltage float64
+				TripTimeMs     int
+			}{}
+			err = mapstructure.Decode(data, &args)
+			if err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
+			robot := field.SimulatedRobot{
+				RobotLinked: args.RobotLinked, BatteryVoltage: args.BatteryVoltage, TripTimeMs: args.TripTimeMs,
+			}
+			if err = web.arena.SetSimulatedRobot(args.Station, robot); err != nil {
+				ws.WriteError(err.Error())
+				continue
+			}
 		default:
 			ws.WriteError(fmt.Sprintf("Invalid message type '%s'.", messageType))
 			continue
@@ -4,7 +4,9 @@
 package web
 
 import (
+	"github.com/FRCTeam1987/crimson-arena/field"
 	"github.com/FRCTeam1987/crimson-arena/game"
+	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/FRCTeam1987/crimson-arena/websocket"
 	gorillawebsocket "github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +35,8 @@ func TestSetupFieldTestingWebsocket(t *testing.T) {
 
 	// Should get a few status updates right after connection.
 	readWebsocketType(t, ws, "plcIoChange")
+	readWebsocketType(t, ws, "driverStationSimulator")
+	readWebsocketType(t, ws, "arenaStatus")
 
 	// Also create a websocket to the audience display to check that it plays the requested game sound.
 	audienceConn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/displays/audience/websocket?displayId=1", nil)
@@ -44,3 +48,52 @@ func TestSetupFieldTestingWebsocket(t *testing.T) {
 	ws.Write("playSound", "resume")
 	assert.Equal(t, "resume", readWebsocketType(t, audienceWs, "playSound"))
 }
+
+func TestSetupFieldTestingDriverStationSimulator(t *testing.T) {
+	web := setupTestWeb(t)
+
+	server, wsUrl := web.startTestServer()
+	defer server.Close()
+	conn, _, err := gorillawebsocket.DefaultDialer.Dial(wsUrl+"/setup/field_testing/websocket", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+	ws := websocket.NewTestWebsocket(conn)
+	readWebsocketMultiple(t, ws, 3)
+
+	// Robot conditions can't be changed until the simulator is running.
+	robot := map[string]any{"Station": "R2", "RobotLinked": false, "BatteryVoltage": 11.2, "TripTimeMs": 35}
+	ws.Write("setSimulatedRobot", robot)
+	assert.Contains(t, readWebsocketError(t, ws), "not running")
+
+	web.arena.LoadMatch(&model.Match{Type: "test", Red2: 254, Blue3: 1114})
+	ws.Write("setDriverStationSimulatorEnabled", true)
+	message := readWebsocketType(t, ws, "driverStationSimulator").(map[string]any)
+	assert.Equal(t, true, message["Enabled"])
+	if assert.NotNil(t, web.arena.DriverStationSimulator) {
+		assert.Equal(t, []int{254, 1114}, web.arena.DriverStationSimulator.Teams())
+	}
+
+	ws.Write("setSimulatedRobot", robot)
+	message = readWebsocketType(t, ws, "driverStationSimulator").(map[string]any)
+	stations := message["Stations"].(map[string]any)
+	assert.Equal(t, 11.2, stations["R2"].(map[string]any)["BatteryVoltage"])
+	assert.Equal(t, 254.0, stations["R2"].(map[string]any)["TeamId"])
+	assert.Contains(t, stations, "B3")
+	simulatedRobot, _ := web.arena.DriverStationSimulator.GetRobot(254)
+	assert.Equal(t, field.SimulatedRobot{RobotLinked: false, BatteryVoltage: 11.2, TripTimeMs: 35}, simulatedRobot)
+
+	robot["Station"] = "R1"
+	ws.Write("setSimulatedRobot", robot)
+	assert.Contains(t, readWebsocketError(t, ws), "no team is assigned to station R1")
+
+	// The simulator should follow the teams as the next match is loaded.
+	web.arena.LoadMatch(&model.Match{Type: "test", Red1: 148})
+	assert.Equal(t, []int{148}, web.arena.DriverStationSimulator.Teams())
+	message = readWebsocketType(t, ws, "driverStationSimulator").(map[string]any)
+	assert.Contains(t, message["Stations"], "R1")
+
+	ws.Write("setDriverStationSimulatorEnabled", false)
+	message = readWebsocketType(t, ws, "driverStationSimulator").(map[string]any)
+	assert.Equal(t, false, message["Enabled"])
+	assert.Nil(t, web.arena.DriverStationSimulator)
+}
@@ -7,8 +7,10 @@ package web
 
 import (
 	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/field"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/FRCTeam1987/crimson-arena/tournament"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -17,6 +19,12 @@ import (
 // Global vars to hold schedules that are in the process of being generated.
 var cachedMatches = make(map[string][]model.Match)
 var cachedTeamFirstMatches = make(map[string]map[int]string)
+var cachedScheduleCandidates = make(map[string][][]model.Match)
+var cachedSelectedCandidates = make(map[string]int)
+
+// Schedule blocks that each cached candidate was laid out in, if they should replace the saved ones once the candidate
+// is saved.
+var cachedCandidateScheduleBlocks = make(map[string][][]model.ScheduleBlock)
 
 // Shows the schedule editing page.
 func (web *Web) scheduleGetHandler(w http.ResponseWriter, r *http.Request) {
@@ -71,6 +79,7 @@ func (web *Web) scheduleGeneratePostHandler(w http.ResponseWriter, r *http.Reque
 		handleWebErr(w, err)
 		return
 	}
+	teams = getSchedulableTeams(teams)
 	if len(teams) == 0 {
 		web.renderSchedule(w, r, "No team list is configured. Set up the list of teams at the event before "+
 			"generating the schedule.")
@@ -86,9 +95,83 @@ func (web *Web) scheduleGeneratePostHandler(w http.ResponseWriter, r *http.Reque
 		web.renderSchedule(w, r, fmt.Sprintf("Error generating schedule: %s.", err.Error()))
 		return
 	}
+
+	// Keep the previously generated schedules around for comparison if requested.
+	if r.PostFormValue("addCandidate") != "true" {
+		cachedScheduleCandidates[matchType] = nil
+		cachedCandidateScheduleBlocks[matchType] = nil
+	}
+	cachedScheduleCandidates[matchType] = append(cachedScheduleCandidates[matchType], matches)
+	cachedCandidateScheduleBlocks[matchType] = append(cachedCandidateScheduleBlocks[matchType], nil)
+	selectScheduleCandidate(matchType, len(cachedScheduleCandidates[matchType])-1)
+
+	http.Redirect(w, r, "/setup/schedule?matchType="+matchType, 303)
+}
+
+// Imports a schedule produced by another tool and presents it for review without saving it. The schedule blocks it was
+// laid out in replace the saved ones only once it is saved.
+func (web *Web) scheduleImportPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	matchType := getMatchType(r)
+	file, _, err := r.FormFile("scheduleFile")
+	if err != nil {
+		web.renderSchedule(w, r, "No schedule file was specified.")
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	teams, err := web.arena.Database.GetAllTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	matches, err := tournament.ParseSchedule(data, matchType)
+	if err == nil {
+		err = tournament.ValidateSchedule(matches, getSchedulableTeams(teams))
+	}
+	if err != nil {
+		web.renderSchedule(w, r, fmt.Sprintf("Error importing schedule: %s.", err.Error()))
+		return
+	}
+
+	cachedScheduleCandidates[matchType] = [][]model.Match{matches}
+	cachedCandidateScheduleBlocks[matchType] = [][]model.ScheduleBlock{
+		tournament.GetScheduleBlocks(matches, field.MaxMatchGapMin*time.Minute),
+	}
+	selectScheduleCandidate(matchType, 0)
+
+	http.Redirect(w, r, "/setup/schedule?matchType="+matchType, 303)
+}
+
+// Chooses which of the generated candidate schedules will be committed when the schedule is saved.
+func (web *Web) scheduleSelectPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	matchType := getMatchType(r)
+	candidate, err := strconv.Atoi(r.PostFormValue("candidate"))
+	if err != nil || candidate < 0 || candidate >= len(cachedScheduleCandidates[matchType]) {
+		web.renderSchedule(w, r, "Invalid candidate schedule selected.")
+		return
+	}
+	selectScheduleCandidate(matchType, candidate)
+
+	http.Redirect(w, r, "/setup/schedule?matchType="+matchType, 303)
+}
+
+// Makes the given candidate schedule the one under review, and determines each team's first match within it.
+func selectScheduleCandidate(matchType string, candidate int) {
+	matches := cachedScheduleCandidates[matchType][candidate]
 	cachedMatches[matchType] = matches
+	cachedSelectedCandidates[matchType] = candidate
 
-	// Determine each team's first match.
 	teamFirstMatches := make(map[int]string)
 	for _, match := range matches {
 		checkTeam := func(team int) {
@@ -105,6 +188,124 @@ func (web *Web) scheduleGeneratePostHandler(w http.ResponseWriter, r *http.Reque
 		checkTeam(match.Blue3)
 	}
 	cachedTeamFirstMatches[matchType] = teamFirstMatches
+}
+
+// Rebuilds the unplayed matches of the saved schedule for the current team list, leaving completed matches intact.
+func (web *Web) scheduleReschedulePostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	matchType := getMatchType(r)
+	if web.arena.MatchState != field.PreMatch && web.arena.CurrentMatch.Type == matchType {
+		web.renderSchedule(w, r, "Can't reschedule the remaining matches while a match is in progress or has results "+
+			"pending.")
+		return
+	}
+	existingMatches, err := web.arena.Database.GetMatchesByType(matchType)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	allTeams, err := web.arena.Database.GetAllTeams()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+
+	// Apply the requested changes to the team list, but only persist them once the new schedule is known to work.
+	teamsById := make(map[int]*model.Team, len(allTeams))
+	for i := range allTeams {
+		teamsById[allTeams[i].Id] = &allTeams[i]
+	}
+	var newTeamIds []int
+	var changedTeams []*model.Team
+	for _, teamId := range parseTeamNumbers(r.PostFormValue("addTeams")) {
+		if team, ok := teamsById[teamId]; ok {
+			if !team.Withdrawn {
+				web.renderSchedule(w, r, fmt.Sprintf("Team %d is already part of the event.", teamId))
+				return
+			}
+			team.Withdrawn = false
+			changedTeams = append(changedTeams, team)
+		} else {
+			newTeamIds = append(newTeamIds, teamId)
+		}
+	}
+	for _, teamId := range parseTeamNumbers(r.PostFormValue("withdrawTeams")) {
+		team, ok := teamsById[teamId]
+		if !ok {
+			web.renderSchedule(w, r, fmt.Sprintf("Team %d is not part of the event.", teamId))
+			return
+		}
+		team.Withdrawn = true
+		changedTeams = append(changedTeams, team)
+	}
+	teams := getSchedulableTeams(allTeams)
+	for _, teamId := range newTeamIds {
+		teams = append(teams, model.Team{Id: teamId})
+	}
+
+	remainingMatches, err := tournament.BuildRemainingSchedule(teams, existingMatches)
+	if err != nil {
+		web.renderSchedule(w, r, fmt.Sprintf("Error rescheduling remaining matches: %s.", err.Error()))
+		return
+	}
+
+	// Back up the database before replacing the unplayed matches.
+	err = web.arena.Database.Backup(web.arena.EventSettings.Name, "pre_reschedule")
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	for _, teamId := range newTeamIds {
+		if err = web.createTeam(teamId); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+	for _, team := range changedTeams {
+		if err = web.arena.Database.UpdateTeam(team); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+	for _, match := range existingMatches {
+		if !match.IsComplete() {
+			if err = web.arena.Database.DeleteMatch(match.Id); err != nil {
+				handleWebErr(w, err)
+				return
+			}
+		}
+	}
+	for _, match := range remainingMatches {
+		if err = web.arena.Database.CreateMatch(&match); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	// Replace the loaded match if it was one of the unplayed matches that no longer exists.
+	if web.arena.CurrentMatch.Type == matchType && !web.arena.CurrentMatch.IsComplete() {
+		if err = web.arena.LoadNextMatch(); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
+	if web.arena.EventSettings.TbaPublishingEnabled && matchType != "practice" {
+		// Publish the revised schedule to The Blue Alliance.
+		err = web.arena.TbaClient.DeletePublishedMatches()
+		if err != nil {
+			http.Error(w, "Failed to delete published matches: "+err.Error(), 500)
+			return
+		}
+		err = web.arena.TbaClient.PublishMatches(web.arena.Database)
+		if err != nil {
+			http.Error(w, "Failed to publish matches: "+err.Error(), 500)
+			return
+		}
+	}
 
 	http.Redirect(w, r, "/setup/schedule?matchType="+matchType, 303)
 }
@@ -149,6 +350,21 @@ func (web *Web) scheduleSavePostHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if candidate := cachedSelectedCandidates[matchType]; candidate < len(cachedCandidateScheduleBlocks[matchType]) &&
+		cachedCandidateScheduleBlocks[matchType][candidate] != nil {
+		// Replace the schedule blocks with the ones an imported schedule was laid out in.
+		if err = web.arena.Database.DeleteScheduleBlocksByMatchType(matchType); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+		for _, block := range cachedCandidateScheduleBlocks[matchType][candidate] {
+			if err = web.arena.Database.CreateScheduleBlock(&block); err != nil {
+				handleWebErr(w, err)
+				return
+			}
+		}
+	}
+
 	for _, match := range cachedMatches[matchType] {
 		err = web.arena.Database.CreateMatch(&match)
 		if err != nil {
@@ -194,6 +410,28 @@ func (web *Web) renderSchedule(w http.ResponseWriter, r *http.Request, errorMess
 		handleWebErr(w, err)
 		return
 	}
+
+	// Evaluate the saved schedule, if any, alongside the generated candidates so that they can be compared.
+	savedMatches, err := web.arena.Database.GetMatchesByType(matchType)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	var savedEvaluation *tournament.ScheduleEvaluation
+	numUnplayed := 0
+	if len(savedMatches) > 0 {
+		savedEvaluation = tournament.EvaluateSchedule(savedMatches)
+		for _, match := range savedMatches {
+			if !match.IsComplete() {
+				numUnplayed++
+			}
+		}
+	}
+	var candidateEvaluations []*tournament.ScheduleEvaluation
+	for _, candidate := range cachedScheduleCandidates[matchType] {
+		candidateEvaluations = append(candidateEvaluations, tournament.EvaluateSchedule(candidate))
+	}
+
 	template, err := web.parseFiles("templates/setup_schedule.html", "templates/base.html")
 	if err != nil {
 		handleWebErr(w, err)
@@ -201,14 +439,19 @@ func (web *Web) renderSchedule(w http.ResponseWriter, r *http.Request, errorMess
 	}
 	data := struct {
 		*model.EventSettings
-		MatchType        string
-		ScheduleBlocks   []model.ScheduleBlock
-		NumTeams         int
-		Matches          []model.Match
-		TeamFirstMatches map[int]string
-		ErrorMessage     string
+		MatchType         string
+		ScheduleBlocks    []model.ScheduleBlock
+		NumTeams          int
+		Matches           []model.Match
+		TeamFirstMatches  map[int]string
+		SavedEvaluation   *tournament.ScheduleEvaluation
+		Candidates        []*tournament.ScheduleEvaluation
+		NumUnplayed       int
+		SelectedCandidate int
+		ErrorMessage      string
 	}{web.arena.EventSettings, matchType, scheduleBlocks, len(teams), cachedMatches[matchType],
-		cachedTeamFirstMatches[matchType], errorMessage}
+		cachedTeamFirstMatches[matchType], savedEvaluation, candidateEvaluations, numUnplayed,
+		cachedSelectedCandidates[matchType], errorMessage}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
@@ -243,6 +486,17 @@ func getScheduleBlocks(r *http.Request) ([]model.ScheduleBlock, error) {
 	return scheduleBlocks, returnErr
 }
 
+// Returns the teams that should be scheduled into matches, leaving out any that have withdrawn from the event.
+func getSchedulableTeams(teams []model.Team) []model.Team {
+	var schedulableTeams []model.Team
+	for _, team := range teams {
+		if !team.Withdrawn {
+			schedulableTeams = append(schedulableTeams, team)
+		}
+	}
+	return schedulableTeams
+}
+
 func getMatchType(r *http.Request) string {
 	if matchType, ok := r.URL.Query()["matchType"]; ok {
 		return matchType[0]
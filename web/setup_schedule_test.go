@@ -4,6 +4,8 @@
 package web
 
 import (
+	"bytes"
+	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -49,6 +51,206 @@ func TestSetupSchedule(t *testing.T) {
 	assert.Equal(t, time.Date(2014, 1, 3, 13, 0, 0, 0, location).Unix(), matches[24].Time.Unix())
 }
 
+func TestSetupScheduleCandidates(t *testing.T) {
+	web := setupTestWeb(t)
+
+	for i := 0; i < 18; i++ {
+		web.arena.Database.CreateTeam(&model.Team{Id: i + 101})
+	}
+	postData := "numScheduleBlocks=1&startTime0=2014-01-01 09:00:00 AM&numMatches0=18&matchSpacingSec0=480&" +
+		"matchType=qualification"
+	recorder := web.postHttpResponse("/setup/schedule/generate", postData)
+	assert.Equal(t, 303, recorder.Code)
+	firstCandidate := cachedMatches["qualification"]
+	recorder = web.postHttpResponse("/setup/schedule/generate", postData+"&addCandidate=true")
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.getHttpResponse("/setup/schedule?matchType=qualification")
+	assert.Contains(t, recorder.Body.String(), "Schedule Quality")
+	assert.Contains(t, recorder.Body.String(), "Candidate 2")
+	assert.NotContains(t, recorder.Body.String(), "Candidate 3")
+	assert.Equal(t, 1, cachedSelectedCandidates["qualification"])
+
+	// Select the first candidate and check that it is the one that gets saved.
+	recorder = web.postHttpResponse("/setup/schedule/select?matchType=qualification", "candidate=0")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, firstCandidate, cachedMatches["qualification"])
+	recorder = web.postHttpResponse("/setup/schedule/select?matchType=qualification", "candidate=2")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid candidate schedule selected.")
+	recorder = web.postHttpResponse("/setup/schedule/save?matchType=qualification", "")
+	assert.Equal(t, 303, recorder.Code)
+	matches, _ := web.arena.Database.GetMatchesByType("qualification")
+	if assert.Equal(t, 18, len(matches)) {
+		assert.Equal(t, firstCandidate[0].Red1, matches[0].Red1)
+		assert.Equal(t, firstCandidate[17].Blue3, matches[17].Blue3)
+	}
+
+	// Generating without keeping candidates starts the comparison over, and the saved schedule is evaluated too.
+	recorder = web.postHttpResponse("/setup/schedule/generate", postData)
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.getHttpResponse("/setup/schedule?matchType=qualification")
+	assert.Contains(t, recorder.Body.String(), "<td>Saved</td>")
+	assert.Contains(t, recorder.Body.String(), "Candidate 1")
+	assert.NotContains(t, recorder.Body.String(), "Candidate 2")
+}
+
+func TestSetupScheduleReschedule(t *testing.T) {
+	web := setupTestWeb(t)
+
+	for i := 0; i < 18; i++ {
+		web.arena.Database.CreateTeam(&model.Team{Id: i + 101})
+	}
+	postData := "numScheduleBlocks=1&startTime0=2014-01-01 09:00:00 AM&numMatches0=30&matchSpacingSec0=360&" +
+		"matchType=qualification"
+	recorder := web.postHttpResponse("/setup/schedule/generate", postData)
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.postHttpResponse("/setup/schedule/save?matchType=qualification", "")
+	assert.Equal(t, 303, recorder.Code)
+	matches, _ := web.arena.Database.GetMatchesByType("qualification")
+	for i := 0; i < 9; i++ {
+		matches[i].Status = game.RedWonMatch
+		web.arena.Database.UpdateMatch(&matches[i])
+	}
+	recorder = web.getHttpResponse("/setup/schedule?matchType=qualification")
+	assert.Contains(t, recorder.Body.String(), "regenerate the 21 unplayed qualification matches")
+
+	// Invalid team list changes.
+	recorder = web.postHttpResponse("/setup/schedule/reschedule?matchType=qualification", "addTeams=101")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 101 is already part of the event.")
+	recorder = web.postHttpResponse("/setup/schedule/reschedule?matchType=qualification", "withdrawTeams=254")
+	assert.Equal(t, 200, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team 254 is not part of the event.")
+	newMatches, _ := web.arena.Database.GetMatchesByType("qualification")
+	assert.Equal(t, matches, newMatches)
+
+	web.arena.EventSettings.TBADownloadEnabled = false
+	recorder = web.postHttpResponse(
+		"/setup/schedule/reschedule?matchType=qualification", "addTeams=201%0D%0A202&withdrawTeams=105",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	team, _ := web.arena.Database.GetTeamById(201)
+	assert.NotNil(t, team)
+	team, _ = web.arena.Database.GetTeamById(105)
+	assert.True(t, team.Withdrawn)
+	newMatches, _ = web.arena.Database.GetMatchesByType("qualification")
+	if assert.Equal(t, 29, len(newMatches)) {
+		assert.Equal(t, matches[:9], newMatches[:9])
+		assert.Equal(t, "10", newMatches[9].DisplayName)
+		assert.Equal(t, matches[9].Time.Unix(), newMatches[9].Time.Unix())
+	}
+	for _, match := range newMatches[9:] {
+		assert.NotContains(t, []int{match.Red1, match.Red2, match.Red3, match.Blue1, match.Blue2, match.Blue3}, 105)
+	}
+}
+
+func TestSetupScheduleImport(t *testing.T) {
+	web := setupTestWeb(t)
+
+	for i := 0; i < 18; i++ {
+		web.arena.Database.CreateTeam(&model.Team{Id: i + 101})
+	}
+	postData := "numScheduleBlocks=2&startTime0=2014-01-01 09:00:00 AM&numMatches0=10&matchSpacingSec0=480&" +
+		"startTime1=2014-01-01 01:00:00 PM&numMatches1=8&matchSpacingSec1=420&matchType=qualification"
+	recorder := web.postHttpResponse("/setup/schedule/generate", postData)
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.postHttpResponse("/setup/schedule/save?matchType=qualification", "")
+	assert.Equal(t, 303, recorder.Code)
+	generatedMatches, _ := web.arena.Database.GetMatchesByType("qualification")
+
+	// Re-import the schedule report after clearing the schedule.
+	recorder = web.getHttpResponse("/reports/csv/schedule/qualification")
+	assert.Equal(t, 200, recorder.Code)
+	report := recorder.Body
+	assert.Nil(t, web.arena.Database.TruncateMatches())
+	assert.Nil(t, web.arena.Database.DeleteScheduleBlocksByMatchType("qualification"))
+	recorder = web.postFileHttpResponse("/setup/schedule/import?matchType=qualification", "scheduleFile", report)
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.getHttpResponse("/setup/schedule?matchType=qualification")
+	assert.Contains(t, recorder.Body.String(), "2014-01-01 13:49:00") // Last match of second block.
+	assert.Contains(t, recorder.Body.String(), "Candidate 1")
+	scheduleBlocks, _ := web.arena.Database.GetScheduleBlocksByMatchType("qualification")
+	assert.Empty(t, scheduleBlocks)
+	matches, _ := web.arena.Database.GetMatchesByType("qualification")
+	assert.Empty(t, matches)
+
+	recorder = web.postHttpResponse("/setup/schedule/save?matchType=qualification", "")
+	assert.Equal(t, 303, recorder.Code)
+	scheduleBlocks, _ = web.arena.Database.GetScheduleBlocksByMatchType("qualification")
+	if assert.Equal(t, 2, len(scheduleBlocks)) {
+		assert.Equal(t, 10, scheduleBlocks[0].NumMatches)
+		assert.Equal(t, 480, scheduleBlocks[0].MatchSpacingSec)
+		assert.Equal(t, 8, scheduleBlocks[1].NumMatches)
+		assert.Equal(t, 420, scheduleBlocks[1].MatchSpacingSec)
+	}
+	matches, _ = web.arena.Database.GetMatchesByType("qualification")
+	if assert.Equal(t, len(generatedMatches), len(matches)) {
+		for i := range matches {
+			assert.Equal(t, generatedMatches[i].DisplayName, matches[i].DisplayName)
+			assert.Equal(t, generatedMatches[i].Time.Unix(), matches[i].Time.Unix())
+			assert.Equal(t, generatedMatches[i].Red1, matches[i].Red1)
+			assert.Equal(t, generatedMatches[i].Blue3, matches[i].Blue3)
+			assert.Equal(t, generatedMatches[i].Blue3IsSurrogate, matches[i].Blue3IsSurrogate)
+		}
+	}
+}
+
+func TestSetupScheduleImportErrors(t *testing.T) {
+	web := setupTestWeb(t)
+
+	for i := 0; i < 6; i++ {
+		web.arena.Database.CreateTeam(&model.Team{Id: i + 101})
+	}
+	recorder := web.postHttpResponse("/setup/schedule/import?matchType=practice", "")
+	assert.Contains(t, recorder.Body.String(), "No schedule file was specified.")
+
+	data := "Match,Time,Red1,Red2,Red3,Blue1,Blue2,Blue3\n1,2014-01-01 09:00,101,102,103,104,105,254\n"
+	recorder = web.postFileHttpResponse("/setup/schedule/import?matchType=practice", "scheduleFile",
+		bytes.NewBufferString(data))
+	assert.Contains(t, recorder.Body.String(), "Error importing schedule: Team 254 in match 1 is not part of the event.")
+
+	data = `{"Schedule": [{"matchNumber": 1, "startTime": "2014-01-01T09:00:00", "tournamentLevel": "Qualification"}]}`
+	recorder = web.postFileHttpResponse("/setup/schedule/import?matchType=practice", "scheduleFile",
+		bytes.NewBufferString(data))
+	assert.Contains(t, recorder.Body.String(), "Error importing schedule: Match 1 is a Qualification match rather "+
+		"than a practice match.")
+	assert.Empty(t, cachedMatches["practice"])
+}
+
+func TestSetupScheduleImportKeepsBlocksUntilSaved(t *testing.T) {
+	web := setupTestWeb(t)
+
+	for i := 0; i < 6; i++ {
+		web.arena.Database.CreateTeam(&model.Team{Id: i + 101})
+	}
+	block := model.ScheduleBlock{MatchType: "practice", StartTime: time.Unix(1388566800, 0), NumMatches: 5,
+		MatchSpacingSec: 600}
+	assert.Nil(t, web.arena.Database.CreateScheduleBlock(&block))
+
+	// Importing a schedule and then generating another one instead should leave the entered blocks alone.
+	data := "Match,Time,Red1,Red2,Red3,Blue1,Blue2,Blue3\n1,2014-01-01 09:00,101,102,103,104,105,106\n"
+	recorder := web.postFileHttpResponse("/setup/schedule/import?matchType=practice", "scheduleFile",
+		bytes.NewBufferString(data))
+	assert.Equal(t, 303, recorder.Code)
+	scheduleBlocks, _ := web.arena.Database.GetScheduleBlocksByMatchType("practice")
+	if assert.Equal(t, 1, len(scheduleBlocks)) {
+		assert.Equal(t, 5, scheduleBlocks[0].NumMatches)
+	}
+	postData := "numScheduleBlocks=1&startTime0=2014-01-01 09:00:00 AM&numMatches0=5&matchSpacingSec0=600&" +
+		"matchType=practice"
+	recorder = web.postHttpResponse("/setup/schedule/generate", postData)
+	assert.Equal(t, 303, recorder.Code)
+	recorder = web.postHttpResponse("/setup/schedule/save?matchType=practice", "")
+	assert.Equal(t, 303, recorder.Code)
+	scheduleBlocks, _ = web.arena.Database.GetScheduleBlocksByMatchType("practice")
+	if assert.Equal(t, 1, len(scheduleBlocks)) {
+		assert.Equal(t, 5, scheduleBlocks[0].NumMatches)
+		assert.Equal(t, 600, scheduleBlocks[0].MatchSpacingSec)
+	}
+	matches, _ := web.arena.Database.GetMatchesByType("practice")
+	assert.Equal(t, 5, len(matches))
+}
+
 func TestSetupScheduleErrors(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -69,13 +271,12 @@ func TestSetupScheduleErrors(t *testing.T) {
 	assert.Equal(t, 200, recorder.Code)
 	assert.Contains(t, recorder.Body.String(), "There must be at least 6 teams to generate a schedule.")
 
-	// More matches per team than schedules exist for.
+	// More matches per team than schedule templates exist for, which are generated instead.
 	web.arena.Database.CreateTeam(&model.Team{Id: 118})
 	postData = "numScheduleBlocks=1&startTime0=2014-01-01 09:00:00 AM&numMatches0=700&matchSpacingSec0=480&" +
 		"matchType=practice"
 	recorder = web.postHttpResponse("/setup/schedule/generate", postData)
-	assert.Equal(t, 200, recorder.Code)
-	assert.Contains(t, recorder.Body.String(), "No schedule template exists for 6 teams and 700 matches")
+	assert.Equal(t, 303, recorder.Code)
 
 	// Incomplete scheduling data received.
 	postData = "numScheduleBlocks=1&startTime0=2014-01-01 09:00:00 AM&numMatches0=&matchSpacingSec0=480&" +
@@ -7,16 +7,36 @@ package web
 
 import (
 	"fmt"
+	"github.com/FRCTeam1987/crimson-arena/bracket"
+	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
+	"github.com/FRCTeam1987/crimson-arena/tournament"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// A playoff round as shown on the settings page, with the series lengths that can be chosen for it and the one that is
+// currently in effect.
+type playoffRoundSetting struct {
+	bracket.PlayoffRound
+	Options []int
+	BestOf  int
+}
+
+// The round robin of a playoff type as shown on the settings page, with the settings currently in effect and the ones
+// that the playoff type uses by default.
+type roundRobinSetting struct {
+	bracket.RoundRobinDefinition
+	Default bracket.RoundRobinDefinition
+}
+
 // Shows the event settings editing page.
 func (web *Web) settingsGetHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
@@ -40,22 +60,162 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 		eventSettings.Name = previousEventName
 	}
 	previousAdminPassword := eventSettings.AdminPassword
+	previousElimType := eventSettings.ElimType
 
 	eventSettings.ElimType = r.PostFormValue("elimType")
-	numAlliances := 0
-	if eventSettings.ElimType == "double" {
-		numAlliances = 8
-	} else {
+	bracketDefinition, err := bracket.GetBracketDefinition(eventSettings.ElimType)
+	if err != nil {
+		web.renderSettings(w, r, fmt.Sprintf("Error loading playoff type: %v.", err))
+		return
+	}
+	numAlliances := bracketDefinition.MinAlliances
+	if bracketDefinition.MaxAlliances > bracketDefinition.MinAlliances {
 		numAlliances, _ = strconv.Atoi(r.PostFormValue("numElimAlliances"))
-		if numAlliances < 2 || numAlliances > 16 {
-			web.renderSettings(w, r, "Number of alliances must be between 2 and 16.")
+		if numAlliances < bracketDefinition.MinAlliances || numAlliances > bracketDefinition.MaxAlliances {
+			web.renderSettings(w, r, fmt.Sprintf("Number of alliances must be between %d and %d.",
+				bracketDefinition.MinAlliances, bracketDefinition.MaxAlliances))
+			return
+		}
+	}
+
+	var previousRoundBestOf map[int]int
+	if eventSettings.ElimType == previousElimType {
+		previousRoundBestOf = eventSettings.ElimRoundBestOf
+	}
+	playedRounds, err := web.getPlayedPlayoffRounds()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	elimRoundBestOf := make(map[int]int)
+	for _, round := range getPlayoffRoundSettings(bracketDefinition, previousRoundBestOf) {
+		bestOf := round.DefaultBestOf
+		if value := r.PostFormValue(fmt.Sprintf("bestOf_%s_%d", bracketDefinition.Id, round.Round)); value != "" {
+			bestOf, _ = strconv.Atoi(value)
+			if !slices.Contains(round.Options, bestOf) {
+				web.renderSettings(w, r, fmt.Sprintf("Invalid series length for %s: %s.", round.Name, value))
+				return
+			}
+		}
+		if bestOf != round.BestOf && playedRounds[round.Round] {
+			// Changing the length of a series already under way could decide it or reopen it after the fact.
+			web.renderSettings(w, r, fmt.Sprintf("Can't change the series length for %s once any of its matches "+
+				"have been played.", round.Name))
+			return
+		}
+		if bestOf != round.DefaultBestOf {
+			elimRoundBestOf[round.Round] = bestOf
+		}
+	}
+
+	var roundRobinMatchesPerAlliance int
+	var roundRobinRankBy string
+	if defaultRoundRobin := bracketDefinition.RoundRobin; defaultRoundRobin != nil {
+		previousRoundRobin := *defaultRoundRobin
+		if eventSettings.ElimType == previousElimType {
+			previousRoundRobin = defaultRoundRobin.Override(
+				eventSettings.ElimRoundRobinMatchesPerAlliance, eventSettings.ElimRoundRobinRankBy,
+			)
+		}
+		roundRobin := *defaultRoundRobin
+		if value := r.PostFormValue("roundRobinMatchesPerAlliance_" + bracketDefinition.Id); value != "" {
+			roundRobin.MatchesPerAlliance, _ = strconv.Atoi(value)
+		}
+		if value := r.PostFormValue("roundRobinRankBy_" + bracketDefinition.Id); value != "" {
+			roundRobin.RankBy = value
+		}
+		if err = roundRobin.Validate(); err != nil {
+			web.renderSettings(w, r, fmt.Sprintf("Invalid round robin settings: %v.", err))
+			return
+		}
+		if roundRobin != previousRoundRobin && playedRounds[bracket.RoundRobinRound] {
+			// Changing the round robin once it is under way would reshuffle its matches or its standings.
+			web.renderSettings(w, r, "Can't change the round robin settings once any of its matches have been played.")
 			return
 		}
+		if roundRobin.MatchesPerAlliance != defaultRoundRobin.MatchesPerAlliance {
+			roundRobinMatchesPerAlliance = roundRobin.MatchesPerAlliance
+		}
+		if roundRobin.RankBy != defaultRoundRobin.RankBy {
+			roundRobinRankBy = roundRobin.RankBy
+		}
+	}
+
+	elimMinRestSec, _ := strconv.Atoi(r.PostFormValue("elimMinRestSec"))
+	if elimMinRestSec < 0 {
+		web.renderSettings(w, r, "Minimum playoff rest time cannot be negative.")
+		return
+	}
+
+	elimLineupCutoffSec, _ := strconv.Atoi(r.PostFormValue("elimLineupCutoffSec"))
+	if elimLineupCutoffSec < 0 {
+		web.renderSettings(w, r, "Captain lineup cutoff cannot be negative.")
+		return
+	}
+
+	selectionPickTimeSec, _ := strconv.Atoi(r.PostFormValue("selectionPickTimeSec"))
+	if selectionPickTimeSec < 0 {
+		web.renderSettings(w, r, "Alliance selection pick time cannot be negative.")
+		return
+	}
+
+	retimeCycleTimeSec, _ := strconv.Atoi(r.PostFormValue("retimeCycleTimeSec"))
+	if retimeCycleTimeSec < 0 {
+		web.renderSettings(w, r, "Re-timing cycle time cannot be negative.")
+		return
+	}
+
+	gameSpecificDataRevealSec, _ := strconv.Atoi(r.PostFormValue("gameSpecificDataRevealSec"))
+	if gameSpecificDataRevealSec < 0 {
+		web.renderSettings(w, r, "Game-specific message reveal time cannot be negative.")
+		return
+	}
+	gameSpecificDataOptions := r.PostFormValue("gameSpecificDataOptions")
+	gameSpecificData := model.EventSettings{GameSpecificDataOptions: gameSpecificDataOptions}
+	for _, choice := range gameSpecificData.GameSpecificDataChoices() {
+		if len(choice) > model.MaxGameSpecificDataLength {
+			web.renderSettings(w, r, fmt.Sprintf("Game-specific message '%s' is longer than %d characters.", choice,
+				model.MaxGameSpecificDataLength))
+			return
+		}
+	}
+
+	rankingRules := &game.RankingRules{Tiebreakers: game.ParseTiebreakers(r.PostFormValue("tiebreakers"))}
+	if len(rankingRules.Tiebreakers) == 0 {
+		rankingRules.Tiebreakers = game.DefaultRankingRules().Tiebreakers
+	}
+	rankingRules.WinRankingPoints, _ = strconv.Atoi(r.PostFormValue("winRankingPoints"))
+	rankingRules.TieRankingPoints, _ = strconv.Atoi(r.PostFormValue("tieRankingPoints"))
+	rankingRules.LossRankingPoints, _ = strconv.Atoi(r.PostFormValue("lossRankingPoints"))
+	bonusRankingPoints, err := game.ParseBonusRankingPoints(r.PostFormValue("bonusRankingPoints"))
+	if err == nil {
+		rankingRules.BonusRankingPoints = bonusRankingPoints
+		err = rankingRules.Validate(eventSettings.GameDefinition)
+	}
+	if err != nil {
+		web.renderSettings(w, r, fmt.Sprintf("Invalid ranking rules: %v", err))
+		return
 	}
+	rankingRulesChanged := !reflect.DeepEqual(rankingRules, eventSettings.RankingRules)
+	eventSettings.RankingRules = rankingRules
+
+	playoffTiebreakers := game.ParsePlayoffTiebreakers(r.PostFormValue("playoffTiebreakers"))
+	if err = game.ValidatePlayoffTiebreakers(playoffTiebreakers, eventSettings.GameDefinition); err != nil {
+		web.renderSettings(w, r, fmt.Sprintf("Invalid playoff tiebreakers: %v", err))
+		return
+	}
+	eventSettings.PlayoffTiebreakers = playoffTiebreakers
 
 	eventSettings.NumElimAlliances = numAlliances
+	eventSettings.ElimRoundBestOf = elimRoundBestOf
+	eventSettings.ElimRoundRobinMatchesPerAlliance = roundRobinMatchesPerAlliance
+	eventSettings.ElimRoundRobinRankBy = roundRobinRankBy
+	eventSettings.ElimMinRestSec = elimMinRestSec
+	eventSettings.ElimRestEnforced = r.PostFormValue("elimRestEnforced") == "on"
+	eventSettings.ElimLineupCutoffSec = elimLineupCutoffSec
 	eventSettings.SelectionRound2Order = r.PostFormValue("selectionRound2Order")
 	eventSettings.SelectionRound3Order = r.PostFormValue("selectionRound3Order")
+	eventSettings.SelectionPickTimeSec = selectionPickTimeSec
 	eventSettings.TBADownloadEnabled = r.PostFormValue("TBADownloadEnabled") == "on"
 	eventSettings.TbaPublishingEnabled = r.PostFormValue("tbaPublishingEnabled") == "on"
 	eventSettings.TbaEventCode = r.PostFormValue("tbaEventCode")
@@ -74,8 +234,13 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 	eventSettings.PauseDurationSec, _ = strconv.Atoi(r.PostFormValue("pauseDurationSec"))
 	eventSettings.TeleopDurationSec, _ = strconv.Atoi(r.PostFormValue("teleopDurationSec"))
 	eventSettings.WarningRemainingDurationSec, _ = strconv.Atoi(r.PostFormValue("warningRemainingDurationSec"))
+	eventSettings.RetimeCycleTimeSec = retimeCycleTimeSec
+	eventSettings.AutoRetimeEnabled = r.PostFormValue("autoRetimeEnabled") == "on"
+	eventSettings.GameSpecificDataOptions = gameSpecificDataOptions
+	eventSettings.GameSpecificDataPerAlliance = r.PostFormValue("gameSpecificDataPerAlliance") == "on"
+	eventSettings.GameSpecificDataRevealSec = gameSpecificDataRevealSec
 
-	err := web.arena.Database.UpdateEventSettings(eventSettings)
+	err = web.arena.Database.UpdateEventSettings(eventSettings)
 	if err != nil {
 		handleWebErr(w, err)
 		return
@@ -88,6 +253,14 @@ func (web *Web) settingsPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if rankingRulesChanged {
+		// Re-rank the teams under the new rules.
+		if _, err = tournament.CalculateRankings(web.arena.Database, true); err != nil {
+			handleWebErr(w, err)
+			return
+		}
+	}
+
 	if eventSettings.AdminPassword != previousAdminPassword {
 		// Delete any existing user sessions to force a logout.
 		if err := web.arena.Database.TruncateUserSessions(); err != nil {
@@ -183,6 +356,36 @@ func (web *Web) restoreDbHandler(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/setup/settings", 303)
 }
 
+// Accepts a JSON or YAML game definition file as an upload and makes it the event's scoring model.
+func (web *Web) gameDefinitionPostHandler(w http.ResponseWriter, r *http.Request) {
+	if !web.userIsAdmin(w, r) {
+		return
+	}
+
+	file, _, err := r.FormFile("gameDefinitionFile")
+	if err != nil {
+		web.renderSettings(w, r, "No game definition file was specified.")
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	gameDefinition, err := game.ParseGameDefinition(data)
+	if err != nil {
+		web.renderSettings(w, r, fmt.Sprintf("Invalid game definition: %s", err.Error()))
+		return
+	}
+
+	if err = web.arena.SetGameDefinition(gameDefinition); err != nil {
+		web.renderSettings(w, r, fmt.Sprintf("Unable to use game definition: %s", err.Error()))
+		return
+	}
+
+	http.Redirect(w, r, "/setup/settings", 303)
+}
+
 // Deletes all data except for the team list.
 func (web *Web) clearDbHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
@@ -216,8 +419,11 @@ func (web *Web) clearDbHandler(w http.ResponseWriter, r *http.Request) {
 		handleWebErr(w, err)
 		return
 	}
-	web.arena.AllianceSelectionAlliances = []model.Alliance{}
-	cachedRankedTeams = []*RankedTeam{}
+	web.arena.AllianceSelection, err = web.arena.Database.ResetAllianceSelection()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
 
 	http.Redirect(w, r, "/setup/settings", 303)
 }
@@ -228,13 +434,81 @@ func (web *Web) renderSettings(w http.ResponseWriter, r *http.Request, errorMess
 		handleWebErr(w, err)
 		return
 	}
+	bracketDefinitions, err := bracket.GetBracketDefinitions()
+	if err != nil {
+		handleWebErr(w, err)
+		return
+	}
+	playoffRounds := make(map[string][]playoffRoundSetting, len(bracketDefinitions))
+	roundRobins := make(map[string]*roundRobinSetting)
+	for i, definition := range bracketDefinitions {
+		var elimRoundBestOf map[int]int
+		if definition.Id == web.arena.EventSettings.ElimType {
+			elimRoundBestOf = web.arena.EventSettings.ElimRoundBestOf
+		}
+		playoffRounds[definition.Id] = getPlayoffRoundSettings(&bracketDefinitions[i], elimRoundBestOf)
+
+		if definition.RoundRobin != nil {
+			setting := roundRobinSetting{*definition.RoundRobin, *definition.RoundRobin}
+			if definition.Id == web.arena.EventSettings.ElimType {
+				setting.RoundRobinDefinition = definition.RoundRobin.Override(
+					web.arena.EventSettings.ElimRoundRobinMatchesPerAlliance, web.arena.EventSettings.ElimRoundRobinRankBy,
+				)
+			}
+			roundRobins[definition.Id] = &setting
+		}
+	}
 	data := struct {
 		*model.EventSettings
-		ErrorMessage string
-	}{web.arena.EventSettings, errorMessage}
+		BracketDefinitions []bracket.BracketDefinition
+		PlayoffRounds      map[string][]playoffRoundSetting
+		RoundRobins        map[string]*roundRobinSetting
+		PlayoffTiebreakers string
+		ErrorMessage       string
+	}{
+		web.arena.EventSettings,
+		bracketDefinitions,
+		playoffRounds,
+		roundRobins,
+		game.FormatPlayoffTiebreakers(web.arena.EventSettings.PlayoffTiebreakers),
+		errorMessage,
+	}
 	err = template.ExecuteTemplate(w, "base", data)
 	if err != nil {
 		handleWebErr(w, err)
 		return
 	}
 }
+
+// Returns the series length settings for each round of the given bracket format, applying the given overrides. The
+// format's own default is always offered even if it isn't one of the standard options.
+// Returns the set of playoff rounds having at least one completed match.
+func (web *Web) getPlayedPlayoffRounds() (map[int]bool, error) {
+	matches, err := web.arena.Database.GetMatchesByType("elimination")
+	if err != nil {
+		return nil, err
+	}
+	playedRounds := make(map[int]bool)
+	for _, match := range matches {
+		if match.IsComplete() {
+			playedRounds[match.ElimRound] = true
+		}
+	}
+	return playedRounds, nil
+}
+
+func getPlayoffRoundSettings(definition *bracket.BracketDefinition, elimRoundBestOf map[int]int) []playoffRoundSetting {
+	var settings []playoffRoundSetting
+	for _, round := range definition.Rounds() {
+		setting := playoffRoundSetting{PlayoffRound: round, Options: bracket.BestOfOptions, BestOf: round.DefaultBestOf}
+		if !slices.Contains(setting.Options, round.DefaultBestOf) {
+			setting.Options = append(slices.Clone(setting.Options), round.DefaultBestOf)
+			slices.Sort(setting.Options)
+		}
+		if bestOf, ok := elimRoundBestOf[round.Round]; ok {
+			setting.BestOf = bestOf
+		}
+		settings = append(settings, setting)
+	}
+	return settings
+}
@@ -5,6 +5,7 @@ package web
 
 import (
 	"bytes"
+	"github.com/FRCTeam1987/crimson-arena/bracket"
 	"github.com/FRCTeam1987/crimson-arena/game"
 	"github.com/FRCTeam1987/crimson-arena/model"
 	"github.com/FRCTeam1987/crimson-arena/tournament"
@@ -13,6 +14,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -43,17 +45,249 @@ func TestSetupSettingsDoubleElimination(t *testing.T) {
 	web := setupTestWeb(t)
 
 	recorder := web.postHttpResponse("/setup/settings", "elimType=double&numElimAlliances=3")
+	assert.Contains(t, recorder.Body.String(), "Number of alliances must be between 4 and 8.")
+
+	recorder = web.postHttpResponse("/setup/settings", "elimType=double&numElimAlliances=6")
 	assert.Equal(t, 303, recorder.Code)
 	assert.Equal(t, "double", web.arena.EventSettings.ElimType)
+	assert.Equal(t, 6, web.arena.EventSettings.NumElimAlliances)
+	assert.Equal(t, 10, len(web.arena.PlayoffBracket.GetAllMatchups()))
+
+	recorder = web.postHttpResponse("/setup/settings", "elimType=double&numElimAlliances=8")
+	assert.Equal(t, 303, recorder.Code)
 	assert.Equal(t, 8, web.arena.EventSettings.NumElimAlliances)
+
+	// Check that the playoff types are offered from the bracket definitions with the current one selected.
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "Single-Elimination (2-16 alliances)")
+	assert.Contains(t, recorder.Body.String(), "Double-Elimination (4-8 alliances)")
+	assert.Regexp(t, `value="double"[^>]*\s+selected>`, recorder.Body.String())
+	assert.NotRegexp(t, `value="single"[^>]*\s+selected>`, recorder.Body.String())
+}
+
+func TestSetupSettingsRoundRobin(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "elimType=round_robin&numElimAlliances=5")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "round_robin", web.arena.EventSettings.ElimType)
+	if assert.NotNil(t, web.arena.PlayoffBracket.RoundRobin) {
+		// Five alliances playing three matches each leaves one alliance a match short.
+		assert.Equal(t, 7, len(web.arena.PlayoffBracket.RoundRobin.Matchups()))
+	}
+	assert.Equal(t, 8, len(web.arena.PlayoffBracket.GetAllMatchups()))
+
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "Round-Robin (3-8 alliances)")
+}
+
+func TestSetupSettingsRoundRobinOptions(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "elimType=round_robin&numElimAlliances=4&"+
+		"roundRobinMatchesPerAlliance_round_robin=6&roundRobinRankBy_round_robin=rankingPoints")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 6, web.arena.EventSettings.ElimRoundRobinMatchesPerAlliance)
+	assert.Equal(t, bracket.RoundRobinRankByRankingPoints, web.arena.EventSettings.ElimRoundRobinRankBy)
+	if assert.NotNil(t, web.arena.PlayoffBracket.RoundRobin) {
+		assert.Equal(t, 12, len(web.arena.PlayoffBracket.RoundRobin.Matchups()))
+		assert.Equal(t, bracket.RoundRobinRankByRankingPoints, web.arena.PlayoffBracket.RoundRobin.RankBy)
+	}
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Regexp(t, `name="roundRobinMatchesPerAlliance_round_robin"\s*value="6"`, recorder.Body.String())
+	assert.Regexp(t, `<option value="rankingPoints" selected>`, recorder.Body.String())
+
+	// Values matching the playoff type's defaults aren't stored as overrides.
+	recorder = web.postHttpResponse("/setup/settings", "elimType=round_robin&numElimAlliances=4&"+
+		"roundRobinMatchesPerAlliance_round_robin=3&roundRobinRankBy_round_robin=averageScore")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 0, web.arena.EventSettings.ElimRoundRobinMatchesPerAlliance)
+	assert.Equal(t, "", web.arena.EventSettings.ElimRoundRobinRankBy)
+	assert.Equal(t, 6, len(web.arena.PlayoffBracket.RoundRobin.Matchups()))
+
+	for _, postData := range []string{
+		"roundRobinMatchesPerAlliance_round_robin=0",
+		"roundRobinMatchesPerAlliance_round_robin=21",
+		"roundRobinMatchesPerAlliance_round_robin=abc",
+		"roundRobinRankBy_round_robin=wins",
+	} {
+		recorder = web.postHttpResponse("/setup/settings", "elimType=round_robin&numElimAlliances=4&"+postData)
+		assert.Contains(t, recorder.Body.String(), "Invalid round robin settings", postData)
+	}
+
+	// The round robin can't be changed once it is under way.
+	match := model.Match{Type: "elimination", DisplayName: "1", ElimRound: bracket.RoundRobinRound, ElimGroup: 1,
+		ElimInstance: 1, Status: game.RedWonMatch}
+	assert.Nil(t, web.arena.Database.CreateMatch(&match))
+	recorder = web.postHttpResponse("/setup/settings", "elimType=round_robin&numElimAlliances=4&"+
+		"roundRobinMatchesPerAlliance_round_robin=4")
+	assert.Contains(t, recorder.Body.String(), "Can't change the round robin settings once any of its matches have "+
+		"been played.")
+	recorder = web.postHttpResponse("/setup/settings", "elimType=round_robin&numElimAlliances=4&"+
+		"roundRobinRankBy_round_robin=rankingPoints")
+	assert.Contains(t, recorder.Body.String(), "Can't change the round robin settings")
+	recorder = web.postHttpResponse("/setup/settings", "elimType=round_robin&numElimAlliances=4&"+
+		"roundRobinMatchesPerAlliance_round_robin=3")
+	assert.Equal(t, 303, recorder.Code)
+}
+
+func TestSetupSettingsRoundBestOf(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse(
+		"/setup/settings", "elimType=single&numElimAlliances=8&bestOf_single_2=1&bestOf_single_3=3&bestOf_single_4=5",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, map[int]int{2: 1, 4: 5}, web.arena.EventSettings.ElimRoundBestOf)
+	assert.Equal(t, 5, web.arena.PlayoffBracket.FinalsMatchup.BestOf())
+	quarterfinal, _ := web.arena.PlayoffBracket.GetMatchup(2, 1)
+	assert.Equal(t, 1, quarterfinal.BestOf())
+
+	// The overrides survive a reload of the settings.
+	assert.Nil(t, web.arena.LoadSettings())
+	assert.Equal(t, 5, web.arena.PlayoffBracket.FinalsMatchup.BestOf())
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Regexp(t, `name="bestOf_single_4">\s*<option value="1" >\s*Best-of-1\s*</option>\s*`+
+		`<option value="3" >\s*Best-of-3 \(default\)\s*</option>\s*<option value="5" selected>`, recorder.Body.String())
+
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&bestOf_single_4=7")
+	assert.Contains(t, recorder.Body.String(), "Invalid series length for Finals: 7.")
+
+	// Settings for rounds of other playoff types are ignored.
+	recorder = web.postHttpResponse("/setup/settings", "elimType=double&numElimAlliances=8&bestOf_single_4=1")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Empty(t, web.arena.EventSettings.ElimRoundBestOf)
+	assert.Equal(t, 3, web.arena.PlayoffBracket.FinalsMatchup.BestOf())
+}
+
+func TestSetupSettingsRoundBestOfAfterPlay(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&bestOf_single_3=1")
+	assert.Equal(t, 303, recorder.Code)
+	match := model.Match{Type: "elimination", DisplayName: "SF1", ElimRound: 3, ElimGroup: 1, ElimInstance: 1,
+		Status: game.TieMatch}
+	assert.Nil(t, web.arena.Database.CreateMatch(&match))
+
+	// A round that has been played can't have its series length changed, whether shortened or lengthened.
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&bestOf_single_3=3")
+	assert.Contains(t, recorder.Body.String(), "Can't change the series length for Round 3 once any of its matches "+
+		"have been played.")
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8")
+	assert.Contains(t, recorder.Body.String(), "Can't change the series length for Round 3")
+	assert.Equal(t, map[int]int{3: 1}, web.arena.EventSettings.ElimRoundBestOf)
+
+	// Other rounds can still be changed as long as the played round keeps its length.
+	recorder = web.postHttpResponse(
+		"/setup/settings", "elimType=single&numElimAlliances=8&bestOf_single_3=1&bestOf_single_4=5",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, map[int]int{3: 1, 4: 5}, web.arena.EventSettings.ElimRoundBestOf)
 }
 
 func TestSetupSettingsInvalidValues(t *testing.T) {
 	web := setupTestWeb(t)
 
 	// Invalid number of alliances.
-	recorder := web.postHttpResponse("/setup/settings", "numAlliances=1")
+	recorder := web.postHttpResponse("/setup/settings", "elimType=single&numAlliances=1")
 	assert.Contains(t, recorder.Body.String(), "must be between 2 and 16")
+
+	// Nonexistent playoff type.
+	recorder = web.postHttpResponse("/setup/settings", "elimType=triple&numElimAlliances=8")
+	assert.Contains(t, recorder.Body.String(), "invalid playoff type: triple")
+	recorder = web.postHttpResponse("/setup/settings", "elimType=../single&numElimAlliances=8")
+	assert.Contains(t, recorder.Body.String(), "invalid playoff type: ../single")
+}
+
+func TestSetupSettingsRetiming(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse(
+		"/setup/settings", "elimType=single&numElimAlliances=8&retimeCycleTimeSec=420&autoRetimeEnabled=on",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 420, web.arena.EventSettings.RetimeCycleTimeSec)
+	assert.True(t, web.arena.EventSettings.AutoRetimeEnabled)
+
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&retimeCycleTimeSec=-5")
+	assert.Contains(t, recorder.Body.String(), "Re-timing cycle time cannot be negative.")
+	assert.Equal(t, 420, web.arena.EventSettings.RetimeCycleTimeSec)
+}
+
+func TestSetupSettingsElimRest(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse(
+		"/setup/settings", "elimType=single&numElimAlliances=8&elimMinRestSec=480&elimRestEnforced=on",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 480, web.arena.EventSettings.ElimMinRestSec)
+	assert.True(t, web.arena.EventSettings.ElimRestEnforced)
+
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&elimMinRestSec=-1")
+	assert.Contains(t, recorder.Body.String(), "Minimum playoff rest time cannot be negative.")
+	assert.Equal(t, 480, web.arena.EventSettings.ElimMinRestSec)
+}
+
+func TestSetupSettingsElimLineupCutoff(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&elimLineupCutoffSec=300")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 300, web.arena.EventSettings.ElimLineupCutoffSec)
+
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&elimLineupCutoffSec=-5")
+	assert.Contains(t, recorder.Body.String(), "Captain lineup cutoff cannot be negative.")
+	assert.Equal(t, 300, web.arena.EventSettings.ElimLineupCutoffSec)
+}
+
+func TestSetupSettingsRankingRules(t *testing.T) {
+	web := setupTestWeb(t)
+	defer func() { game.CurrentRankingRules = game.DefaultRankingRules() }()
+
+	recorder := web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&winRankingPoints=3&"+
+		"tieRankingPoints=1&lossRankingPoints=0&tiebreakers=rankingPoints,wins,teleopPoints&"+
+		"bonusRankingPoints=Auto Bonus, autoPoints, 20, 1")
+	assert.Equal(t, 303, recorder.Code)
+	rules := web.arena.EventSettings.RankingRules
+	assert.Equal(t, 3, rules.WinRankingPoints)
+	assert.Equal(t, []game.Tiebreaker{game.RankingPointsTiebreaker, game.WinsTiebreaker, game.TeleopPointsTiebreaker},
+		rules.Tiebreakers)
+	assert.Equal(
+		t,
+		[]game.BonusRankingPoint{{Name: "Auto Bonus", Component: "autoPoints", Threshold: 20, RankingPoints: 1}},
+		rules.BonusRankingPoints,
+	)
+	assert.Equal(t, rules, game.CurrentRankingRules)
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "rankingPoints, wins, teleopPoints")
+	assert.Contains(t, recorder.Body.String(), "Auto Bonus, autoPoints, 20, 1")
+
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&tiebreakers=wins,fouls")
+	assert.Contains(t, recorder.Body.String(), "Invalid ranking rules: invalid tiebreaker \"fouls\"")
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&"+
+		"bonusRankingPoints=Bonus, notes, 5, 1")
+	assert.Contains(t, recorder.Body.String(), "has invalid score component")
+}
+
+func TestSetupSettingsPlayoffTiebreakers(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "fewerFoulPoints, autoPoints, endgamePoints")
+
+	recorder = web.postHttpResponse(
+		"/setup/settings", "elimType=single&numElimAlliances=8&playoffTiebreakers=teleop, fewerFoulPoints",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, []game.PlayoffTiebreaker{"teleop", "fewerFoulPoints"}, web.arena.EventSettings.PlayoffTiebreakers)
+
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&playoffTiebreakers=")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, []game.PlayoffTiebreaker{}, web.arena.EventSettings.PlayoffTiebreakers)
+
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&playoffTiebreakers=fouls")
+	assert.Contains(t, recorder.Body.String(), "Invalid playoff tiebreakers: invalid playoff tiebreaker \"fouls\"")
 }
 
 func TestSetupSettingsClearDb(t *testing.T) {
@@ -77,7 +311,7 @@ func TestSetupSettingsClearDb(t *testing.T) {
 	assert.Empty(t, rankings)
 	alliances, _ := web.arena.Database.GetAllAlliances()
 	assert.Empty(t, alliances)
-	assert.Empty(t, web.arena.AllianceSelectionAlliances)
+	assert.Empty(t, web.arena.AllianceSelection.Alliances)
 }
 
 func TestSetupSettingsBackupRestoreDb(t *testing.T) {
@@ -113,6 +347,39 @@ func TestSetupSettingsBackupRestoreDb(t *testing.T) {
 	assert.Equal(t, "Chezy Champs", web.arena.EventSettings.Name)
 }
 
+func TestSetupSettingsGameDefinition(t *testing.T) {
+	web := setupTestWeb(t)
+	defer func() { game.CurrentGame = game.DefaultGameDefinition() }()
+
+	recorder := web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "Generic")
+
+	recorder = web.postHttpResponse("/setup/settings/game_definition", "")
+	assert.Contains(t, recorder.Body.String(), "No game definition file was specified")
+
+	recorder = web.postFileHttpResponse("/setup/settings/game_definition", "gameDefinitionFile",
+		bytes.NewBufferString("name: Broken"))
+	assert.Contains(t, recorder.Body.String(), "Invalid game definition")
+	assert.Equal(t, "Generic", web.arena.EventSettings.GameDefinition.Name)
+
+	recorder = web.postFileHttpResponse("/setup/settings/game_definition", "gameDefinitionFile",
+		bytes.NewBufferString("name: Test Game\nscoringElements:\n  - {id: note, name: Note, period: teleop, points: 5}\n"))
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "Test Game", web.arena.EventSettings.GameDefinition.Name)
+	assert.Equal(t, web.arena.EventSettings.GameDefinition, game.CurrentGame)
+	eventSettings, _ := web.arena.Database.GetEventSettings()
+	assert.Equal(t, "Test Game", eventSettings.GameDefinition.Name)
+	recorder = web.getHttpResponse("/setup/settings")
+	assert.Contains(t, recorder.Body.String(), "Note")
+
+	// The definition can't be changed once match results have been recorded.
+	assert.Nil(t, web.arena.Database.CreateMatchResult(model.BuildTestMatchResult(1, 1)))
+	recorder = web.postFileHttpResponse("/setup/settings/game_definition", "gameDefinitionFile",
+		bytes.NewBufferString("name: Other Game\nscoringElements:\n  - {id: cube, name: Cube, period: auto, points: 3}\n"))
+	assert.Contains(t, recorder.Body.String(), "once match results have been recorded")
+	assert.Equal(t, "Test Game", web.arena.EventSettings.GameDefinition.Name)
+}
+
 func (web *Web) postFileHttpResponse(path string, paramName string, file *bytes.Buffer) *httptest.ResponseRecorder {
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
@@ -125,3 +392,35 @@ func (web *Web) postFileHttpResponse(path string, paramName string, file *bytes.
 	web.newHandler().ServeHTTP(recorder, req)
 	return recorder
 }
+
+func TestSetupSettingsSelectionPickTime(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&selectionPickTimeSec=120")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, 120, web.arena.EventSettings.SelectionPickTimeSec)
+
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&selectionPickTimeSec=-1")
+	assert.Contains(t, recorder.Body.String(), "Alliance selection pick time cannot be negative.")
+	assert.Equal(t, 120, web.arena.EventSettings.SelectionPickTimeSec)
+}
+
+func TestSetupSettingsGameSpecificData(t *testing.T) {
+	web := setupTestWeb(t)
+
+	recorder := web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&"+
+		"gameSpecificDataOptions=LRL,RLR&gameSpecificDataPerAlliance=on&gameSpecificDataRevealSec=5")
+	assert.Equal(t, 303, recorder.Code)
+	assert.Equal(t, "LRL,RLR", web.arena.EventSettings.GameSpecificDataOptions)
+	assert.True(t, web.arena.EventSettings.GameSpecificDataPerAlliance)
+	assert.Equal(t, 5, web.arena.EventSettings.GameSpecificDataRevealSec)
+
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&"+
+		"gameSpecificDataRevealSec=-1")
+	assert.Contains(t, recorder.Body.String(), "Game-specific message reveal time cannot be negative.")
+	recorder = web.postHttpResponse("/setup/settings", "elimType=single&numElimAlliances=8&"+
+		"gameSpecificDataOptions=L,"+strings.Repeat("R", 65))
+	assert.Contains(t, recorder.Body.String(), "is longer than 64 characters.")
+	assert.Equal(t, "LRL,RLR", web.arena.EventSettings.GameSpecificDataOptions)
+	assert.Equal(t, 5, web.arena.EventSettings.GameSpecificDataRevealSec)
+}
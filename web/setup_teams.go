@@ -17,7 +17,10 @@ import (
 	"time"
 )
 
-const wpaKeyLength = 8
+const (
+	wpaKeyLength           = 8
+	availabilityTimeFormat = "2006-01-02T15:04"
+)
 
 // Shows the team list.
 func (web *Web) teamsGetHandler(w http.ResponseWriter, r *http.Request) {
@@ -39,23 +42,8 @@ func (web *Web) teamsPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var teamNumbers []int
-	for _, teamNumberString := range strings.Split(r.PostFormValue("teamNumbers"), "\r\n") {
-		teamNumber, err := strconv.Atoi(teamNumberString)
-		if err == nil {
-			teamNumbers = append(teamNumbers, teamNumber)
-		}
-	}
-
-	for _, teamNumber := range teamNumbers {
-		team := model.Team{Id: teamNumber}
-		if web.arena.EventSettings.TBADownloadEnabled {
-			if err := web.populateOfficialTeamInfo(&team); err != nil {
-				handleWebErr(w, err)
-				return
-			}
-		}
-		if err := web.arena.Database.CreateTeam(&team); err != nil {
+	for _, teamNumber := range parseTeamNumbers(r.PostFormValue("teamNumbers")) {
+		if err := web.createTeam(teamNumber); err != nil {
 			handleWebErr(w, err)
 			return
 		}
@@ -176,6 +164,19 @@ func (web *Web) teamEditPostHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	team.HasConnected = r.PostFormValue("hasConnected") == "on"
+	team.Withdrawn = r.PostFormValue("withdrawn") == "on"
+	if team.AvailableFrom, err = parseAvailabilityTime(r.PostFormValue("availableFrom")); err != nil {
+		handleWebErr(w, fmt.Errorf("Invalid availability start time: %s", r.PostFormValue("availableFrom")))
+		return
+	}
+	if team.AvailableUntil, err = parseAvailabilityTime(r.PostFormValue("availableUntil")); err != nil {
+		handleWebErr(w, fmt.Errorf("Invalid availability end time: %s", r.PostFormValue("availableUntil")))
+		return
+	}
+	if !team.AvailableFrom.IsZero() && !team.AvailableUntil.IsZero() && team.AvailableUntil.Before(team.AvailableFrom) {
+		handleWebErr(w, fmt.Errorf("Team cannot become unavailable before it becomes available."))
+		return
+	}
 	err = web.arena.Database.UpdateTeam(team)
 	if err != nil {
 		handleWebErr(w, err)
@@ -184,6 +185,14 @@ func (web *Web) teamEditPostHandler(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/setup/teams", 303)
 }
 
+// Parses a time from a datetime-local form field in the local time zone, treating a blank value as no constraint.
+func parseAvailabilityTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(availabilityTimeFormat, value, time.Local)
+}
+
 // Removes a team from the team list.
 func (web *Web) teamDeletePostHandler(w http.ResponseWriter, r *http.Request) {
 	if !web.userIsAdmin(w, r) {
@@ -278,6 +287,29 @@ func (web *Web) renderTeams(w http.ResponseWriter, r *http.Request, showErrorMes
 	}
 }
 
+// Adds the given team to the team list, downloading its details from TBA if enabled.
+func (web *Web) createTeam(teamNumber int) error {
+	team := model.Team{Id: teamNumber}
+	if web.arena.EventSettings.TBADownloadEnabled {
+		if err := web.populateOfficialTeamInfo(&team); err != nil {
+			return err
+		}
+	}
+	return web.arena.Database.CreateTeam(&team)
+}
+
+// Parses a newline-separated list of team numbers, ignoring any lines that aren't numbers.
+func parseTeamNumbers(value string) []int {
+	var teamNumbers []int
+	for _, teamNumberString := range strings.Split(value, "\r\n") {
+		teamNumber, err := strconv.Atoi(teamNumberString)
+		if err == nil {
+			teamNumbers = append(teamNumbers, teamNumber)
+		}
+	}
+	return teamNumbers
+}
+
 // Returns true if it is safe to change the team list (i.e. no matches/results exist yet).
 func (web *Web) canModifyTeamList() bool {
 	matches, err := web.arena.Database.GetMatchesByType("qualification")
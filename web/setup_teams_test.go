@@ -11,6 +11,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestSetupTeams(t *testing.T) {
@@ -206,6 +207,37 @@ func TestSetupTeamsWpaKeys(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "WPA key must be between 8 and 63 characters")
 }
 
+func TestSetupTeamsAvailability(t *testing.T) {
+	web := setupTestWeb(t)
+
+	web.arena.Database.CreateTeam(&model.Team{Id: 254})
+	recorder := web.postHttpResponse(
+		"/setup/teams/254/edit", "availableFrom=2026-03-14T10:30&availableUntil=2026-03-15T15:00",
+	)
+	assert.Equal(t, 303, recorder.Code)
+	team, _ := web.arena.Database.GetTeamById(254)
+	assert.Equal(t, time.Date(2026, 3, 14, 10, 30, 0, 0, time.Local).Unix(), team.AvailableFrom.Unix())
+	assert.Equal(t, time.Date(2026, 3, 15, 15, 0, 0, 0, time.Local).Unix(), team.AvailableUntil.Unix())
+	recorder = web.getHttpResponse("/setup/teams/254/edit")
+	assert.Contains(t, recorder.Body.String(), "value=\"2026-03-14T10:30\"")
+	assert.Contains(t, recorder.Body.String(), "value=\"2026-03-15T15:00\"")
+
+	// Clearing the fields removes the constraints.
+	recorder = web.postHttpResponse("/setup/teams/254/edit", "availableFrom=&availableUntil=")
+	assert.Equal(t, 303, recorder.Code)
+	team, _ = web.arena.Database.GetTeamById(254)
+	assert.False(t, team.HasAvailabilityWindow())
+
+	recorder = web.postHttpResponse("/setup/teams/254/edit", "availableFrom=noon")
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Invalid availability start time: noon")
+	recorder = web.postHttpResponse(
+		"/setup/teams/254/edit", "availableFrom=2026-03-15T10:30&availableUntil=2026-03-14T15:00",
+	)
+	assert.Equal(t, 500, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Team cannot become unavailable before it becomes available.")
+}
+
 func TestSetupTeamsPublish(t *testing.T) {
 	web := setupTestWeb(t)
 
@@ -25,10 +25,11 @@ const (
 type Web struct {
 	arena           *field.Arena
 	templateHelpers template.FuncMap
+	lineupPinGuard  *lineupPinGuard
 }
 
 func NewWeb(arena *field.Arena) *Web {
-	web := &Web{arena: arena}
+	web := &Web{arena: arena, lineupPinGuard: newLineupPinGuard()}
 
 	// Helper functions that can be used inside templates.
 	web.templateHelpers = template.FuncMap{
@@ -113,9 +114,14 @@ func (web *Web) newHandler() http.Handler {
 	router.HandleFunc("/alliance_selection/publish", web.allianceSelectionPublishHandler).Methods("POST")
 	router.HandleFunc("/alliance_selection/reset", web.allianceSelectionResetHandler).Methods("POST")
 	router.HandleFunc("/alliance_selection/start", web.allianceSelectionStartHandler).Methods("POST")
+	router.HandleFunc("/alliance_selection/websocket", web.allianceSelectionWebsocketHandler).Methods("GET")
+	router.HandleFunc("/alliances/lineups", web.allianceLineupsGetHandler).Methods("GET")
+	router.HandleFunc("/alliances/{allianceId}/lineup", web.allianceLineupGetHandler).Methods("GET")
+	router.HandleFunc("/alliances/{allianceId}/lineup", web.allianceLineupPostHandler).Methods("POST")
 	router.HandleFunc("/api/alliances", web.alliancesApiHandler).Methods("GET")
 	router.HandleFunc("/api/arena/websocket", web.arenaWebsocketApiHandler).Methods("GET")
 	router.HandleFunc("/api/bracket/svg", web.bracketSvgApiHandler).Methods("GET")
+	router.HandleFunc("/api/logs/{filename}", web.logApiHandler).Methods("GET")
 	router.HandleFunc("/api/matches/{type}", web.matchesApiHandler).Methods("GET")
 	router.HandleFunc("/api/rankings", web.rankingsApiHandler).Methods("GET")
 	router.HandleFunc("/api/scores", web.getScoresHandler).Methods("GET")
@@ -143,6 +149,8 @@ func (web *Web) newHandler() http.Handler {
 	router.HandleFunc("/displays/twitch/websocket", web.twitchDisplayWebsocketHandler).Methods("GET")
 	router.HandleFunc("/login", web.loginHandler).Methods("GET")
 	router.HandleFunc("/login", web.loginPostHandler).Methods("POST")
+	router.HandleFunc("/logs", web.logsGetHandler).Methods("GET")
+	router.HandleFunc("/logs/{filename}", web.logGetHandler).Methods("GET")
 	router.HandleFunc("/match_play", web.matchPlayHandler).Methods("GET")
 	router.HandleFunc("/match_play/{matchId}/load", web.matchPlayLoadHandler).Methods("GET")
 	router.HandleFunc("/match_play/{matchId}/show_result", web.matchPlayShowResultHandler).Methods("GET")
@@ -184,10 +192,14 @@ func (web *Web) newHandler() http.Handler {
 	router.HandleFunc("/setup/scc/websocket", web.sccGetTestingWebsocketHandler).Methods("GET")
 	router.HandleFunc("/setup/schedule", web.scheduleGetHandler).Methods("GET")
 	router.HandleFunc("/setup/schedule/generate", web.scheduleGeneratePostHandler).Methods("POST")
+	router.HandleFunc("/setup/schedule/import", web.scheduleImportPostHandler).Methods("POST")
 	router.HandleFunc("/setup/schedule/republish", web.scheduleRepublishPostHandler).Methods("POST")
+	router.HandleFunc("/setup/schedule/reschedule", web.scheduleReschedulePostHandler).Methods("POST")
 	router.HandleFunc("/setup/schedule/save", web.scheduleSavePostHandler).Methods("POST")
+	router.HandleFunc("/setup/schedule/select", web.scheduleSelectPostHandler).Methods("POST")
 	router.HandleFunc("/setup/settings", web.settingsGetHandler).Methods("GET")
 	router.HandleFunc("/setup/settings", web.settingsPostHandler).Methods("POST")
+	router.HandleFunc("/setup/settings/game_definition", web.gameDefinitionPostHandler).Methods("POST")
 	router.HandleFunc("/setup/sponsor_slides", web.sponsorSlidesGetHandler).Methods("GET")
 	router.HandleFunc("/setup/sponsor_slides", web.sponsorSlidesPostHandler).Methods("POST")
 	router.HandleFunc("/setup/teams", web.teamsGetHandler).Methods("GET")